@@ -0,0 +1,9 @@
+package main
+
+import (
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/dynamic"
+)
+
+func main() {
+	dynamic.Main()
+}
@@ -0,0 +1,167 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dynamic bridges an arbitrary Terraform provider binary at runtime. Rather than linking the provider into a
+// Go program and generating code ahead of time, the provider is launched over the Terraform plugin protocol, its
+// tokens are derived from its schema at startup, and its Pulumi schema is served from GetSchema.
+package dynamic
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfgen"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin6"
+)
+
+const (
+	// ProviderEnvVar names the environment variable that holds the path to the Terraform provider binary to bridge.
+	ProviderEnvVar = "PULUMI_TFBRIDGE_DYNAMIC_PROVIDER"
+	// ProviderInfoEnvVar names the environment variable that holds the path to an optional JSON-encoded
+	// tfbridge.MarshallableProviderInfo with overrides for the derived provider info.
+	ProviderInfoEnvVar = "PULUMI_TFBRIDGE_DYNAMIC_PROVIDER_INFO"
+)
+
+// terraformVersion is the Terraform version reported to dynamically bridged providers.
+const terraformVersion = "1.0.0"
+
+// defaultVersion is the version of the Pulumi provider if the provider info does not specify one.
+const defaultVersion = "0.0.1"
+
+// Main launches the Terraform provider binary named by ProviderEnvVar and serves it as a Pulumi provider.
+func Main() {
+	providerPath := os.Getenv(ProviderEnvVar)
+	if providerPath == "" {
+		cmdutil.ExitError(ProviderEnvVar + " must be set to the path of a Terraform provider binary")
+	}
+
+	info, err := LoadProviderInfo(context.Background(), providerPath, os.Getenv(ProviderInfoEnvVar))
+	if err != nil {
+		cmdutil.ExitError(err.Error())
+	}
+
+	// Only errors are reported: missing docs and the like are expected for dynamically bridged providers.
+	sink := diag.DefaultSink(ioutil.Discard, os.Stderr, diag.FormatOptions{Color: colors.Never})
+	spec, err := tfgen.GenerateSchemaWithOptions(tfgen.GeneratorOptions{
+		Package:      info.Name,
+		Version:      info.Version,
+		ProviderInfo: info,
+		Sink:         sink,
+		SkipDocs:     true,
+		SkipExamples: true,
+	})
+	if err != nil {
+		cmdutil.ExitError(err.Error())
+	}
+	pulumiSchema, err := json.Marshal(spec)
+	if err != nil {
+		cmdutil.ExitError(err.Error())
+	}
+
+	tfbridge.Main(info.Name, info.Version, info, pulumiSchema)
+}
+
+// LoadProviderInfo launches the Terraform provider binary at the given path and derives a ProviderInfo for it. If
+// infoPath is not empty, it names a JSON-encoded tfbridge.MarshallableProviderInfo whose name, version, config and
+// resource and data source mappings take precedence over the derived ones. Every resource and data source that is
+// not mapped explicitly is assigned a token in the package's index module.
+func LoadProviderInfo(ctx context.Context, providerPath, infoPath string) (tfbridge.ProviderInfo, error) {
+	// Providers built on terraform-plugin-framework only serve protocol 6, so fall back to it if the provider
+	// rejects protocol 5.
+	p, err := tfplugin5.StartProvider(ctx, providerPath, terraformVersion)
+	if err != nil {
+		p6, err6 := tfplugin6.StartProvider(ctx, providerPath, terraformVersion)
+		if err6 != nil {
+			return tfbridge.ProviderInfo{}, errors.Errorf("starting provider %q: with protocol 5: %v; with protocol 6: %v",
+				providerPath, err, err6)
+		}
+		p = p6
+	}
+
+	info := tfbridge.ProviderInfo{}
+	if infoPath != "" {
+		contents, err := ioutil.ReadFile(infoPath)
+		if err != nil {
+			return tfbridge.ProviderInfo{}, err
+		}
+		var m tfbridge.MarshallableProviderInfo
+		if err = json.Unmarshal(contents, &m); err != nil {
+			return tfbridge.ProviderInfo{}, errors.Wrapf(err, "decoding provider info %q", infoPath)
+		}
+		info = *m.Unmarshal()
+	}
+
+	info.P = p
+	if info.Name == "" {
+		info.Name = providerName(providerPath)
+	}
+	if info.Version == "" {
+		info.Version = defaultVersion
+	}
+	if info.Resources == nil {
+		info.Resources = map[string]*tfbridge.ResourceInfo{}
+	}
+	if info.DataSources == nil {
+		info.DataSources = map[string]*tfbridge.DataSourceInfo{}
+	}
+
+	p.ResourcesMap().Range(func(name string, _ shim.Resource) bool {
+		if r, ok := info.Resources[name]; !ok || r.Tok == "" {
+			tok := tfbridge.MakeResource(info.Name, "index",
+				tfbridge.TerraformToPulumiName(withoutPrefix(info.Name, name), nil, nil, true))
+			info.Resources[name] = &tfbridge.ResourceInfo{Tok: tok}
+		}
+		return true
+	})
+	p.DataSourcesMap().Range(func(name string, _ shim.Resource) bool {
+		if d, ok := info.DataSources[name]; !ok || d.Tok == "" {
+			tok := tfbridge.MakeDataSource(info.Name, "index",
+				"get"+tfbridge.TerraformToPulumiName(withoutPrefix(info.Name, name), nil, nil, true))
+			info.DataSources[name] = &tfbridge.DataSourceInfo{Tok: tok}
+		}
+		return true
+	})
+
+	return info, nil
+}
+
+// providerBinaryPattern matches the names of Terraform provider binaries, e.g. terraform-provider-aws_v3.0.0_x5.
+var providerBinaryPattern = regexp.MustCompile(`^terraform-provider-([^_.]+)`)
+
+// providerName derives a provider's name from the name of its binary.
+func providerName(providerPath string) string {
+	base := filepath.Base(providerPath)
+	if m := providerBinaryPattern.FindStringSubmatch(base); m != nil {
+		return m[1]
+	}
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// withoutPrefix strips the provider's name prefix, if any, from the name of a resource or data source.
+func withoutPrefix(provider, name string) string {
+	return strings.TrimPrefix(name, provider+"_")
+}
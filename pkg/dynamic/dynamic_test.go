@@ -0,0 +1,71 @@
+package dynamic
+
+import (
+	"context"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfgen"
+)
+
+func TestProviderName(t *testing.T) {
+	assert.Equal(t, "aws", providerName("/plugins/terraform-provider-aws_v3.74.0_x5"))
+	assert.Equal(t, "random", providerName("terraform-provider-random"))
+	assert.Equal(t, "custom", providerName("custom.exe"))
+}
+
+func TestLoadProviderInfoReportsBothProtocols(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "terraform-provider-missing")
+	_, err := LoadProviderInfo(context.Background(), missing, "")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "with protocol 5: ")
+		assert.Contains(t, err.Error(), "with protocol 6: ")
+	}
+}
+
+func TestLoadProviderInfo(t *testing.T) {
+	testProviderPath, err := exec.LookPath("pulumi-terraform-bridge-test-provider")
+	if err != nil {
+		t.Skip("pulumi-terraform-bridge-test-provider is not on PATH")
+	}
+
+	infoPath := filepath.Join(t.TempDir(), "info.json")
+	err = ioutil.WriteFile(infoPath, []byte(`{
+		"name": "example",
+		"version": "1.2.3",
+		"resources": {"second_resource": {"tok": "example:custom/second:Second"}}
+	}`), 0600)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	info, err := LoadProviderInfo(ctx, testProviderPath, infoPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, "example", info.Name)
+	assert.Equal(t, "1.2.3", info.Version)
+	assert.Equal(t, tokens.Type("example:index/resource:Resource"), info.Resources["example_resource"].Tok)
+	assert.Equal(t, tokens.Type("example:custom/second:Second"), info.Resources["second_resource"].Tok)
+	assert.Equal(t, tokens.Type("example:index/nestedSecretResource:NestedSecretResource"),
+		info.Resources["nested_secret_resource"].Tok)
+	assert.Equal(t, tokens.ModuleMember("example:index/getResource:getResource"),
+		info.DataSources["example_resource"].Tok)
+
+	spec, err := tfgen.GenerateSchemaWithOptions(tfgen.GeneratorOptions{
+		Package:      info.Name,
+		Version:      info.Version,
+		ProviderInfo: info,
+		SkipDocs:     true,
+		SkipExamples: true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, spec.Resources, "example:index/resource:Resource")
+	assert.Contains(t, spec.Resources, "example:custom/second:Second")
+	assert.Contains(t, spec.Functions, "example:index/getResource:getResource")
+}
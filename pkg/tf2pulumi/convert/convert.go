@@ -19,6 +19,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"sync"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/spf13/afero"
@@ -47,6 +48,11 @@ var (
 	ValidLanguages = [...]string{LanguageTypescript, LanguagePulumi, LanguagePython, LanguageCSharp, LanguageGo}
 )
 
+// programGenMutex serializes the language program generators, which are not safe for concurrent use: they import
+// language-specific metadata into the package schemas shared through Options.PackageCache and update package-level
+// state. Parsing and binding, which make up most of a conversion, still run concurrently.
+var programGenMutex sync.Mutex
+
 type Diagnostics struct {
 	All   hcl.Diagnostics
 	files []*syntax.File
@@ -115,6 +121,9 @@ func Convert(opts Options) (map[string][]byte, Diagnostics, error) {
 		return nil, Diagnostics{All: diagnostics, files: tf12Files}, nil
 	}
 
+	programGenMutex.Lock()
+	defer programGenMutex.Unlock()
+
 	switch opts.TargetLanguage {
 	case LanguageTypescript:
 		tsFiles, genDiags, _ := hcl2nodejs.GenerateProgram(program)
@@ -1751,10 +1751,7 @@ func (b *tf12binder) resourceType(addr addrs.Resource,
 		schemas.TFRes = info.P.DataSourcesMap().Get(addr.Type)
 		schemas.Pulumi = schemaInfo
 	}
-	if schemas.TFRes == nil {
-		schemas.TFRes = (&schema.Resource{Schema: schema.SchemaMap{}}).Shim()
-	}
-	schemas.TFRes.Schema().Set("id", (&schema.Schema{Type: shim.TypeString, Computed: true}).Shim())
+	schemas.TFRes = il.WithComputedID(schemas.TFRes)
 
 	return token, schemas, schemas.ModelType(), nil
 }
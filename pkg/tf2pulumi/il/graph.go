@@ -298,10 +298,9 @@ func (r *ResourceNode) Schemas() Schemas {
 		}
 		tf := r.Provider.Info.P.ResourcesMap().Get(r.Type)
 		if tf == nil {
-			tf = (&schema.Resource{Schema: schema.SchemaMap{}}).Shim()
-		}
-		if _, ok := tf.Schema().GetOk("id"); !ok {
-			tf.Schema().Set("id", (&schema.Schema{Type: shim.TypeString, Computed: true}).Shim())
+			tf = WithComputedID(nil)
+		} else if _, ok := tf.Schema().GetOk("id"); !ok {
+			tf = WithComputedID(tf)
 		}
 		return Schemas{
 			TFRes:  tf,
@@ -314,10 +313,9 @@ func (r *ResourceNode) Schemas() Schemas {
 		}
 		tf := r.Provider.Info.P.DataSourcesMap().Get(r.Type)
 		if tf == nil {
-			tf = (&schema.Resource{Schema: schema.SchemaMap{}}).Shim()
-		}
-		if _, ok := tf.Schema().GetOk("id"); !ok {
-			tf.Schema().Set("id", (&schema.Schema{Type: shim.TypeString, Computed: true}).Shim())
+			tf = WithComputedID(nil)
+		} else if _, ok := tf.Schema().GetOk("id"); !ok {
+			tf = WithComputedID(tf)
 		}
 		return Schemas{
 			TFRes:  tf,
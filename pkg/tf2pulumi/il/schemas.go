@@ -21,8 +21,38 @@ import (
 
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 )
 
+// WithComputedID returns a resource that is the given resource with a computed "id" property in place of any existing
+// one in its schema. The given resource is left untouched, as it belongs to the provider and may be shared by
+// concurrent conversions; every method but Schema is forwarded to it.
+func WithComputedID(res shim.Resource) shim.Resource {
+	properties := schema.SchemaMap{}
+	if res != nil && res.Schema() != nil {
+		res.Schema().Range(func(key string, value shim.Schema) bool {
+			properties[key] = value
+			return true
+		})
+	}
+	properties["id"] = (&schema.Schema{Type: shim.TypeString, Computed: true}).Shim()
+	if res == nil {
+		return (&schema.Resource{Schema: properties}).Shim()
+	}
+	return computedIDResource{Resource: res, schema: properties}
+}
+
+// computedIDResource overrides the schema of a resource.
+type computedIDResource struct {
+	shim.Resource
+
+	schema shim.SchemaMap
+}
+
+func (r computedIDResource) Schema() shim.SchemaMap {
+	return r.schema
+}
+
 // Schemas bundles a property's Terraform and Pulumi schema information into a single type. This information is then
 // used to determine type and name information for the property. If the Terraform property is of a composite type--a
 // map, list, or set--the property's schemas may also be used to access child schemas.
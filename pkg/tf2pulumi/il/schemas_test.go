@@ -0,0 +1,36 @@
+package il
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func TestWithComputedID(t *testing.T) {
+	res := (&schema.Resource{
+		Schema: schema.SchemaMap{
+			"name": (&schema.Schema{Type: shim.TypeString, Required: true}).Shim(),
+		},
+		SchemaVersion:      2,
+		DeprecationMessage: "use another resource",
+	}).Shim()
+
+	withID := WithComputedID(res)
+	id, ok := withID.Schema().GetOk("id")
+	assert.True(t, ok)
+	assert.True(t, id.Computed())
+	_, ok = withID.Schema().GetOk("name")
+	assert.True(t, ok)
+	assert.Equal(t, 2, withID.SchemaVersion())
+	assert.Equal(t, "use another resource", withID.DeprecationMessage())
+
+	// The original resource is left untouched.
+	_, ok = res.Schema().GetOk("id")
+	assert.False(t, ok)
+
+	_, ok = WithComputedID(nil).Schema().GetOk("id")
+	assert.True(t, ok)
+}
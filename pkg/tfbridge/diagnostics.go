@@ -0,0 +1,95 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"golang.org/x/net/context"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+)
+
+// attributePathFunc renders the attribute path of a diagnostic as a Pulumi property path.
+type attributePathFunc func(path cty.Path) string
+
+// resourceAttributePath renders attribute paths relative to the given resource.
+func resourceAttributePath(t tokens.Type, res Resource) attributePathFunc {
+	return func(path cty.Path) string {
+		return strings.Join(pathToAttributePath(path, t.Name().String(), res.TF.Schema(), res.Schema.Fields), "")
+	}
+}
+
+// dataSourceAttributePath renders attribute paths relative to the given data source.
+func dataSourceAttributePath(tok tokens.ModuleMember, ds DataSource) attributePathFunc {
+	return func(path cty.Path) string {
+		return strings.Join(pathToAttributePath(path, tok.Name().String(), ds.TF.Schema(), ds.Schema.Fields), "")
+	}
+}
+
+// formatDiagnostic renders a diagnostic reported by the Terraform provider for display. If the diagnostic has an
+// attribute path and attributePath is non-nil, the path is rendered in terms of Pulumi property names. A single-line
+// detail is appended to the summary; a multi-line detail is indented beneath it so that it stays legible.
+func formatDiagnostic(d diagnostics.Diagnostic, attributePath attributePathFunc) string {
+	var path string
+	if len(d.AttributePath) > 0 && attributePath != nil {
+		path = attributePath(d.AttributePath)
+	}
+
+	detail := strings.TrimRight(d.Detail, "\n")
+	multiline := strings.Contains(detail, "\n")
+
+	var sb strings.Builder
+	sb.WriteString(d.Summary)
+	if detail != "" && !multiline {
+		fmt.Fprintf(&sb, ": %s", detail)
+	}
+	if path != "" {
+		fmt.Fprintf(&sb, ". Examine values at '%s'.", path)
+	}
+	if multiline {
+		for _, line := range strings.Split(detail, "\n") {
+			sb.WriteString("\n")
+			if line != "" {
+				sb.WriteString("    " + line)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// logWarnings reports the warnings returned by the Terraform provider to the engine, if the provider is connected to
+// an engine, each prefixed with the given description of the operation that produced it.
+func (p *Provider) logWarnings(ctx context.Context, urn resource.URN, prefix string,
+	warnings diagnostics.Diagnostics, attributePath attributePathFunc) error {
+
+	for _, w := range warnings {
+		msg := fmt.Sprintf("%s: %s", prefix, formatDiagnostic(w, attributePath))
+		glog.V(9).Info(msg)
+		if p.host == nil {
+			continue
+		}
+		if err := p.host.Log(ctx, diag.Warning, urn, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
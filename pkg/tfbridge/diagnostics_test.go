@@ -0,0 +1,100 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+func TestFormatDiagnostic(t *testing.T) {
+	res := Resource{
+		TF: shimv2.NewResource(testTFProviderV2.ResourcesMap["example_resource"]),
+		Schema: &ResourceInfo{
+			Tok: tokens.NewTypeToken(tokens.NewModuleToken("module", "index"), "ExampleResource"),
+		},
+	}
+	attributePath := resourceAttributePath(res.Schema.Tok, res)
+
+	tests := []struct {
+		name     string
+		diag     diagnostics.Diagnostic
+		expected string
+	}{
+		{
+			name:     "summary only",
+			diag:     diagnostics.Diagnostic{Summary: "something happened"},
+			expected: "something happened",
+		},
+		{
+			name:     "single-line detail",
+			diag:     diagnostics.Diagnostic{Summary: "something happened", Detail: "it was bad"},
+			expected: "something happened: it was bad",
+		},
+		{
+			name: "attribute path",
+			diag: diagnostics.Diagnostic{
+				Summary:       "invalid value",
+				AttributePath: cty.GetAttrPath("string_property_value"),
+			},
+			expected: "invalid value. Examine values at 'ExampleResource.StringPropertyValue'.",
+		},
+		{
+			name: "multi-line detail",
+			diag: diagnostics.Diagnostic{
+				Severity:      diagnostics.Warning,
+				Summary:       "deprecated attribute",
+				Detail:        "This attribute is deprecated.\n\nUse another one instead.\n",
+				AttributePath: cty.GetAttrPath("array_property_value").Index(cty.NumberIntVal(1)),
+			},
+			expected: "deprecated attribute. Examine values at 'ExampleResource.ArrayPropertyValues[1]'.\n" +
+				"    This attribute is deprecated.\n" +
+				"\n" +
+				"    Use another one instead.",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatDiagnostic(tt.diag, attributePath))
+		})
+	}
+
+	// Without a way to render paths, the path is omitted.
+	assert.Equal(t, "invalid value", formatDiagnostic(diagnostics.Diagnostic{
+		Summary:       "invalid value",
+		AttributePath: cty.GetAttrPath("string_property_value"),
+	}, nil))
+
+	// Data sources without overlaid info still render their paths.
+	ds := DataSource{TF: (&schema.Resource{Schema: schema.SchemaMap{}}).Shim(), Schema: &DataSourceInfo{}}
+	assert.Equal(t, "ds.Foo", dataSourceAttributePath("module:index:ds", ds)(cty.GetAttrPath("foo")))
+}
+
+func TestLogWarningsWithoutHost(t *testing.T) {
+	p := &Provider{}
+	err := p.logWarnings(context.Background(), "", "test", diagnostics.Diagnostics{
+		{Severity: diagnostics.Warning, Summary: "careful"},
+	}, nil)
+	assert.NoError(t, err)
+}
@@ -56,7 +56,7 @@ func diffTest(t *testing.T, tfs map[string]*schema.Schema, info map[string]*Sche
 	config, _, err := MakeTerraformConfig(&Provider{tf: provider}, inputsMap, sch, info)
 	assert.NoError(t, err)
 
-	tfDiff, err := provider.Diff("resource", tfState, config)
+	tfDiff, _, err := provider.Diff("resource", tfState, config)
 	assert.NoError(t, err)
 
 	// ProcessIgnoreChanges
@@ -0,0 +1,562 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// GrpcLogEnvVar names the environment variable that, when set to a file path, causes Serve to record every
+// RPC the provider handles to that file as JSON lines. Secret values, sensitive config variables and the values of
+// resource and data source attributes that the Terraform schema marks as sensitive are redacted before they are
+// written.
+const GrpcLogEnvVar = "PULUMI_TFBRIDGE_GRPC_LOG"
+
+// redactedValue replaces every secret or sensitive value in a gRPC log.
+const redactedValue = "[redacted]"
+
+// GrpcLogEntry is a single recorded RPC. Requests and responses are stored in their protobuf JSON encoding.
+type GrpcLogEntry struct {
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// grpcLogger redacts and encodes RPCs to a JSON lines stream.
+type grpcLogger struct {
+	m         sync.Mutex
+	w         io.Writer
+	enc       *json.Encoder
+	p         *Provider       // the provider whose resource and data source schemas identify sensitive attributes.
+	sensitive map[string]bool // the Pulumi names of sensitive config variables.
+}
+
+func newGrpcLogger(w io.Writer, p *Provider) *grpcLogger {
+	return &grpcLogger{w: w, enc: json.NewEncoder(w), p: p, sensitive: sensitiveConfigNames(p)}
+}
+
+// sensitiveConfigNames returns the Pulumi names of all provider config variables marked as sensitive.
+func sensitiveConfigNames(p *Provider) map[string]bool {
+	names := map[string]bool{}
+	if p.config == nil {
+		return names
+	}
+	p.config.Range(func(key string, sch shim.Schema) bool {
+		if sch.Sensitive() {
+			names[TerraformToPulumiName(key, sch, p.info.Config[key], false)] = true
+		}
+		return true
+	})
+	return names
+}
+
+// encode marshals and redacts a request or response message. tfs and ps describe the properties of the resource or
+// data source that the message concerns, if any.
+func (l *grpcLogger) encode(method string, msg proto.Message, tfs shim.SchemaMap,
+	ps map[string]*SchemaInfo) (json.RawMessage, error) {
+
+	if msg == nil || reflect.ValueOf(msg).IsNil() {
+		return nil, nil
+	}
+
+	marshaler := jsonpb.Marshaler{OrigName: true}
+	s, err := marshaler.MarshalToString(msg)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err = json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, err
+	}
+	v = redactSecrets(v)
+	if isConfigMethod(method) {
+		v = l.redactConfig(v)
+	} else if msg, ok := v.(map[string]interface{}); ok && tfs != nil {
+		for _, field := range []string{"olds", "news", "inputs", "properties", "args", "return"} {
+			redactSensitive(msg[field], tfs, ps)
+		}
+	}
+	return json.Marshal(v)
+}
+
+// schemaFor returns the schema of the resource or data source that the given request concerns, if any.
+func (l *grpcLogger) schemaFor(req proto.Message) (shim.SchemaMap, map[string]*SchemaInfo) {
+	if l.p == nil {
+		return nil, nil
+	}
+	switch req := req.(type) {
+	case *pulumirpc.InvokeRequest:
+		if ds, ok := l.p.dataSources[tokens.ModuleMember(req.GetTok())]; ok && ds.TF != nil {
+			return ds.TF.Schema(), ds.Schema.Fields
+		}
+	case interface{ GetUrn() string }:
+		urn := resource.URN(req.GetUrn())
+		if !urn.IsValid() {
+			return nil, nil
+		}
+		if res, ok := l.p.resources[urn.Type()]; ok && res.TF != nil {
+			return res.TF.Schema(), res.Schema.Fields
+		}
+	}
+	return nil, nil
+}
+
+// record appends an entry for the given RPC to the log. Failures to record are reported but never fail the RPC.
+func (l *grpcLogger) record(method string, req, resp proto.Message, rpcErr error) {
+	entry, err := l.entry(method, req, resp, rpcErr)
+	if err == nil {
+		l.m.Lock()
+		err = l.enc.Encode(entry)
+		l.m.Unlock()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record %s RPC: %v\n", method, err)
+	}
+}
+
+func (l *grpcLogger) entry(method string, req, resp proto.Message, rpcErr error) (GrpcLogEntry, error) {
+	entry := GrpcLogEntry{Method: method}
+	tfs, ps := l.schemaFor(req)
+	var err error
+	if entry.Request, err = l.encode(method, req, tfs, ps); err != nil {
+		return entry, err
+	}
+	if rpcErr != nil {
+		entry.Error = rpcErr.Error()
+		return entry, nil
+	}
+	if entry.Response, err = l.encode(method, resp, tfs, ps); err != nil {
+		return entry, err
+	}
+	return entry, nil
+}
+
+func isConfigMethod(method string) bool {
+	return method == "CheckConfig" || method == "DiffConfig" || method == "Configure"
+}
+
+// redactSecrets replaces the underlying value of every secret in a JSON-decoded property bag.
+func redactSecrets(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if s, ok := v[resource.SigKey].(string); ok && s == resource.SecretSig {
+			return map[string]interface{}{resource.SigKey: resource.SecretSig, "value": redactedValue}
+		}
+		for k, e := range v {
+			v[k] = redactSecrets(e)
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = redactSecrets(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// redactSensitive replaces the values of the attributes that the Terraform schema marks as sensitive in a
+// JSON-decoded property bag, including those of nested blocks.
+func redactSensitive(v interface{}, tfs shim.SchemaMap, ps map[string]*SchemaInfo) {
+	bag, ok := v.(map[string]interface{})
+	if !ok || tfs == nil {
+		return
+	}
+	tfs.Range(func(key string, sch shim.Schema) bool {
+		name, _, info := getInfoFromTerraformName(key, tfs, ps, false)
+		value, has := bag[string(name)]
+		if !has {
+			return true
+		}
+		if sch.Sensitive() {
+			bag[string(name)] = redactedValue
+			return true
+		}
+		if elem, ok := sch.Elem().(shim.Resource); ok {
+			var fields map[string]*SchemaInfo
+			if info != nil && info.Elem != nil {
+				fields = info.Elem.Fields
+			}
+			// Blocks are lists of objects, or single objects if they are flattened by MaxItemsOne.
+			if values, ok := value.([]interface{}); ok {
+				for _, e := range values {
+					redactSensitive(e, elem.Schema(), fields)
+				}
+			} else {
+				redactSensitive(value, elem.Schema(), fields)
+			}
+		}
+		return true
+	})
+}
+
+// redactConfig replaces the values of sensitive config variables in a config-related request or response. Config
+// variables appear both as property bags keyed by name and as "variables" maps keyed by fully-qualified token.
+func (l *grpcLogger) redactConfig(v interface{}) interface{} {
+	msg, ok := v.(map[string]interface{})
+	if !ok || len(l.sensitive) == 0 {
+		return v
+	}
+	for _, field := range []string{"variables", "args", "olds", "news", "inputs"} {
+		bag, ok := msg[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for k := range bag {
+			name := k
+			if i := strings.LastIndex(k, ":"); i != -1 {
+				name = k[i+1:]
+			}
+			if l.sensitive[name] {
+				bag[k] = redactedValue
+			}
+		}
+	}
+	return msg
+}
+
+// grpcLogProvider wraps a resource provider server and records every RPC it handles.
+type grpcLogProvider struct {
+	server pulumirpc.ResourceProviderServer
+	log    *grpcLogger
+}
+
+var _ pulumirpc.ResourceProviderServer = (*grpcLogProvider)(nil)
+
+// NewGrpcLogProvider returns a resource provider server that forwards all RPCs to the given provider and records
+// them to w as JSON lines. The resulting log can be fed back to a provider with ReplayGrpcLog.
+func NewGrpcLogProvider(p *Provider, w io.Writer) pulumirpc.ResourceProviderServer {
+	return newGrpcLogProvider(p, p, w)
+}
+
+func newGrpcLogProvider(server pulumirpc.ResourceProviderServer, p *Provider, w io.Writer) *grpcLogProvider {
+	return &grpcLogProvider{server: server, log: newGrpcLogger(w, p)}
+}
+
+func (r *grpcLogProvider) GetSchema(ctx context.Context,
+	req *pulumirpc.GetSchemaRequest) (*pulumirpc.GetSchemaResponse, error) {
+	resp, err := r.server.GetSchema(ctx, req)
+	r.log.record("GetSchema", req, resp, err)
+	return resp, err
+}
+
+func (r *grpcLogProvider) CheckConfig(ctx context.Context,
+	req *pulumirpc.CheckRequest) (*pulumirpc.CheckResponse, error) {
+	resp, err := r.server.CheckConfig(ctx, req)
+	r.log.record("CheckConfig", req, resp, err)
+	return resp, err
+}
+
+func (r *grpcLogProvider) DiffConfig(ctx context.Context,
+	req *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
+	resp, err := r.server.DiffConfig(ctx, req)
+	r.log.record("DiffConfig", req, resp, err)
+	return resp, err
+}
+
+func (r *grpcLogProvider) Configure(ctx context.Context,
+	req *pulumirpc.ConfigureRequest) (*pulumirpc.ConfigureResponse, error) {
+	resp, err := r.server.Configure(ctx, req)
+	r.log.record("Configure", req, resp, err)
+	return resp, err
+}
+
+func (r *grpcLogProvider) Invoke(ctx context.Context,
+	req *pulumirpc.InvokeRequest) (*pulumirpc.InvokeResponse, error) {
+	resp, err := r.server.Invoke(ctx, req)
+	r.log.record("Invoke", req, resp, err)
+	return resp, err
+}
+
+// StreamInvoke is forwarded as-is; streaming RPCs are not recorded.
+func (r *grpcLogProvider) StreamInvoke(req *pulumirpc.InvokeRequest,
+	server pulumirpc.ResourceProvider_StreamInvokeServer) error {
+	return r.server.StreamInvoke(req, server)
+}
+
+func (r *grpcLogProvider) Call(ctx context.Context,
+	req *pulumirpc.CallRequest) (*pulumirpc.CallResponse, error) {
+	resp, err := r.server.Call(ctx, req)
+	r.log.record("Call", req, resp, err)
+	return resp, err
+}
+
+func (r *grpcLogProvider) Check(ctx context.Context,
+	req *pulumirpc.CheckRequest) (*pulumirpc.CheckResponse, error) {
+	resp, err := r.server.Check(ctx, req)
+	r.log.record("Check", req, resp, err)
+	return resp, err
+}
+
+func (r *grpcLogProvider) Diff(ctx context.Context,
+	req *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
+	resp, err := r.server.Diff(ctx, req)
+	r.log.record("Diff", req, resp, err)
+	return resp, err
+}
+
+func (r *grpcLogProvider) Create(ctx context.Context,
+	req *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
+	resp, err := r.server.Create(ctx, req)
+	r.log.record("Create", req, resp, err)
+	return resp, err
+}
+
+func (r *grpcLogProvider) Read(ctx context.Context,
+	req *pulumirpc.ReadRequest) (*pulumirpc.ReadResponse, error) {
+	resp, err := r.server.Read(ctx, req)
+	r.log.record("Read", req, resp, err)
+	return resp, err
+}
+
+func (r *grpcLogProvider) Update(ctx context.Context,
+	req *pulumirpc.UpdateRequest) (*pulumirpc.UpdateResponse, error) {
+	resp, err := r.server.Update(ctx, req)
+	r.log.record("Update", req, resp, err)
+	return resp, err
+}
+
+func (r *grpcLogProvider) Delete(ctx context.Context,
+	req *pulumirpc.DeleteRequest) (*pbempty.Empty, error) {
+	resp, err := r.server.Delete(ctx, req)
+	r.log.record("Delete", req, resp, err)
+	return resp, err
+}
+
+func (r *grpcLogProvider) Construct(ctx context.Context,
+	req *pulumirpc.ConstructRequest) (*pulumirpc.ConstructResponse, error) {
+	resp, err := r.server.Construct(ctx, req)
+	r.log.record("Construct", req, resp, err)
+	return resp, err
+}
+
+func (r *grpcLogProvider) Cancel(ctx context.Context, req *pbempty.Empty) (*pbempty.Empty, error) {
+	resp, err := r.server.Cancel(ctx, req)
+	r.log.record("Cancel", req, resp, err)
+	return resp, err
+}
+
+func (r *grpcLogProvider) GetPluginInfo(ctx context.Context, req *pbempty.Empty) (*pulumirpc.PluginInfo, error) {
+	resp, err := r.server.GetPluginInfo(ctx, req)
+	r.log.record("GetPluginInfo", req, resp, err)
+	return resp, err
+}
+
+// GrpcLogMismatch describes a recorded RPC whose replayed result differs from the recording.
+type GrpcLogMismatch struct {
+	Line     int    // the 1-based line number of the entry in the log.
+	Method   string // the name of the RPC.
+	Expected string // the recorded response or error.
+	Actual   string // the replayed response or error.
+}
+
+func (m GrpcLogMismatch) String() string {
+	return fmt.Sprintf("line %d: %s:\n  expected: %s\n  actual:   %s", m.Line, m.Method, m.Expected, m.Actual)
+}
+
+// GrpcLogReplay is the result of replaying a gRPC log.
+type GrpcLogReplay struct {
+	// Mismatches are the entries whose replayed responses or errors differ from the recording.
+	Mismatches []GrpcLogMismatch
+	// Skipped are the 1-based line numbers of the entries that were not replayed because their requests contain
+	// redacted values, which cannot be passed to the provider in place of the real ones.
+	Skipped []int
+}
+
+// ReplayGrpcLog feeds every request in a log recorded by NewGrpcLogProvider to the given provider, in order, and
+// returns the entries whose responses or errors differ from the recording. Replayed responses are redacted the same
+// way as recorded ones before they are compared, so redacted values never produce spurious mismatches. Requests that
+// contain redacted values are skipped, since replaying them would pass the placeholder to the provider as real input.
+//
+// Replay is not offline: requests are served by p's underlying Terraform provider, so calls such as Create and Invoke
+// reach the remote API with whatever credentials are available to the process.
+func ReplayGrpcLog(ctx context.Context, p *Provider, r io.Reader) (GrpcLogReplay, error) {
+	log := newGrpcLogger(io.Discard, p)
+	server := reflect.ValueOf(p)
+
+	var result GrpcLogReplay
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		var entry GrpcLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return GrpcLogReplay{}, errors.Wrapf(err, "line %d", line)
+		}
+		if isRedacted(entry.Request) {
+			result.Skipped = append(result.Skipped, line)
+			continue
+		}
+
+		req, resp, rpcErr, err := replayEntry(ctx, server, entry)
+		if err != nil {
+			return GrpcLogReplay{}, errors.Wrapf(err, "line %d: replaying %s", line, entry.Method)
+		}
+		actual, err := log.entry(entry.Method, req, resp, rpcErr)
+		if err != nil {
+			return GrpcLogReplay{}, errors.Wrapf(err, "line %d: encoding %s response", line, entry.Method)
+		}
+
+		if !grpcLogResultsEqual(entry, actual) {
+			result.Mismatches = append(result.Mismatches, GrpcLogMismatch{
+				Line:     line,
+				Method:   entry.Method,
+				Expected: grpcLogResult(entry),
+				Actual:   grpcLogResult(actual),
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return GrpcLogReplay{}, err
+	}
+	return result, nil
+}
+
+// isRedacted returns true if the given recorded message contains redacted values.
+func isRedacted(msg json.RawMessage) bool {
+	redacted, err := json.Marshal(redactedValue)
+	contract.AssertNoError(err)
+	return bytes.Contains(msg, redacted)
+}
+
+// replayEntry decodes a recorded request and invokes the RPC of the same name on the given server. It returns the
+// decoded request along with the RPC's response and error.
+func replayEntry(ctx context.Context, server reflect.Value,
+	entry GrpcLogEntry) (proto.Message, proto.Message, error, error) {
+
+	method := server.MethodByName(entry.Method)
+	if !method.IsValid() {
+		return nil, nil, nil, errors.Errorf("unknown method %q", entry.Method)
+	}
+	typ := method.Type()
+	if typ.NumIn() != 2 || typ.NumOut() != 2 || typ.In(1).Kind() != reflect.Ptr {
+		return nil, nil, nil, errors.Errorf("method %q is not a unary RPC", entry.Method)
+	}
+
+	req := reflect.New(typ.In(1).Elem())
+	msg, ok := req.Interface().(proto.Message)
+	contract.Assertf(ok, "request type %v is not a protobuf message", typ.In(1))
+	if len(entry.Request) != 0 {
+		if err := jsonpb.UnmarshalString(string(entry.Request), msg); err != nil {
+			return nil, nil, nil, errors.Wrap(err, "decoding request")
+		}
+	}
+
+	results := method.Call([]reflect.Value{reflect.ValueOf(ctx), req})
+	var resp proto.Message
+	if !results[0].IsNil() {
+		resp = results[0].Interface().(proto.Message)
+	}
+	var rpcErr error
+	if !results[1].IsNil() {
+		rpcErr = results[1].Interface().(error)
+	}
+	return msg, resp, rpcErr, nil
+}
+
+func grpcLogResultsEqual(expected, actual GrpcLogEntry) bool {
+	if expected.Error != actual.Error {
+		return false
+	}
+	var e, a interface{}
+	if len(expected.Response) != 0 {
+		if err := json.Unmarshal(expected.Response, &e); err != nil {
+			return false
+		}
+	}
+	if len(actual.Response) != 0 {
+		if err := json.Unmarshal(actual.Response, &a); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(e, a)
+}
+
+func grpcLogResult(entry GrpcLogEntry) string {
+	if entry.Error != "" {
+		return "error: " + entry.Error
+	}
+	return string(entry.Response)
+}
+
+// newGrpcLogFile opens the gRPC log file named by GrpcLogEnvVar, if any.
+func newGrpcLogFile() (*os.File, error) {
+	path := os.Getenv(GrpcLogEnvVar)
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening gRPC log %q", path)
+	}
+	return f, nil
+}
+
+// replayGrpcLogFile replays the log at the given path against a fresh provider. Skipped entries are reported to the
+// sink, and mismatches are reported through the returned error.
+func replayGrpcLogFile(path, module, version string, info ProviderInfo, pulumiSchema []byte, sink diag.Sink) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer contract.IgnoreClose(f)
+
+	// The provider is not connected to an engine, so it has no host to log to.
+	ctx := context.Background()
+	p := NewProvider(ctx, nil, module, version, info.P, info, pulumiSchema)
+	result, err := ReplayGrpcLog(ctx, p, f)
+	if err != nil {
+		return err
+	}
+	for _, line := range result.Skipped {
+		sink.Warningf(diag.Message("", "line %d of %s was not replayed because its request contains redacted values"),
+			line, path)
+	}
+	if len(result.Mismatches) != 0 {
+		lines := make([]string, len(result.Mismatches))
+		for i, m := range result.Mismatches {
+			lines[i] = m.String()
+		}
+		return errors.Errorf("%d of the recorded RPCs did not match:\n%s", len(result.Mismatches),
+			strings.Join(lines, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+func newGrpcLogTestProvider() *Provider {
+	provider := &Provider{
+		tf:     shimv2.NewProvider(testTFProviderV2),
+		config: shimv2.NewSchemaMap(testTFProviderV2.Schema),
+	}
+	provider.resources = map[tokens.Type]Resource{
+		"NestedSecretResource": {
+			TF:     shimv2.NewResource(testTFProviderV2.ResourcesMap["nested_secret_resource"]),
+			TFName: "nested_secret_resource",
+			Schema: &ResourceInfo{Tok: "NestedSecretResource"},
+		},
+	}
+	return provider
+}
+
+func TestGrpcLogRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	urn := resource.NewURN("stack", "project", "", "NestedSecretResource", "name")
+
+	var log bytes.Buffer
+	server := NewGrpcLogProvider(newGrpcLogTestProvider(), &log)
+
+	_, err := server.Configure(ctx, &pulumirpc.ConfigureRequest{AcceptSecrets: true, AcceptResources: true})
+	assert.NoError(t, err)
+	_, err = server.Create(ctx, &pulumirpc.CreateRequest{Urn: string(urn)})
+	assert.NoError(t, err)
+	_, err = server.Read(ctx, &pulumirpc.ReadRequest{Id: "0", Urn: string(urn)})
+	assert.NoError(t, err)
+	_, err = server.Create(ctx, &pulumirpc.CreateRequest{Urn: "urn:pulumi:stack::project::Unknown::name"})
+	assert.Error(t, err)
+
+	recorded := log.String()
+	assert.Len(t, strings.Split(strings.TrimSpace(recorded), "\n"), 4)
+	assert.NotContains(t, recorded, "password")
+	assert.Contains(t, recorded, redactedValue)
+
+	// Replaying against a fresh provider reproduces the recording.
+	result, err := ReplayGrpcLog(ctx, newGrpcLogTestProvider(), strings.NewReader(recorded))
+	assert.NoError(t, err)
+	assert.Empty(t, result.Mismatches)
+	assert.Empty(t, result.Skipped)
+
+	// A change in behavior is reported against the entry that recorded it.
+	tampered := strings.Replace(recorded, `"id":"0"`, `"id":"1"`, 1)
+	result, err = ReplayGrpcLog(ctx, newGrpcLogTestProvider(), strings.NewReader(tampered))
+	assert.NoError(t, err)
+	if assert.Len(t, result.Mismatches, 1) {
+		assert.Equal(t, 2, result.Mismatches[0].Line)
+		assert.Equal(t, "Create", result.Mismatches[0].Method)
+	}
+}
+
+func TestGrpcLogReplaySkipsRedactedRequests(t *testing.T) {
+	recorded := `{"method":"Configure","request":{"variables":{"test:config:token":"[redacted]"}},"response":{}}` +
+		"\n" + `{"method":"Configure","request":{"variables":{}},"response":{"supportsPreview":true}}` + "\n"
+
+	result, err := ReplayGrpcLog(context.Background(), newGrpcLogTestProvider(), strings.NewReader(recorded))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, result.Skipped)
+	assert.Empty(t, result.Mismatches)
+}
+
+func TestReplayGrpcLogFileReportsMismatches(t *testing.T) {
+	f, err := ioutil.TempFile("", "grpc-log")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`{"method":"Configure","request":{"variables":{"test:config:token":"[redacted]"}}}` + "\n" +
+		`{"method":"GetPluginInfo","request":{},"response":{"version":"1.0.0"}}` + "\n")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	var stderr bytes.Buffer
+	sink := diag.DefaultSink(ioutil.Discard, &stderr, diag.FormatOptions{Color: colors.Never})
+	info := ProviderInfo{Name: "test", P: testTokensProvider(nil, nil).Shim()}
+	err = replayGrpcLogFile(f.Name(), "test", "2.0.0", info, nil, sink)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "1 of the recorded RPCs did not match")
+		assert.Contains(t, err.Error(), "line 2: GetPluginInfo")
+	}
+	assert.Contains(t, stderr.String(), "line 1 of "+f.Name()+" was not replayed")
+}
+
+func TestGrpcLogRedactsSensitiveConfig(t *testing.T) {
+	l := &grpcLogger{sensitive: map[string]bool{"token": true}}
+	req := &pulumirpc.ConfigureRequest{Variables: map[string]string{
+		"test:config:token":  "hunter2",
+		"test:config:region": "us-west-2",
+	}}
+
+	encoded, err := l.encode("Configure", req, nil, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(encoded), "hunter2")
+	assert.Contains(t, string(encoded), "us-west-2")
+}
+
+func TestGrpcLogRedactsSensitiveAttributes(t *testing.T) {
+	sensitive := func(typ shim.ValueType) shim.Schema {
+		return (&schema.Schema{Type: typ, Optional: true, Sensitive: true}).Shim()
+	}
+	plain := (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim()
+	tf := (&schema.Resource{Schema: schema.SchemaMap{
+		"name":     plain,
+		"password": sensitive(shim.TypeString),
+		"credential": (&schema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			Elem: (&schema.Resource{Schema: schema.SchemaMap{
+				"label":  plain,
+				"secret": sensitive(shim.TypeString),
+			}}).Shim(),
+		}).Shim(),
+	}}).Shim()
+
+	p := &Provider{
+		resources: map[tokens.Type]Resource{
+			"test:index:Widget": {TF: tf, TFName: "test_widget", Schema: &ResourceInfo{
+				Fields: map[string]*SchemaInfo{"password": {Name: "passphrase"}},
+			}},
+		},
+		dataSources: map[tokens.ModuleMember]DataSource{
+			"test:index:getWidget": {TF: tf, TFName: "test_widget", Schema: &DataSourceInfo{}},
+		},
+	}
+	l := newGrpcLogger(ioutil.Discard, p)
+
+	props := resource.PropertyMap{
+		"name":       resource.NewStringProperty("widget"),
+		"passphrase": resource.NewStringProperty("hunter2"),
+		"credentials": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.NewObjectProperty(resource.PropertyMap{
+				"label":  resource.NewStringProperty("primary"),
+				"secret": resource.NewStringProperty("s3cret"),
+			}),
+		}),
+	}
+	marshalled, err := plugin.MarshalProperties(props, plugin.MarshalOptions{})
+	assert.NoError(t, err)
+
+	urn := resource.NewURN("stack", "project", "", "test:index:Widget", "name")
+	entry, err := l.entry("Create",
+		&pulumirpc.CreateRequest{Urn: string(urn), Properties: marshalled},
+		&pulumirpc.CreateResponse{Id: "1", Properties: marshalled}, nil)
+	assert.NoError(t, err)
+	for _, msg := range []json.RawMessage{entry.Request, entry.Response} {
+		assert.NotContains(t, string(msg), "hunter2")
+		assert.NotContains(t, string(msg), "s3cret")
+		assert.Contains(t, string(msg), "widget")
+		assert.Contains(t, string(msg), "primary")
+	}
+
+	entry, err = l.entry("Invoke",
+		&pulumirpc.InvokeRequest{Tok: "test:index:getWidget", Args: marshalled},
+		&pulumirpc.InvokeResponse{Return: marshalled}, nil)
+	assert.NoError(t, err)
+	for _, msg := range []json.RawMessage{entry.Request, entry.Response} {
+		assert.NotContains(t, string(msg), "s3cret")
+		assert.Contains(t, string(msg), "primary")
+	}
+}
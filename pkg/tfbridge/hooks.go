@@ -0,0 +1,190 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// Code-only parts of a ProviderInfo, such as transformers and default functions, cannot be encoded as JSON. Instead,
+// a provider may register each such hook under a name. The marshallable forms record the names of registered hooks,
+// and unmarshalling resolves those names back to the hooks registered in the current process. Hooks are matched by
+// identity, so a provider must use the very func value it registered: a closure or method value created anew, even
+// from the same code, is treated as unregistered.
+
+// hookRegistry maps names to hooks of a single kind.
+type hookRegistry struct {
+	m     sync.Mutex
+	hooks map[string]interface{}
+}
+
+func (r *hookRegistry) register(kind, name string, hook interface{}) {
+	contract.Assertf(name != "", "%s name must not be empty", kind)
+	contract.Assertf(hook != nil, "%s %q must not be nil", kind, name)
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.hooks == nil {
+		r.hooks = map[string]interface{}{}
+	}
+	r.hooks[name] = hook
+}
+
+func (r *hookRegistry) lookup(name string) (interface{}, bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	hook, ok := r.hooks[name]
+	return hook, ok
+}
+
+// nameOf returns the name under which the given hook is registered, or "" if it is not registered. A hook that was
+// registered under more than one name is treated as unregistered, as the choice between the names would be arbitrary.
+func (r *hookRegistry) nameOf(hook interface{}) string {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	id := hookIdentity(hook)
+	name := ""
+	for k, v := range r.hooks {
+		if hookIdentity(v) == id {
+			if name != "" {
+				return ""
+			}
+			name = k
+		}
+	}
+	return name
+}
+
+// hookIdentity returns the identity of the given func value: the address of its closure. Unlike the code pointer
+// returned by reflect.Value.Pointer, this tells apart closures that were created by the same function literal but
+// capture different variables, while copies of a func value keep the identity of the original.
+func hookIdentity(hook interface{}) unsafe.Pointer {
+	contract.Assertf(reflect.TypeOf(hook).Kind() == reflect.Func, "hook must be a func, not %T", hook)
+
+	// A func value is a pointer to its closure, and an interface holding a pointer-shaped value stores it directly
+	// as its data word.
+	return (*[2]unsafe.Pointer)(unsafe.Pointer(&hook))[1]
+}
+
+var (
+	transformers          hookRegistry
+	defaultFuncs          hookRegistry
+	preConfigureCallbacks hookRegistry
+	docsPatches           hookRegistry
+)
+
+// RegisterTransformer registers a Transformer under the given name so that SchemaInfo values that use it survive a
+// round-trip through MarshallableSchemaInfo.
+func RegisterTransformer(name string, t Transformer) {
+	transformers.register("transformer", name, t)
+}
+
+// RegisterDefaultFunc registers a function suitable for DefaultInfo.From under the given name so that DefaultInfo
+// values that use it survive a round-trip through MarshallableDefaultInfo.
+func RegisterDefaultFunc(name string, f func(res *PulumiResource) (interface{}, error)) {
+	defaultFuncs.register("default func", name, f)
+}
+
+// RegisterPreConfigureCallback registers a PreConfigureCallback under the given name so that ProviderInfo values that
+// use it survive a round-trip through MarshallableProviderInfo.
+func RegisterPreConfigureCallback(name string, cb PreConfigureCallback) {
+	preConfigureCallbacks.register("pre-configure callback", name, cb)
+}
+
+// RegisterDocsPatch registers a function suitable for DocsEdit.Patch under the given name so that DocsEdit values that
+// use it survive a round-trip through MarshallableDocsEdit.
+func RegisterDocsPatch(name string, patch func(fileName string, markdown []byte) ([]byte, error)) {
+	docsPatches.register("docs patch", name, patch)
+}
+
+// MarshallableHook is the JSON-marshallable form of a code-only hook. Name is empty if the hook was not registered,
+// in which case only its presence is recorded.
+type MarshallableHook struct {
+	Name string `json:"name,omitempty"`
+}
+
+func marshalHook(registry *hookRegistry, hook interface{}) *MarshallableHook {
+	if reflect.ValueOf(hook).IsNil() {
+		return nil
+	}
+	return &MarshallableHook{Name: registry.nameOf(hook)}
+}
+
+// unresolvedHookError returns the error reported when an unmarshaled hook is invoked but cannot be resolved.
+func unresolvedHookError(kind string, m *MarshallableHook) error {
+	if m.Name == "" {
+		return fmt.Errorf("unnamed %s cannot be run after unmarshaling", kind)
+	}
+	return fmt.Errorf("%s %q is not registered", kind, m.Name)
+}
+
+func (m *MarshallableHook) unmarshalTransformer() Transformer {
+	if m == nil {
+		return nil
+	}
+	if hook, ok := transformers.lookup(m.Name); ok {
+		return hook.(Transformer)
+	}
+	return func(resource.PropertyValue) (resource.PropertyValue, error) {
+		return resource.PropertyValue{}, unresolvedHookError("transformer", m)
+	}
+}
+
+func (m *MarshallableHook) unmarshalDefaultFunc() func(*PulumiResource) (interface{}, error) {
+	if m == nil {
+		return nil
+	}
+	if hook, ok := defaultFuncs.lookup(m.Name); ok {
+		return hook.(func(*PulumiResource) (interface{}, error))
+	}
+	return func(*PulumiResource) (interface{}, error) {
+		return nil, unresolvedHookError("default func", m)
+	}
+}
+
+func (m *MarshallableHook) unmarshalPreConfigureCallback() PreConfigureCallback {
+	if m == nil {
+		return nil
+	}
+	if hook, ok := preConfigureCallbacks.lookup(m.Name); ok {
+		return hook.(PreConfigureCallback)
+	}
+	return func(resource.PropertyMap, shim.ResourceConfig) error {
+		return unresolvedHookError("pre-configure callback", m)
+	}
+}
+
+func (m *MarshallableHook) unmarshalDocsPatch() func(string, []byte) ([]byte, error) {
+	if m == nil {
+		return nil
+	}
+	if hook, ok := docsPatches.lookup(m.Name); ok {
+		return hook.(func(string, []byte) ([]byte, error))
+	}
+	return func(string, []byte) ([]byte, error) {
+		return nil, unresolvedHookError("docs patch", m)
+	}
+}
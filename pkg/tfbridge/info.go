@@ -15,7 +15,11 @@
 package tfbridge
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"regexp"
+	"time"
 	"unicode"
 
 	"github.com/blang/semver"
@@ -76,6 +80,21 @@ type ProviderInfo struct {
 	TFProviderModuleVersion  string             // the Go module version of the provider. Default is unversioned e.g. v1
 
 	PreConfigureCallback PreConfigureCallback // a provider-specific callback to invoke prior to TF Configure
+
+	// ProviderMeta is an optional provider_meta value to send to the TF provider with each resource and data source
+	// operation, e.g. to attribute requests to Pulumi. The TF provider must implement shim.ProviderWithMeta.
+	ProviderMeta map[string]interface{}
+
+	// DocsEdits are edits to apply to the upstream markdown of every resource and data source before it is parsed,
+	// e.g. to remove Terraform-specific text. Edits for a single entity may be given in its DocInfo instead.
+	DocsEdits []DocsEdit
+
+	// EmitReplaceOnChanges marks the properties whose changes force the replacement of their resources in TF as
+	// replaceOnChanges in the generated schema. The generated SDKs then ask the engine to replace such resources on
+	// any change to these properties, even where the TF provider would suppress the diff, so this is opt-in. It also
+	// notes in the docs of each input property whether changing it forces replacement and which value it takes when
+	// it is not set, whether from an environment variable, the provider configuration or the provider itself.
+	EmitReplaceOnChanges bool
 }
 
 // TFProviderLicense is a way to be able to pass a license type for the upstream Terraform provider.
@@ -172,6 +191,10 @@ type DataSourceInfo struct {
 	Fields             map[string]*SchemaInfo
 	Docs               *DocInfo // overrides for finding and mapping TF docs.
 	DeprecationMessage string   // message to use in deprecation warning
+
+	// CacheTTL, if non-zero, enables memoization of this data source's invocations within a single provider process.
+	// Invocations with identical, fully-known arguments reuse the previous result until it is older than CacheTTL.
+	CacheTTL time.Duration
 }
 
 // GetTok returns a datasource type token
@@ -244,6 +267,35 @@ type SchemaInfo struct {
 
 	// whether or not to treat this property as secret
 	Secret *bool
+
+	// an optional enum type for a string property, which overrides any enum derived from its Terraform validator.
+	Enum *EnumInfo
+}
+
+// EnumInfo describes the Pulumi enum type of a string property. Enums only apply to inputs, which generated SDKs type
+// as either the enum or a plain string, and the provider still accepts any string. Programs written before the enum
+// was introduced therefore keep compiling and working.
+type EnumInfo struct {
+	// Tok is an optional token for the enum type, e.g. "aws:s3/storageClass:StorageClass". Properties that set the
+	// same token share a single type and must describe the same values; tfgen reports an error otherwise. If Tok is
+	// empty, the type is named after the property like other nested types.
+	Tok tokens.Type
+	// Description documents the enum type.
+	Description string
+	// Values lists the values of the enum.
+	Values []EnumValueInfo
+}
+
+// EnumValueInfo describes a single value of an enum type.
+type EnumValueInfo struct {
+	// Value is the string that is sent to Terraform.
+	Value string
+	// Name optionally overrides the name of the value in generated SDKs, which is otherwise derived from Value.
+	Name string
+	// Description documents the value.
+	Description string
+	// DeprecationMessage, if set, marks the value as deprecated.
+	DeprecationMessage string
 }
 
 // ConfigInfo represents a synthetic configuration variable that is Pulumi-only, and not passed to Terraform.
@@ -264,12 +316,35 @@ type Transformer func(resource.PropertyValue) (resource.PropertyValue, error)
 
 // DocInfo contains optional overrides for finding and mapping TF docs.
 type DocInfo struct {
-	Source                         string // an optional override to locate TF docs; "" uses the default.
-	Markdown                       []byte // an optional override for the source markdown.
-	IncludeAttributesFrom          string // optionally include attributes from another raw resource for docs.
-	IncludeArgumentsFrom           string // optionally include arguments from another raw resource for docs.
-	IncludeAttributesFromArguments string // optionally include attributes from another raw resource's arguments.
-	ImportDetails                  string // Overwrite for import instructions
+	Source                         string     // an optional override to locate TF docs; "" uses the default.
+	Markdown                       []byte     // an optional override for the source markdown.
+	IncludeAttributesFrom          string     // optionally include attributes from another raw resource for docs.
+	IncludeArgumentsFrom           string     // optionally include arguments from another raw resource for docs.
+	IncludeAttributesFromArguments string     // optionally include attributes from another raw resource's arguments.
+	ImportDetails                  string     // Overwrite for import instructions
+	Edits                          []DocsEdit // edits to apply to the markdown after ProviderInfo.DocsEdits.
+}
+
+// DocsEdit is a declarative edit to the upstream markdown of resources and data sources. Edits are applied to the raw
+// markdown before it is parsed. Exactly one of Literal, Regexp, RemoveSection or Patch must be set. tfgen warns about
+// every edit that did not change any of the files it applied to, so that edits do not silently go stale when the
+// upstream docs change.
+type DocsEdit struct {
+	// Path, if set, limits the edit to the docs files whose names match this pattern, in the syntax of path.Match,
+	// e.g. "*_instance.html.markdown".
+	Path string
+	// Literal is text to replace with Replacement wherever it occurs.
+	Literal string
+	// Regexp is a pattern to replace with Replacement wherever it matches. Replacement may refer to submatches as
+	// described by regexp.Regexp.Expand, e.g. "$1".
+	Regexp *regexp.Regexp
+	// Replacement is the text that replaces Literal or Regexp.
+	Replacement string
+	// RemoveSection is the title of a section to remove, e.g. "Timeouts". The section's heading and everything up to
+	// the next heading of the same or a higher level are removed.
+	RemoveSection string
+	// Patch edits the markdown of the file with the given name and returns the result.
+	Patch func(fileName string, markdown []byte) ([]byte, error)
 }
 
 // GetImportDetails returns a string of import instructions defined in the Pulumi provider. Defaults to empty.
@@ -345,24 +420,42 @@ type CSharpInfo struct {
 type PreConfigureCallback func(vars resource.PropertyMap, config shim.ResourceConfig) error
 
 // The types below are marshallable versions of the schema descriptions associated with a provider. These are used when
-// marshalling a provider info as JSON; Note that these types only represent a subset of the informatino associated
-// with a ProviderInfo; thus, a ProviderInfo cannot be round-tripped through JSON.
+// marshalling a provider info as JSON. They capture every declarative part of a ProviderInfo; code-only hooks are
+// recorded by the name under which they were registered (see RegisterTransformer and friends), and resolved back to
+// the registered hooks when unmarshaling. The Terraform provider itself is captured as a schema-only provider.
+
+// ProviderInfoFormatVersion is the version of the JSON encoding written by MarshalProviderInfo. Encodings without a
+// version predate versioning and are treated as version 1.
+const ProviderInfoFormatVersion = 2
 
 // MarshallableSchema is the JSON-marshallable form of a Terraform schema.
 type MarshallableSchema struct {
-	Type               shim.ValueType    `json:"type"`
-	Optional           bool              `json:"optional,omitempty"`
-	Required           bool              `json:"required,omitempty"`
-	Computed           bool              `json:"computed,omitempty"`
-	ForceNew           bool              `json:"forceNew,omitempty"`
-	Elem               *MarshallableElem `json:"element,omitempty"`
-	MaxItems           int               `json:"maxItems,omitempty"`
-	MinItems           int               `json:"minItems,omitempty"`
-	DeprecationMessage string            `json:"deprecated,omitempty"`
+	Type               shim.ValueType         `json:"type"`
+	Optional           bool                   `json:"optional,omitempty"`
+	Required           bool                   `json:"required,omitempty"`
+	Computed           bool                   `json:"computed,omitempty"`
+	ForceNew           bool                   `json:"forceNew,omitempty"`
+	Elem               *MarshallableElem      `json:"element,omitempty"`
+	MaxItems           int                    `json:"maxItems,omitempty"`
+	MinItems           int                    `json:"minItems,omitempty"`
+	DeprecationMessage string                 `json:"deprecated,omitempty"`
+	Description        string                 `json:"description,omitempty"`
+	Default            interface{}            `json:"default,omitempty"`
+	Sensitive          bool                   `json:"sensitive,omitempty"`
+	ConflictsWith      []string               `json:"conflictsWith,omitempty"`
+	ExactlyOneOf       []string               `json:"exactlyOneOf,omitempty"`
+	AtLeastOneOf       []string               `json:"atLeastOneOf,omitempty"`
+	RequiredWith       []string               `json:"requiredWith,omitempty"`
+	Validation         *shim.SchemaValidation `json:"validation,omitempty"`
+	Removed            string                 `json:"removed,omitempty"`
 }
 
 // MarshalSchema converts a Terraform schema into a MarshallableSchema.
 func MarshalSchema(s shim.Schema) *MarshallableSchema {
+	if s == nil {
+		return nil
+	}
+
 	return &MarshallableSchema{
 		Type:               s.Type(),
 		Optional:           s.Optional(),
@@ -373,21 +466,49 @@ func MarshalSchema(s shim.Schema) *MarshallableSchema {
 		MaxItems:           s.MaxItems(),
 		MinItems:           s.MinItems(),
 		DeprecationMessage: s.Deprecated(),
+		Description:        s.Description(),
+		Default:            s.Default(),
+		Sensitive:          s.Sensitive(),
+		ConflictsWith:      s.ConflictsWith(),
+		ExactlyOneOf:       s.ExactlyOneOf(),
+		AtLeastOneOf:       s.AtLeastOneOf(),
+		RequiredWith:       s.RequiredWith(),
+		Validation:         s.Validation(),
+		Removed:            s.Removed(),
 	}
 }
 
 // Unmarshal creates a mostly-initialized Terraform schema from the given MarshallableSchema.
 func (m *MarshallableSchema) Unmarshal() shim.Schema {
+	if m == nil {
+		return nil
+	}
+
+	// JSON decodes every number as a float64, but the defaults of integer properties are ints.
+	defaultValue := m.Default
+	if m.Type == shim.TypeInt {
+		defaultValue = restoreNumber(defaultValue, "int")
+	}
+
 	return (&schema.Schema{
-		Type:       m.Type,
-		Optional:   m.Optional,
-		Required:   m.Required,
-		Computed:   m.Computed,
-		ForceNew:   m.ForceNew,
-		Elem:       m.Elem.Unmarshal(),
-		MaxItems:   m.MaxItems,
-		MinItems:   m.MinItems,
-		Deprecated: m.DeprecationMessage,
+		Type:          m.Type,
+		Optional:      m.Optional,
+		Required:      m.Required,
+		Computed:      m.Computed,
+		ForceNew:      m.ForceNew,
+		Elem:          m.Elem.Unmarshal(),
+		MaxItems:      m.MaxItems,
+		MinItems:      m.MinItems,
+		Deprecated:    m.DeprecationMessage,
+		Description:   m.Description,
+		Default:       defaultValue,
+		Sensitive:     m.Sensitive,
+		ConflictsWith: m.ConflictsWith,
+		ExactlyOneOf:  m.ExactlyOneOf,
+		AtLeastOneOf:  m.AtLeastOneOf,
+		RequiredWith:  m.RequiredWith,
+		Validation:    m.Validation,
+		Removed:       m.Removed,
 	}).Shim()
 }
 
@@ -508,18 +629,45 @@ func (m *MarshallableProvider) Unmarshal() shim.Provider {
 
 // MarshallableSchemaInfo is the JSON-marshallable form of a Pulumi SchemaInfo value.
 type MarshallableSchemaInfo struct {
-	Name        string                             `json:"name,omitempty"`
-	CSharpName  string                             `json:"csharpName,omitempty"`
-	Type        tokens.Type                        `json:"typeomitempty"`
-	AltTypes    []tokens.Type                      `json:"altTypes,omitempty"`
-	Elem        *MarshallableSchemaInfo            `json:"element,omitempty"`
-	Fields      map[string]*MarshallableSchemaInfo `json:"fields,omitempty"`
-	Asset       *AssetTranslation                  `json:"asset,omitempty"`
-	Default     *MarshallableDefaultInfo           `json:"default,omitempty"`
-	MaxItemsOne *bool                              `json:"maxItemsOne,omitempty"`
-	Deprecated  string                             `json:"deprecated,omitempty"`
-	ForceNew    *bool                              `json:"forceNew,omitempty"`
-	Secret      *bool                              `json:"secret,omitempty"`
+	Name                     string                             `json:"name,omitempty"`
+	CSharpName               string                             `json:"csharpName,omitempty"`
+	Type                     tokens.Type                        `json:"type,omitempty"`
+	AltTypes                 []tokens.Type                      `json:"altTypes,omitempty"`
+	NestedType               tokens.Type                        `json:"nestedType,omitempty"`
+	Transform                *MarshallableHook                  `json:"transform,omitempty"`
+	Elem                     *MarshallableSchemaInfo            `json:"element,omitempty"`
+	Fields                   map[string]*MarshallableSchemaInfo `json:"fields,omitempty"`
+	Asset                    *AssetTranslation                  `json:"asset,omitempty"`
+	Default                  *MarshallableDefaultInfo           `json:"default,omitempty"`
+	Stable                   *bool                              `json:"stable,omitempty"`
+	MaxItemsOne              *bool                              `json:"maxItemsOne,omitempty"`
+	SuppressEmptyMapElements *bool                              `json:"suppressEmptyMapElements,omitempty"`
+	MarkAsComputedOnly       *bool                              `json:"markAsComputedOnly,omitempty"`
+	MarkAsOptional           *bool                              `json:"markAsOptional,omitempty"`
+	Deprecated               string                             `json:"deprecated,omitempty"`
+	ForceNew                 *bool                              `json:"forceNew,omitempty"`
+	Removed                  bool                               `json:"removed,omitempty"`
+	Omit                     bool                               `json:"omit,omitempty"`
+	Secret                   *bool                              `json:"secret,omitempty"`
+	Enum                     *MarshallableEnumInfo              `json:"enum,omitempty"`
+}
+
+// UnmarshalJSON decodes a MarshallableSchemaInfo. Version 1 encodings wrote the type override under the key
+// "typeomitempty"; that key is still accepted.
+func (m *MarshallableSchemaInfo) UnmarshalJSON(b []byte) error {
+	type plain MarshallableSchemaInfo
+	var v struct {
+		plain
+		LegacyType tokens.Type `json:"typeomitempty,omitempty"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v.Type == "" {
+		v.Type = v.LegacyType
+	}
+	*m = MarshallableSchemaInfo(v.plain)
+	return nil
 }
 
 // MarshalSchemaInfo converts a Pulumi SchemaInfo value into a MarshallableSchemaInfo value.
@@ -528,23 +676,28 @@ func MarshalSchemaInfo(s *SchemaInfo) *MarshallableSchemaInfo {
 		return nil
 	}
 
-	fields := make(map[string]*MarshallableSchemaInfo)
-	for k, v := range s.Fields {
-		fields[k] = MarshalSchemaInfo(v)
-	}
 	return &MarshallableSchemaInfo{
-		Name:        s.Name,
-		CSharpName:  s.CSharpName,
-		Type:        s.Type,
-		AltTypes:    s.AltTypes,
-		Elem:        MarshalSchemaInfo(s.Elem),
-		Fields:      fields,
-		Asset:       s.Asset,
-		Default:     MarshalDefaultInfo(s.Default),
-		MaxItemsOne: s.MaxItemsOne,
-		Deprecated:  s.DeprecationMessage,
-		ForceNew:    s.ForceNew,
-		Secret:      s.Secret,
+		Name:                     s.Name,
+		CSharpName:               s.CSharpName,
+		Type:                     s.Type,
+		AltTypes:                 s.AltTypes,
+		NestedType:               s.NestedType,
+		Transform:                marshalHook(&transformers, s.Transform),
+		Elem:                     MarshalSchemaInfo(s.Elem),
+		Fields:                   marshalSchemaInfos(s.Fields),
+		Asset:                    s.Asset,
+		Default:                  MarshalDefaultInfo(s.Default),
+		Stable:                   s.Stable,
+		MaxItemsOne:              s.MaxItemsOne,
+		SuppressEmptyMapElements: s.SuppressEmptyMapElements,
+		MarkAsComputedOnly:       s.MarkAsComputedOnly,
+		MarkAsOptional:           s.MarkAsOptional,
+		Deprecated:               s.DeprecationMessage,
+		ForceNew:                 s.ForceNew,
+		Removed:                  s.Removed,
+		Omit:                     s.Omit,
+		Secret:                   s.Secret,
+		Enum:                     MarshalEnumInfo(s.Enum),
 	}
 }
 
@@ -554,32 +707,147 @@ func (m *MarshallableSchemaInfo) Unmarshal() *SchemaInfo {
 		return nil
 	}
 
-	fields := make(map[string]*SchemaInfo)
-	for k, v := range m.Fields {
-		fields[k] = v.Unmarshal()
-	}
 	return &SchemaInfo{
-		Name:               m.Name,
-		CSharpName:         m.CSharpName,
-		Type:               m.Type,
-		AltTypes:           m.AltTypes,
-		Elem:               m.Elem.Unmarshal(),
-		Fields:             fields,
-		Asset:              m.Asset,
-		Default:            m.Default.Unmarshal(),
-		MaxItemsOne:        m.MaxItemsOne,
-		DeprecationMessage: m.Deprecated,
-		ForceNew:           m.ForceNew,
-		Secret:             m.Secret,
+		Name:                     m.Name,
+		CSharpName:               m.CSharpName,
+		Type:                     m.Type,
+		AltTypes:                 m.AltTypes,
+		NestedType:               m.NestedType,
+		Transform:                m.Transform.unmarshalTransformer(),
+		Elem:                     m.Elem.Unmarshal(),
+		Fields:                   unmarshalSchemaInfos(m.Fields),
+		Asset:                    m.Asset,
+		Default:                  m.Default.Unmarshal(),
+		Stable:                   m.Stable,
+		MaxItemsOne:              m.MaxItemsOne,
+		SuppressEmptyMapElements: m.SuppressEmptyMapElements,
+		MarkAsComputedOnly:       m.MarkAsComputedOnly,
+		MarkAsOptional:           m.MarkAsOptional,
+		DeprecationMessage:       m.Deprecated,
+		ForceNew:                 m.ForceNew,
+		Removed:                  m.Removed,
+		Omit:                     m.Omit,
+		Secret:                   m.Secret,
+		Enum:                     m.Enum.Unmarshal(),
+	}
+}
+
+func marshalSchemaInfos(infos map[string]*SchemaInfo) map[string]*MarshallableSchemaInfo {
+	if infos == nil {
+		return nil
+	}
+
+	m := make(map[string]*MarshallableSchemaInfo)
+	for k, v := range infos {
+		m[k] = MarshalSchemaInfo(v)
+	}
+	return m
+}
+
+func unmarshalSchemaInfos(m map[string]*MarshallableSchemaInfo) map[string]*SchemaInfo {
+	if m == nil {
+		return nil
+	}
+
+	infos := make(map[string]*SchemaInfo)
+	for k, v := range m {
+		infos[k] = v.Unmarshal()
+	}
+	return infos
+}
+
+// MarshallableEnumInfo is the JSON-marshallable form of a Pulumi EnumInfo value.
+type MarshallableEnumInfo struct {
+	Tok         tokens.Type                 `json:"tok,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Values      []MarshallableEnumValueInfo `json:"values,omitempty"`
+}
+
+// MarshallableEnumValueInfo is the JSON-marshallable form of a Pulumi EnumValueInfo value.
+type MarshallableEnumValueInfo struct {
+	Value              string `json:"value"`
+	Name               string `json:"name,omitempty"`
+	Description        string `json:"description,omitempty"`
+	DeprecationMessage string `json:"deprecationMessage,omitempty"`
+}
+
+// MarshalEnumInfo converts a Pulumi EnumInfo value into a MarshallableEnumInfo value.
+func MarshalEnumInfo(e *EnumInfo) *MarshallableEnumInfo {
+	if e == nil {
+		return nil
+	}
+
+	var values []MarshallableEnumValueInfo
+	for _, v := range e.Values {
+		values = append(values, MarshallableEnumValueInfo(v))
+	}
+	return &MarshallableEnumInfo{
+		Tok:         e.Tok,
+		Description: e.Description,
+		Values:      values,
+	}
+}
+
+// Unmarshal creates a Pulumi EnumInfo value from the given MarshallableEnumInfo.
+func (m *MarshallableEnumInfo) Unmarshal() *EnumInfo {
+	if m == nil {
+		return nil
+	}
+
+	var values []EnumValueInfo
+	for _, v := range m.Values {
+		values = append(values, EnumValueInfo(v))
+	}
+	return &EnumInfo{
+		Tok:         m.Tok,
+		Description: m.Description,
+		Values:      values,
 	}
 }
 
 // MarshallableDefaultInfo is the JSON-marshallable form of a Pulumi DefaultInfo value.
 type MarshallableDefaultInfo struct {
-	AutoNamed bool        `json:"autonamed,omitempty"`
-	IsFunc    bool        `json:"isFunc,omitempty"`
-	Value     interface{} `json:"value,omitempty"`
-	EnvVars   []string    `json:"envvars,omitempty"`
+	AutoNamed bool              `json:"autonamed,omitempty"`
+	Config    string            `json:"config,omitempty"`
+	IsFunc    bool              `json:"isFunc,omitempty"`
+	From      *MarshallableHook `json:"from,omitempty"`
+	Value     interface{}       `json:"value,omitempty"`
+	ValueType string            `json:"valueType,omitempty"`
+	EnvVars   []string          `json:"envvars,omitempty"`
+}
+
+// numberTypes maps the names of Go number types other than float64 to their types. JSON decodes every number as a
+// float64, so the marshallable forms record which of these types a number had in order to restore it.
+var numberTypes = func() map[string]reflect.Type {
+	m := map[string]reflect.Type{}
+	for _, v := range []interface{}{
+		int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		float32(0),
+	} {
+		m[reflect.TypeOf(v).String()] = reflect.TypeOf(v)
+	}
+	return m
+}()
+
+// numberType returns the name of the type of v if it is a number that does not decode as itself from JSON.
+func numberType(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if _, ok := numberTypes[reflect.TypeOf(v).String()]; ok {
+		return reflect.TypeOf(v).String()
+	}
+	return ""
+}
+
+// restoreNumber converts a number decoded from JSON back to the type with the given name.
+func restoreNumber(v interface{}, typeName string) interface{} {
+	t, ok := numberTypes[typeName]
+	if f, isFloat := v.(float64); ok && isFloat {
+		return reflect.ValueOf(f).Convert(t).Interface()
+	}
+	return v
 }
 
 // MarshalDefaultInfo converts a Pulumi DefaultInfo value into a MarshallableDefaultInfo value.
@@ -590,8 +858,11 @@ func MarshalDefaultInfo(d *DefaultInfo) *MarshallableDefaultInfo {
 
 	return &MarshallableDefaultInfo{
 		AutoNamed: d.AutoNamed,
+		Config:    d.Config,
 		IsFunc:    d.From != nil,
+		From:      marshalHook(&defaultFuncs, d.From),
 		Value:     d.Value,
+		ValueType: numberType(d.Value),
 		EnvVars:   d.EnvVars,
 	}
 }
@@ -602,118 +873,398 @@ func (m *MarshallableDefaultInfo) Unmarshal() *DefaultInfo {
 		return nil
 	}
 
-	var f func(*PulumiResource) (interface{}, error)
-	if m.IsFunc {
-		f = func(*PulumiResource) (interface{}, error) {
-			panic("transforms cannot be run on unmarshaled DefaultInfo values")
-		}
+	from := m.From
+	if from == nil && m.IsFunc {
+		// Version 1 encodings only record the presence of a function.
+		from = &MarshallableHook{}
 	}
 
 	return &DefaultInfo{
 		AutoNamed: m.AutoNamed,
-		From:      f,
-		Value:     m.Value,
+		Config:    m.Config,
+		From:      from.unmarshalDefaultFunc(),
+		Value:     restoreNumber(m.Value, m.ValueType),
 		EnvVars:   m.EnvVars,
 	}
 }
 
+// MarshallableDocInfo is the JSON-marshallable form of a Pulumi DocInfo value.
+type MarshallableDocInfo struct {
+	Source                         string                 `json:"source,omitempty"`
+	Markdown                       []byte                 `json:"markdown,omitempty"`
+	IncludeAttributesFrom          string                 `json:"includeAttributesFrom,omitempty"`
+	IncludeArgumentsFrom           string                 `json:"includeArgumentsFrom,omitempty"`
+	IncludeAttributesFromArguments string                 `json:"includeAttributesFromArguments,omitempty"`
+	ImportDetails                  string                 `json:"importDetails,omitempty"`
+	Edits                          []MarshallableDocsEdit `json:"edits,omitempty"`
+}
+
+// MarshalDocInfo converts a Pulumi DocInfo value into a MarshallableDocInfo value.
+func MarshalDocInfo(d *DocInfo) *MarshallableDocInfo {
+	if d == nil {
+		return nil
+	}
+
+	return &MarshallableDocInfo{
+		Source:                         d.Source,
+		Markdown:                       d.Markdown,
+		IncludeAttributesFrom:          d.IncludeAttributesFrom,
+		IncludeArgumentsFrom:           d.IncludeArgumentsFrom,
+		IncludeAttributesFromArguments: d.IncludeAttributesFromArguments,
+		ImportDetails:                  d.ImportDetails,
+		Edits:                          marshalDocsEdits(d.Edits),
+	}
+}
+
+// Unmarshal creates a Pulumi DocInfo value from the given MarshallableDocInfo.
+func (m *MarshallableDocInfo) Unmarshal() *DocInfo {
+	if m == nil {
+		return nil
+	}
+
+	return &DocInfo{
+		Source:                         m.Source,
+		Markdown:                       m.Markdown,
+		IncludeAttributesFrom:          m.IncludeAttributesFrom,
+		IncludeArgumentsFrom:           m.IncludeArgumentsFrom,
+		IncludeAttributesFromArguments: m.IncludeAttributesFromArguments,
+		ImportDetails:                  m.ImportDetails,
+		Edits:                          unmarshalDocsEdits(m.Edits),
+	}
+}
+
+// MarshallableDocsEdit is the JSON-marshallable form of a Pulumi DocsEdit value. Regexp holds the source of the
+// pattern; a Patch function is recorded by the name under which it was registered with RegisterDocsPatch.
+type MarshallableDocsEdit struct {
+	Path          string            `json:"path,omitempty"`
+	Literal       string            `json:"literal,omitempty"`
+	Regexp        string            `json:"regexp,omitempty"`
+	Replacement   string            `json:"replacement,omitempty"`
+	RemoveSection string            `json:"removeSection,omitempty"`
+	Patch         *MarshallableHook `json:"patch,omitempty"`
+}
+
+// UnmarshalJSON decodes a MarshallableDocsEdit, rejecting patterns that do not compile.
+func (m *MarshallableDocsEdit) UnmarshalJSON(b []byte) error {
+	type plain MarshallableDocsEdit
+	var v plain
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v.Regexp != "" {
+		if _, err := regexp.Compile(v.Regexp); err != nil {
+			return fmt.Errorf("invalid docs edit pattern: %w", err)
+		}
+	}
+	*m = MarshallableDocsEdit(v)
+	return nil
+}
+
+// MarshalDocsEdit converts a Pulumi DocsEdit value into a MarshallableDocsEdit value.
+func MarshalDocsEdit(e DocsEdit) MarshallableDocsEdit {
+	m := MarshallableDocsEdit{
+		Path:          e.Path,
+		Literal:       e.Literal,
+		Replacement:   e.Replacement,
+		RemoveSection: e.RemoveSection,
+	}
+	if e.Regexp != nil {
+		m.Regexp = e.Regexp.String()
+	}
+	if e.Patch != nil {
+		m.Patch = marshalHook(&docsPatches, e.Patch)
+	}
+	return m
+}
+
+// Unmarshal creates a Pulumi DocsEdit value from the given MarshallableDocsEdit.
+func (m MarshallableDocsEdit) Unmarshal() DocsEdit {
+	e := DocsEdit{
+		Path:          m.Path,
+		Literal:       m.Literal,
+		Replacement:   m.Replacement,
+		RemoveSection: m.RemoveSection,
+		Patch:         m.Patch.unmarshalDocsPatch(),
+	}
+	if m.Regexp != "" {
+		e.Regexp = regexp.MustCompile(m.Regexp)
+	}
+	return e
+}
+
+func marshalDocsEdits(edits []DocsEdit) []MarshallableDocsEdit {
+	var m []MarshallableDocsEdit
+	for _, e := range edits {
+		m = append(m, MarshalDocsEdit(e))
+	}
+	return m
+}
+
+func unmarshalDocsEdits(m []MarshallableDocsEdit) []DocsEdit {
+	var edits []DocsEdit
+	for _, e := range m {
+		edits = append(edits, e.Unmarshal())
+	}
+	return edits
+}
+
 // MarshallableResourceInfo is the JSON-marshallable form of a Pulumi ResourceInfo value.
 type MarshallableResourceInfo struct {
-	Tok      tokens.Type                        `json:"tok"`
-	Fields   map[string]*MarshallableSchemaInfo `json:"fields"`
-	IDFields []string                           `json:"idFields"`
+	Tok                 tokens.Type                        `json:"tok"`
+	Fields              map[string]*MarshallableSchemaInfo `json:"fields"`
+	IDFields            []string                           `json:"idFields"`
+	Docs                *MarshallableDocInfo               `json:"docs,omitempty"`
+	DeleteBeforeReplace bool                               `json:"deleteBeforeReplace,omitempty"`
+	Aliases             []AliasInfo                        `json:"aliases,omitempty"`
+	DeprecationMessage  string                             `json:"deprecated,omitempty"`
+	CSharpName          string                             `json:"csharpName,omitempty"`
 }
 
 // MarshalResourceInfo converts a Pulumi ResourceInfo value into a MarshallableResourceInfo value.
 func MarshalResourceInfo(r *ResourceInfo) *MarshallableResourceInfo {
-	fields := make(map[string]*MarshallableSchemaInfo)
-	for k, v := range r.Fields {
-		fields[k] = MarshalSchemaInfo(v)
-	}
 	return &MarshallableResourceInfo{
-		Tok:      r.Tok,
-		Fields:   fields,
-		IDFields: r.IDFields,
+		Tok:                 r.Tok,
+		Fields:              marshalSchemaInfos(r.Fields),
+		IDFields:            r.IDFields,
+		Docs:                MarshalDocInfo(r.Docs),
+		DeleteBeforeReplace: r.DeleteBeforeReplace,
+		Aliases:             r.Aliases,
+		DeprecationMessage:  r.DeprecationMessage,
+		CSharpName:          r.CSharpName,
 	}
 }
 
 // Unmarshal creates a mostly-=initialized Pulumi ResourceInfo value from the given MarshallableResourceInfo.
 func (m *MarshallableResourceInfo) Unmarshal() *ResourceInfo {
-	fields := make(map[string]*SchemaInfo)
-	for k, v := range m.Fields {
-		fields[k] = v.Unmarshal()
-	}
 	return &ResourceInfo{
-		Tok:      m.Tok,
-		Fields:   fields,
-		IDFields: m.IDFields,
+		Tok:                 m.Tok,
+		Fields:              unmarshalSchemaInfos(m.Fields),
+		IDFields:            m.IDFields,
+		Docs:                m.Docs.Unmarshal(),
+		DeleteBeforeReplace: m.DeleteBeforeReplace,
+		Aliases:             m.Aliases,
+		DeprecationMessage:  m.DeprecationMessage,
+		CSharpName:          m.CSharpName,
 	}
 }
 
 // MarshallableDataSourceInfo is the JSON-marshallable form of a Pulumi DataSourceInfo value.
 type MarshallableDataSourceInfo struct {
-	Tok    tokens.ModuleMember                `json:"tok"`
-	Fields map[string]*MarshallableSchemaInfo `json:"fields"`
+	Tok                tokens.ModuleMember                `json:"tok"`
+	Fields             map[string]*MarshallableSchemaInfo `json:"fields"`
+	Docs               *MarshallableDocInfo               `json:"docs,omitempty"`
+	DeprecationMessage string                             `json:"deprecated,omitempty"`
+	CacheTTL           time.Duration                      `json:"cacheTTL,omitempty"`
 }
 
 // MarshalDataSourceInfo converts a Pulumi DataSourceInfo value into a MarshallableDataSourceInfo value.
 func MarshalDataSourceInfo(d *DataSourceInfo) *MarshallableDataSourceInfo {
-	fields := make(map[string]*MarshallableSchemaInfo)
-	for k, v := range d.Fields {
-		fields[k] = MarshalSchemaInfo(v)
-	}
 	return &MarshallableDataSourceInfo{
-		Tok:    d.Tok,
-		Fields: fields,
+		Tok:                d.Tok,
+		Fields:             marshalSchemaInfos(d.Fields),
+		Docs:               MarshalDocInfo(d.Docs),
+		DeprecationMessage: d.DeprecationMessage,
+		CacheTTL:           d.CacheTTL,
 	}
 }
 
 // Unmarshal creates a mostly-=initialized Pulumi DataSourceInfo value from the given MarshallableDataSourceInfo.
 func (m *MarshallableDataSourceInfo) Unmarshal() *DataSourceInfo {
-	fields := make(map[string]*SchemaInfo)
-	for k, v := range m.Fields {
-		fields[k] = v.Unmarshal()
-	}
 	return &DataSourceInfo{
-		Tok:    m.Tok,
-		Fields: fields,
+		Tok:                m.Tok,
+		Fields:             unmarshalSchemaInfos(m.Fields),
+		Docs:               m.Docs.Unmarshal(),
+		DeprecationMessage: m.DeprecationMessage,
+		CacheTTL:           m.CacheTTL,
 	}
 }
 
+// MarshallableConfigInfo is the JSON-marshallable form of a Pulumi ConfigInfo value.
+type MarshallableConfigInfo struct {
+	Info   *MarshallableSchemaInfo `json:"info,omitempty"`
+	Schema *MarshallableSchema     `json:"schema,omitempty"`
+}
+
+// MarshalConfigInfo converts a Pulumi ConfigInfo value into a MarshallableConfigInfo value.
+func MarshalConfigInfo(c *ConfigInfo) *MarshallableConfigInfo {
+	if c == nil {
+		return nil
+	}
+
+	return &MarshallableConfigInfo{
+		Info:   MarshalSchemaInfo(c.Info),
+		Schema: MarshalSchema(c.Schema),
+	}
+}
+
+// Unmarshal creates a mostly-initialized Pulumi ConfigInfo value from the given MarshallableConfigInfo.
+func (m *MarshallableConfigInfo) Unmarshal() *ConfigInfo {
+	if m == nil {
+		return nil
+	}
+
+	return &ConfigInfo{
+		Info:   m.Info.Unmarshal(),
+		Schema: m.Schema.Unmarshal(),
+	}
+}
+
+// MarshallableHclExample is the JSON-marshallable form of a LocalFileHclExample or InlineHclExample value. Exactly
+// one of RelativePath and Contents is set.
+type MarshallableHclExample struct {
+	Token        string `json:"token"`
+	Title        string `json:"title,omitempty"`
+	RelativePath string `json:"relativePath,omitempty"`
+	Contents     string `json:"contents,omitempty"`
+}
+
+// marshalHclExamples converts HCL examples into their marshallable forms. Examples of other types cannot be
+// described declaratively and are omitted.
+func marshalHclExamples(examples []HclExampler) []MarshallableHclExample {
+	var m []MarshallableHclExample
+	for _, e := range examples {
+		switch e := e.(type) {
+		case LocalFileHclExample:
+			m = append(m, MarshallableHclExample{Token: e.Token, Title: e.Title, RelativePath: e.RelativePath})
+		case *LocalFileHclExample:
+			m = append(m, MarshallableHclExample{Token: e.Token, Title: e.Title, RelativePath: e.RelativePath})
+		case InlineHclExample:
+			m = append(m, MarshallableHclExample{Token: e.Token, Title: e.Title, Contents: e.Contents})
+		case *InlineHclExample:
+			m = append(m, MarshallableHclExample{Token: e.Token, Title: e.Title, Contents: e.Contents})
+		}
+	}
+	return m
+}
+
+func unmarshalHclExamples(m []MarshallableHclExample) []HclExampler {
+	var examples []HclExampler
+	for _, e := range m {
+		if e.RelativePath != "" {
+			examples = append(examples, LocalFileHclExample{Token: e.Token, Title: e.Title, RelativePath: e.RelativePath})
+		} else {
+			examples = append(examples, InlineHclExample{Token: e.Token, Title: e.Title, Contents: e.Contents})
+		}
+	}
+	return examples
+}
+
 // MarshallableProviderInfo is the JSON-marshallable form of a Pulumi ProviderInfo value.
 type MarshallableProviderInfo struct {
-	Provider          *MarshallableProvider                  `json:"provider"`
-	Name              string                                 `json:"name"`
-	Version           string                                 `json:"version"`
-	Config            map[string]*MarshallableSchemaInfo     `json:"config,omitempty"`
-	Resources         map[string]*MarshallableResourceInfo   `json:"resources,omitempty"`
-	DataSources       map[string]*MarshallableDataSourceInfo `json:"dataSources,omitempty"`
-	TFProviderVersion string                                 `json:"tfProviderVersion,omitempty"`
+	// FormatVersion is the version of this encoding. See ProviderInfoFormatVersion.
+	FormatVersion int `json:"formatVersion,omitempty"`
+
+	Provider                 *MarshallableProvider                  `json:"provider"`
+	Name                     string                                 `json:"name"`
+	ResourcePrefix           string                                 `json:"resourcePrefix,omitempty"`
+	GitHubOrg                string                                 `json:"gitHubOrg,omitempty"`
+	GitHubHost               string                                 `json:"gitHubHost,omitempty"`
+	Description              string                                 `json:"description,omitempty"`
+	Keywords                 []string                               `json:"keywords,omitempty"`
+	License                  string                                 `json:"license,omitempty"`
+	LogoURL                  string                                 `json:"logoUrl,omitempty"`
+	DisplayName              string                                 `json:"displayName,omitempty"`
+	Publisher                string                                 `json:"publisher,omitempty"`
+	Homepage                 string                                 `json:"homepage,omitempty"`
+	Repository               string                                 `json:"repository,omitempty"`
+	Version                  string                                 `json:"version"`
+	Config                   map[string]*MarshallableSchemaInfo     `json:"config,omitempty"`
+	ExtraConfig              map[string]*MarshallableConfigInfo     `json:"extraConfig,omitempty"`
+	Resources                map[string]*MarshallableResourceInfo   `json:"resources,omitempty"`
+	DataSources              map[string]*MarshallableDataSourceInfo `json:"dataSources,omitempty"`
+	ExtraTypes               map[string]pschema.ComplexTypeSpec     `json:"extraTypes,omitempty"`
+	ExtraResourceHclExamples []MarshallableHclExample               `json:"extraResourceHclExamples,omitempty"`
+	ExtraFunctionHclExamples []MarshallableHclExample               `json:"extraFunctionHclExamples,omitempty"`
+	IgnoreMappings           []string                               `json:"ignoreMappings,omitempty"`
+	PluginDownloadURL        string                                 `json:"pluginDownloadURL,omitempty"`
+	JavaScript               *JavaScriptInfo                        `json:"javascript,omitempty"`
+	Python                   *PythonInfo                            `json:"python,omitempty"`
+	Golang                   *GolangInfo                            `json:"golang,omitempty"`
+	CSharp                   *CSharpInfo                            `json:"csharp,omitempty"`
+	TFProviderVersion        string                                 `json:"tfProviderVersion,omitempty"`
+	TFProviderLicense        *TFProviderLicense                     `json:"tfProviderLicense,omitempty"`
+	TFProviderModuleVersion  string                                 `json:"tfProviderModuleVersion,omitempty"`
+	PreConfigureCallback     *MarshallableHook                      `json:"preConfigureCallback,omitempty"`
+	DocsEdits                []MarshallableDocsEdit                 `json:"docsEdits,omitempty"`
+	EmitReplaceOnChanges     bool                                   `json:"emitReplaceOnChanges,omitempty"`
+	ProviderMeta             map[string]interface{}                 `json:"providerMeta,omitempty"`
+}
+
+// UnmarshalJSON decodes a MarshallableProviderInfo, rejecting encodings newer than ProviderInfoFormatVersion.
+func (m *MarshallableProviderInfo) UnmarshalJSON(b []byte) error {
+	type plain MarshallableProviderInfo
+	var v plain
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v.FormatVersion > ProviderInfoFormatVersion {
+		return fmt.Errorf("unsupported provider info format version %d; the newest supported version is %d",
+			v.FormatVersion, ProviderInfoFormatVersion)
+	}
+	*m = MarshallableProviderInfo(v)
+	return nil
 }
 
 // MarshalProviderInfo converts a Pulumi ProviderInfo value into a MarshallableProviderInfo value.
 func MarshalProviderInfo(p *ProviderInfo) *MarshallableProviderInfo {
-	config := make(map[string]*MarshallableSchemaInfo)
-	for k, v := range p.Config {
-		config[k] = MarshalSchemaInfo(v)
+	var extraConfig map[string]*MarshallableConfigInfo
+	if p.ExtraConfig != nil {
+		extraConfig = make(map[string]*MarshallableConfigInfo)
+		for k, v := range p.ExtraConfig {
+			extraConfig[k] = MarshalConfigInfo(v)
+		}
 	}
-	resources := make(map[string]*MarshallableResourceInfo)
-	for k, v := range p.Resources {
-		resources[k] = MarshalResourceInfo(v)
+	var resources map[string]*MarshallableResourceInfo
+	if p.Resources != nil {
+		resources = make(map[string]*MarshallableResourceInfo)
+		for k, v := range p.Resources {
+			resources[k] = MarshalResourceInfo(v)
+		}
 	}
-	dataSources := make(map[string]*MarshallableDataSourceInfo)
-	for k, v := range p.DataSources {
-		dataSources[k] = MarshalDataSourceInfo(v)
+	var dataSources map[string]*MarshallableDataSourceInfo
+	if p.DataSources != nil {
+		dataSources = make(map[string]*MarshallableDataSourceInfo)
+		for k, v := range p.DataSources {
+			dataSources[k] = MarshalDataSourceInfo(v)
+		}
 	}
 
 	info := MarshallableProviderInfo{
-		Provider:          MarshalProvider(p.P),
-		Name:              p.Name,
-		Version:           p.Version,
-		Config:            config,
-		Resources:         resources,
-		DataSources:       dataSources,
-		TFProviderVersion: p.TFProviderVersion,
+		FormatVersion:            ProviderInfoFormatVersion,
+		Provider:                 MarshalProvider(p.P),
+		Name:                     p.Name,
+		ResourcePrefix:           p.ResourcePrefix,
+		GitHubOrg:                p.GitHubOrg,
+		GitHubHost:               p.GitHubHost,
+		Description:              p.Description,
+		Keywords:                 p.Keywords,
+		License:                  p.License,
+		LogoURL:                  p.LogoURL,
+		DisplayName:              p.DisplayName,
+		Publisher:                p.Publisher,
+		Homepage:                 p.Homepage,
+		Repository:               p.Repository,
+		Version:                  p.Version,
+		Config:                   marshalSchemaInfos(p.Config),
+		ExtraConfig:              extraConfig,
+		Resources:                resources,
+		DataSources:              dataSources,
+		ExtraTypes:               p.ExtraTypes,
+		ExtraResourceHclExamples: marshalHclExamples(p.ExtraResourceHclExamples),
+		ExtraFunctionHclExamples: marshalHclExamples(p.ExtraFunctionHclExamples),
+		IgnoreMappings:           p.IgnoreMappings,
+		PluginDownloadURL:        p.PluginDownloadURL,
+		JavaScript:               p.JavaScript,
+		Python:                   p.Python,
+		Golang:                   p.Golang,
+		CSharp:                   p.CSharp,
+		TFProviderVersion:        p.TFProviderVersion,
+		TFProviderLicense:        p.TFProviderLicense,
+		TFProviderModuleVersion:  p.TFProviderModuleVersion,
+		PreConfigureCallback:     marshalHook(&preConfigureCallbacks, p.PreConfigureCallback),
+		DocsEdits:                marshalDocsEdits(p.DocsEdits),
+		EmitReplaceOnChanges:     p.EmitReplaceOnChanges,
+		ProviderMeta:             p.ProviderMeta,
 	}
 
 	return &info
@@ -721,27 +1272,63 @@ func MarshalProviderInfo(p *ProviderInfo) *MarshallableProviderInfo {
 
 // Unmarshal creates a mostly-=initialized Pulumi ProviderInfo value from the given MarshallableProviderInfo.
 func (m *MarshallableProviderInfo) Unmarshal() *ProviderInfo {
-	config := make(map[string]*SchemaInfo)
-	for k, v := range m.Config {
-		config[k] = v.Unmarshal()
+	var extraConfig map[string]*ConfigInfo
+	if m.ExtraConfig != nil {
+		extraConfig = make(map[string]*ConfigInfo)
+		for k, v := range m.ExtraConfig {
+			extraConfig[k] = v.Unmarshal()
+		}
 	}
-	resources := make(map[string]*ResourceInfo)
-	for k, v := range m.Resources {
-		resources[k] = v.Unmarshal()
+	var resources map[string]*ResourceInfo
+	if m.Resources != nil {
+		resources = make(map[string]*ResourceInfo)
+		for k, v := range m.Resources {
+			resources[k] = v.Unmarshal()
+		}
 	}
-	dataSources := make(map[string]*DataSourceInfo)
-	for k, v := range m.DataSources {
-		dataSources[k] = v.Unmarshal()
+	var dataSources map[string]*DataSourceInfo
+	if m.DataSources != nil {
+		dataSources = make(map[string]*DataSourceInfo)
+		for k, v := range m.DataSources {
+			dataSources[k] = v.Unmarshal()
+		}
 	}
 
 	info := ProviderInfo{
-		P:                 m.Provider.Unmarshal(),
-		Name:              m.Name,
-		Version:           m.Version,
-		Config:            config,
-		Resources:         resources,
-		DataSources:       dataSources,
-		TFProviderVersion: m.TFProviderVersion,
+		P:                        m.Provider.Unmarshal(),
+		Name:                     m.Name,
+		ResourcePrefix:           m.ResourcePrefix,
+		GitHubOrg:                m.GitHubOrg,
+		GitHubHost:               m.GitHubHost,
+		Description:              m.Description,
+		Keywords:                 m.Keywords,
+		License:                  m.License,
+		LogoURL:                  m.LogoURL,
+		DisplayName:              m.DisplayName,
+		Publisher:                m.Publisher,
+		Homepage:                 m.Homepage,
+		Repository:               m.Repository,
+		Version:                  m.Version,
+		Config:                   unmarshalSchemaInfos(m.Config),
+		ExtraConfig:              extraConfig,
+		Resources:                resources,
+		DataSources:              dataSources,
+		ExtraTypes:               m.ExtraTypes,
+		ExtraResourceHclExamples: unmarshalHclExamples(m.ExtraResourceHclExamples),
+		ExtraFunctionHclExamples: unmarshalHclExamples(m.ExtraFunctionHclExamples),
+		IgnoreMappings:           m.IgnoreMappings,
+		PluginDownloadURL:        m.PluginDownloadURL,
+		JavaScript:               m.JavaScript,
+		Python:                   m.Python,
+		Golang:                   m.Golang,
+		CSharp:                   m.CSharp,
+		TFProviderVersion:        m.TFProviderVersion,
+		TFProviderLicense:        m.TFProviderLicense,
+		TFProviderModuleVersion:  m.TFProviderModuleVersion,
+		PreConfigureCallback:     m.PreConfigureCallback.unmarshalPreConfigureCallback(),
+		DocsEdits:                unmarshalDocsEdits(m.DocsEdits),
+		EmitReplaceOnChanges:     m.EmitReplaceOnChanges,
+		ProviderMeta:             m.ProviderMeta,
 	}
 
 	return &info
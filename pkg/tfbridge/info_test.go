@@ -1,10 +1,22 @@
 package tfbridge
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 )
 
 func TestGetModuleMajorVersion(t *testing.T) {
@@ -62,3 +74,329 @@ func TestStringValue(t *testing.T) {
 	assert.Equal(t, "value1", StringValue(myMap, "key1"))
 	assert.Equal(t, "", StringValue(myMap, "keyThatDoesNotExist"))
 }
+
+func roundTripProviderInfo(t *testing.T, info *ProviderInfo) *ProviderInfo {
+	bytes, err := json.Marshal(MarshalProviderInfo(info))
+	require.NoError(t, err)
+
+	var m MarshallableProviderInfo
+	require.NoError(t, json.Unmarshal(bytes, &m))
+	return m.Unmarshal()
+}
+
+// TestProviderInfoRoundTrip fills in every field of a ProviderInfo and of the info types it refers to, and checks
+// that each of them survives a round-trip through MarshallableProviderInfo. A field that is added to one of these types
+// without a marshallable counterpart fails this test.
+func TestProviderInfoRoundTrip(t *testing.T) {
+	var info ProviderInfo
+	fillInfo(reflect.ValueOf(&info).Elem(), map[reflect.Type]bool{})
+	actual := roundTripProviderInfo(t, &info)
+	assertRoundTripped(t, "ProviderInfo", reflect.ValueOf(info), reflect.ValueOf(*actual))
+}
+
+var (
+	shimProviderType    = reflect.TypeOf((*shim.Provider)(nil)).Elem()
+	shimSchemaType      = reflect.TypeOf((*shim.Schema)(nil)).Elem()
+	hclExamplerType     = reflect.TypeOf((*HclExampler)(nil)).Elem()
+	regexpType          = reflect.TypeOf((*regexp.Regexp)(nil))
+	complexTypeSpecType = reflect.TypeOf(pschema.ComplexTypeSpec{})
+	testSchema          = (&schema.Schema{Type: shim.TypeString, Optional: true, Description: "A string."}).Shim()
+	testComplexTypeSpec = pschema.ComplexTypeSpec{
+		ObjectTypeSpec: pschema.ObjectTypeSpec{Type: "string"},
+		Enum:           []pschema.EnumValueSpec{{Value: "value"}},
+	}
+)
+
+// fillInfo sets every exported field reachable from v to a non-zero value. Types that refer to themselves are only
+// filled in once along each path.
+func fillInfo(v reflect.Value, filling map[reflect.Type]bool) {
+	switch v.Type() {
+	case shimProviderType:
+		v.Set(reflect.ValueOf((&schema.Provider{
+			Schema:       schema.SchemaMap{"region": testSchema},
+			ResourcesMap: schema.ResourceMap{"example_server": (&schema.Resource{Schema: schema.SchemaMap{}}).Shim()},
+			DataSourcesMap: schema.ResourceMap{
+				"example_image": (&schema.Resource{Schema: schema.SchemaMap{"name": testSchema}}).Shim(),
+			},
+		}).Shim()))
+		return
+	case shimSchemaType:
+		v.Set(reflect.ValueOf(testSchema))
+		return
+	case hclExamplerType:
+		v.Set(reflect.ValueOf(InlineHclExample{Token: "token", Title: "title", Contents: "contents"}))
+		return
+	case regexpType:
+		v.Set(reflect.ValueOf(regexp.MustCompile("pattern+")))
+		return
+	case complexTypeSpecType:
+		v.Set(reflect.ValueOf(testComplexTypeSpec))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("value")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf("value"))
+	case reflect.Func:
+		v.Set(reflect.MakeFunc(v.Type(), func([]reflect.Value) []reflect.Value {
+			results := make([]reflect.Value, v.Type().NumOut())
+			for i := range results {
+				results[i] = reflect.Zero(v.Type().Out(i))
+			}
+			return results
+		}))
+	case reflect.Ptr:
+		if filling[v.Type().Elem()] {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		fillInfo(v.Elem(), filling)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillInfo(v.Index(0), filling)
+	case reflect.Map:
+		key, elem := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+		fillInfo(key, filling)
+		fillInfo(elem, filling)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, elem)
+	case reflect.Struct:
+		filling[v.Type()] = true
+		defer delete(filling, v.Type())
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				fillInfo(v.Field(i), filling)
+			}
+		}
+	}
+}
+
+// assertRoundTripped checks that actual, the round-tripped form of expected, holds the same values. Hooks cannot be
+// compared and are only required to be present, and Terraform schemas are compared by their marshallable forms as
+// the unmarshaled schemas are schema-only.
+func assertRoundTripped(t *testing.T, path string, expected, actual reflect.Value) {
+	switch expected.Type() {
+	case shimProviderType:
+		assert.Equal(t, MarshalProvider(expected.Interface().(shim.Provider)),
+			MarshalProvider(actual.Interface().(shim.Provider)), path)
+		return
+	case shimSchemaType:
+		assert.Equal(t, MarshalSchema(expected.Interface().(shim.Schema)),
+			MarshalSchema(actual.Interface().(shim.Schema)), path)
+		return
+	}
+
+	switch expected.Kind() {
+	case reflect.Func:
+		assert.Equal(t, expected.IsNil(), actual.IsNil(), path)
+	case reflect.Ptr:
+		if assert.Equal(t, expected.IsNil(), actual.IsNil(), path) && !expected.IsNil() {
+			assertRoundTripped(t, path, expected.Elem(), actual.Elem())
+		}
+	case reflect.Slice:
+		if assert.Equal(t, expected.Len(), actual.Len(), path) {
+			for i := 0; i < expected.Len(); i++ {
+				assertRoundTripped(t, fmt.Sprintf("%s[%d]", path, i), expected.Index(i), actual.Index(i))
+			}
+		}
+	case reflect.Map:
+		if assert.Equal(t, expected.Len(), actual.Len(), path) {
+			for _, k := range expected.MapKeys() {
+				elem := actual.MapIndex(k)
+				if assert.True(t, elem.IsValid(), "%s[%v]", path, k) {
+					assertRoundTripped(t, fmt.Sprintf("%s[%v]", path, k), expected.MapIndex(k), elem)
+				}
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < expected.NumField(); i++ {
+			if f := expected.Type().Field(i); f.PkgPath == "" {
+				assertRoundTripped(t, path+"."+f.Name, expected.Field(i), actual.Field(i))
+			}
+		}
+	default:
+		assert.Equal(t, expected.Interface(), actual.Interface(), path)
+	}
+}
+
+func TestProviderInfoHooks(t *testing.T) {
+	upper := func(v resource.PropertyValue) (resource.PropertyValue, error) {
+		return resource.NewStringProperty(strings.ToUpper(v.StringValue())), nil
+	}
+	preConfigure := func(resource.PropertyMap, shim.ResourceConfig) error {
+		return errors.New("preconfigured")
+	}
+	RegisterTransformer("test:upper", upper)
+	RegisterPreConfigureCallback("test:preConfigure", preConfigure)
+
+	unregistered := func(v resource.PropertyValue) (resource.PropertyValue, error) { return v, nil }
+	info := &ProviderInfo{
+		Name: "example",
+		Config: map[string]*SchemaInfo{
+			"registered":   {Transform: upper},
+			"unregistered": {Transform: unregistered},
+			"none":         {},
+			"defaultFunc": {Default: &DefaultInfo{From: func(*PulumiResource) (interface{}, error) {
+				return "default", nil
+			}}},
+		},
+		PreConfigureCallback: preConfigure,
+	}
+
+	m := MarshalProviderInfo(info)
+	assert.Equal(t, &MarshallableHook{Name: "test:upper"}, m.Config["registered"].Transform)
+	assert.Equal(t, &MarshallableHook{}, m.Config["unregistered"].Transform)
+	assert.Nil(t, m.Config["none"].Transform)
+	assert.Equal(t, &MarshallableHook{Name: "test:preConfigure"}, m.PreConfigureCallback)
+
+	actual := roundTripProviderInfo(t, info)
+
+	v, err := actual.Config["registered"].Transform(resource.NewStringProperty("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewStringProperty("ABC"), v)
+
+	_, err = actual.Config["unregistered"].Transform(resource.NewStringProperty("abc"))
+	assert.EqualError(t, err, "unnamed transformer cannot be run after unmarshaling")
+
+	assert.Nil(t, actual.Config["none"].Transform)
+
+	_, err = actual.Config["defaultFunc"].Default.From(&PulumiResource{})
+	assert.EqualError(t, err, "unnamed default func cannot be run after unmarshaling")
+
+	assert.EqualError(t, actual.PreConfigureCallback(nil, nil), "preconfigured")
+
+	// Names that are not registered in this process resolve to hooks that fail when run.
+	var missing MarshallableSchemaInfo
+	require.NoError(t, json.Unmarshal([]byte(`{"transform": {"name": "test:missing"}}`), &missing))
+	_, err = missing.Unmarshal().Transform(resource.NewStringProperty("abc"))
+	assert.EqualError(t, err, `transformer "test:missing" is not registered`)
+}
+
+func TestHooksAreMatchedByIdentity(t *testing.T) {
+	prefix := func(p string) Transformer {
+		return func(v resource.PropertyValue) (resource.PropertyValue, error) {
+			return resource.NewStringProperty(p + v.StringValue()), nil
+		}
+	}
+	registered, unregistered := prefix("a-"), prefix("b-")
+	RegisterTransformer("test:prefixA", registered)
+
+	// Closures created by the same function literal share their code, but only the registered one is named.
+	assert.Equal(t, &MarshallableHook{Name: "test:prefixA"}, marshalHook(&transformers, registered))
+	assert.Equal(t, &MarshallableHook{}, marshalHook(&transformers, unregistered))
+
+	// A copy of a registered hook is still that hook.
+	copied := registered
+	assert.Equal(t, &MarshallableHook{Name: "test:prefixA"}, marshalHook(&transformers, copied))
+
+	// A hook that was registered under more than one name is ambiguous.
+	twice := prefix("c-")
+	RegisterTransformer("test:prefixC1", twice)
+	RegisterTransformer("test:prefixC2", twice)
+	assert.Equal(t, &MarshallableHook{}, marshalHook(&transformers, twice))
+}
+
+func TestDefaultValueTypes(t *testing.T) {
+	info := &ProviderInfo{
+		Name: "example",
+		P: (&schema.Provider{
+			Schema: schema.SchemaMap{
+				"retries": (&schema.Schema{Type: shim.TypeInt, Optional: true, Default: 3}).Shim(),
+				"ratio":   (&schema.Schema{Type: shim.TypeFloat, Optional: true, Default: 0.5}).Shim(),
+			},
+			ResourcesMap:   schema.ResourceMap{},
+			DataSourcesMap: schema.ResourceMap{},
+		}).Shim(),
+		Config: map[string]*SchemaInfo{
+			"retries": {Default: &DefaultInfo{Value: 3}},
+			"timeout": {Default: &DefaultInfo{Value: int64(30)}},
+			"ratio":   {Default: &DefaultInfo{Value: 0.5}},
+			"region":  {Default: &DefaultInfo{Value: "us-west-2"}},
+		},
+	}
+
+	actual := roundTripProviderInfo(t, info)
+	assert.Equal(t, 3, actual.Config["retries"].Default.Value)
+	assert.Equal(t, int64(30), actual.Config["timeout"].Default.Value)
+	assert.Equal(t, 0.5, actual.Config["ratio"].Default.Value)
+	assert.Equal(t, "us-west-2", actual.Config["region"].Default.Value)
+	assert.Equal(t, 3, actual.P.Schema().Get("retries").Default())
+	assert.Equal(t, 0.5, actual.P.Schema().Get("ratio").Default())
+}
+
+func TestMarshalNilConfigInfo(t *testing.T) {
+	assert.Nil(t, MarshalConfigInfo(nil))
+	assert.Nil(t, (*MarshallableConfigInfo)(nil).Unmarshal())
+
+	actual := roundTripProviderInfo(t, &ProviderInfo{Name: "example", ExtraConfig: map[string]*ConfigInfo{"nil": nil}})
+	assert.Equal(t, map[string]*ConfigInfo{"nil": nil}, actual.ExtraConfig)
+}
+
+func TestDocsEditHooks(t *testing.T) {
+	patch := func(fileName string, markdown []byte) ([]byte, error) {
+		return append(markdown, []byte(fileName)...), nil
+	}
+	RegisterDocsPatch("test:patch", patch)
+
+	info := &ProviderInfo{
+		Name: "example",
+		DocsEdits: []DocsEdit{
+			{Patch: patch},
+			{Patch: func(string, []byte) ([]byte, error) { return nil, nil }},
+		},
+	}
+
+	m := MarshalProviderInfo(info)
+	assert.Equal(t, &MarshallableHook{Name: "test:patch"}, m.DocsEdits[0].Patch)
+	assert.Equal(t, &MarshallableHook{}, m.DocsEdits[1].Patch)
+
+	actual := roundTripProviderInfo(t, info)
+	require.Len(t, actual.DocsEdits, 2)
+
+	patched, err := actual.DocsEdits[0].Patch("a.md", []byte("# "))
+	require.NoError(t, err)
+	assert.Equal(t, "# a.md", string(patched))
+
+	_, err = actual.DocsEdits[1].Patch("a.md", nil)
+	assert.EqualError(t, err, "unnamed docs patch cannot be run after unmarshaling")
+
+	// Patterns that do not compile are rejected when decoding.
+	var invalid MarshallableDocsEdit
+	err = json.Unmarshal([]byte(`{"regexp": "("}`), &invalid)
+	assert.Error(t, err)
+}
+
+func TestProviderInfoFormatVersion(t *testing.T) {
+	m := MarshalProviderInfo(&ProviderInfo{Name: "example"})
+	assert.Equal(t, ProviderInfoFormatVersion, m.FormatVersion)
+
+	// Version 1 encodings have no version, wrote type overrides under "typeomitempty" and only recorded the presence
+	// of default functions.
+	var legacy MarshallableProviderInfo
+	err := json.Unmarshal([]byte(`{
+		"provider": null,
+		"name": "example",
+		"version": "1.0.0",
+		"config": {
+			"region": {"typeomitempty": "example:index/Region:Region", "default": {"isFunc": true}}
+		}
+	}`), &legacy)
+	require.NoError(t, err)
+	info := legacy.Unmarshal()
+	assert.Equal(t, tokens.Type("example:index/Region:Region"), info.Config["region"].Type)
+	require.NotNil(t, info.Config["region"].Default.From)
+
+	var future MarshallableProviderInfo
+	err = json.Unmarshal([]byte(`{"formatVersion": 3, "name": "example"}`), &future)
+	assert.EqualError(t, err, "unsupported provider info format version 3; the newest supported version is 2")
+}
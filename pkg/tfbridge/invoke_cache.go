@@ -0,0 +1,88 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// invokeCache memoizes the results of data source invocations for data sources that opt in with
+// DataSourceInfo.CacheTTL. Entries are keyed by the data source token and its normalized Terraform inputs.
+type invokeCache struct {
+	m       sync.Mutex
+	entries map[string]invokeCacheEntry
+	now     func() time.Time // the clock used to expire entries; time.Now if nil.
+}
+
+type invokeCacheEntry struct {
+	resp    *pulumirpc.InvokeResponse
+	expires time.Time
+}
+
+// invokeCacheKey returns the cache key for an invocation of the given data source with the given Terraform inputs.
+// The inputs must not contain unknowns.
+func invokeCacheKey(tok tokens.ModuleMember, inputs map[string]interface{}) (string, error) {
+	// encoding/json sorts map keys, so equal inputs always produce the same key.
+	args, err := json.Marshal(inputs)
+	if err != nil {
+		return "", err
+	}
+	return string(tok) + "\x00" + string(args), nil
+}
+
+func (c *invokeCache) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// get returns the cached response for the given key, if there is one and it has not expired.
+func (c *invokeCache) get(key string) (*pulumirpc.InvokeResponse, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.clock().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.resp, true
+}
+
+// put caches a response for the given key for the given duration, and evicts any entries that have expired.
+func (c *invokeCache) put(key string, resp *pulumirpc.InvokeResponse, ttl time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	now := c.clock()
+	if c.entries == nil {
+		c.entries = map[string]invokeCacheEntry{}
+	}
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = invokeCacheEntry{resp: resp, expires: now.Add(ttl)}
+}
@@ -0,0 +1,73 @@
+package tfbridge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+// countingProvider counts the data source reads performed by the underlying provider.
+type countingProvider struct {
+	shim.Provider
+	reads int
+}
+
+func (p *countingProvider) ReadDataApply(t string,
+	d shim.InstanceDiff) (shim.InstanceState, diagnostics.Diagnostics, error) {
+
+	p.reads++
+	return p.Provider.ReadDataApply(t, d)
+}
+
+func TestInvokeCache(t *testing.T) {
+	tf := &countingProvider{Provider: shimv2.NewProvider(testTFProviderV2)}
+	provider := &Provider{
+		tf:     tf,
+		config: shimv2.NewSchemaMap(testTFProviderV2.Schema),
+	}
+	provider.dataSources = map[tokens.ModuleMember]DataSource{
+		"tprov:index/ExampleFn:ExampleFn": {
+			TF:     shimv2.NewResource(testTFProviderV2.DataSourcesMap["example_resource"]),
+			TFName: "example_resource",
+			Schema: &DataSourceInfo{Tok: "tprov:index/ExampleFn:ExampleFn", CacheTTL: time.Minute},
+		},
+	}
+	now := time.Now()
+	provider.invokeCache.now = func() time.Time { return now }
+
+	invoke := func(props resource.PropertyMap) {
+		args, err := plugin.MarshalProperties(props, plugin.MarshalOptions{KeepUnknowns: true})
+		assert.NoError(t, err)
+		resp, err := provider.Invoke(context.Background(), &pulumirpc.InvokeRequest{
+			Tok:  "tprov:index/ExampleFn:ExampleFn",
+			Args: args,
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, resp.GetFailures())
+	}
+	array := resource.NewArrayProperty([]resource.PropertyValue{resource.NewStringProperty("foo")})
+
+	// Identical arguments are only read once.
+	invoke(resource.PropertyMap{"arrayPropertyValues": array})
+	invoke(resource.PropertyMap{"arrayPropertyValues": array})
+	assert.Equal(t, 1, tf.reads)
+
+	// Different arguments are read separately.
+	invoke(resource.PropertyMap{"arrayPropertyValues": array, "stringPropertyValue": resource.NewStringProperty("x")})
+	assert.Equal(t, 2, tf.reads)
+
+	// Expired entries are read again.
+	now = now.Add(2 * time.Minute)
+	invoke(resource.PropertyMap{"arrayPropertyValues": array})
+	assert.Equal(t, 3, tf.reads)
+}
@@ -39,6 +39,7 @@ func Main(pkg string, version string, prov ProviderInfo, pulumiSchema []byte) {
 
 	dumpInfo := flags.Bool("get-provider-info", false, "dump provider info as JSON to stdout")
 	providerVersion := flags.Bool("version", false, "get built provider version")
+	replayLog := flags.String("replay-grpc-log", "", "replay a recorded gRPC log and report mismatched responses")
 
 	err := flags.Parse(os.Args[1:])
 	contract.IgnoreError(err)
@@ -64,6 +65,13 @@ func Main(pkg string, version string, prov ProviderInfo, pulumiSchema []byte) {
 		os.Exit(0)
 	}
 
+	if *replayLog != "" {
+		if err := replayGrpcLogFile(*replayLog, pkg, version, prov, pulumiSchema, cmdutil.Diag()); err != nil {
+			cmdutil.ExitError(err.Error())
+		}
+		os.Exit(0)
+	}
+
 	// Initialize Terraform logging.
 	prov.P.InitLogging()
 
@@ -59,6 +59,7 @@ type Provider struct {
 	dataSources     map[tokens.ModuleMember]DataSource // a map of Pulumi module tokens to data sources.
 	supportsSecrets bool                               // true if the engine supports secret property values
 	pulumiSchema    []byte                             // the JSON-encoded Pulumi schema.
+	invokeCache     invokeCache                        // memoized results of data source invocations.
 }
 
 // Resource wraps both the Terraform resource type info plus the overlay resource info.
@@ -183,6 +184,14 @@ func (p *Provider) setLoggingContext(ctx context.Context) {
 	}
 }
 
+// logDebug writes a debug message to the engine's log, if the provider is connected to an engine.
+func (p *Provider) logDebug(ctx context.Context, msg string) {
+	glog.V(9).Info(msg)
+	if p.host != nil {
+		contract.IgnoreError(p.host.Log(ctx, diag.Debug, "", msg))
+	}
+}
+
 func (p *Provider) label() string {
 	return fmt.Sprintf("tf.Provider[%s]", p.module)
 }
@@ -195,12 +204,10 @@ func (p *Provider) initResourceMaps() {
 		var tok tokens.Type
 
 		// See if there is override information for this resource.  If yes, use that to decode the token.
-		var schema *ResourceInfo
-		if p.info.Resources != nil {
-			schema = p.info.Resources[name]
-			if schema != nil {
-				tok = schema.Tok
-			}
+		// Resources without override information get an empty one, so that Schema is never nil.
+		schema := &ResourceInfo{}
+		if info := p.info.Resources[name]; info != nil {
+			schema, tok = info, info.Tok
 		}
 
 		// Otherwise, we default to the standard naming scheme.
@@ -225,12 +232,10 @@ func (p *Provider) initResourceMaps() {
 		var tok tokens.ModuleMember
 
 		// See if there is override information for this resource.  If yes, use that to decode the token.
-		var schema *DataSourceInfo
-		if p.info.DataSources != nil {
-			schema = p.info.DataSources[name]
-			if schema != nil {
-				tok = schema.Tok
-			}
+		// Data sources without override information get an empty one, so that Schema is never nil.
+		schema := &DataSourceInfo{}
+		if info := p.info.DataSources[name]; info != nil {
+			schema, tok = info, info.Tok
 		}
 
 		// Otherwise, we default to the standard naming scheme.
@@ -389,10 +394,8 @@ func validateProviderConfig(ctx context.Context, p *Provider, config shim.Resour
 
 	// Perform validation of the config state so we can offer nice errors.
 	warns, errs := p.tf.Validate(config)
-	for _, warn := range warns {
-		if err := p.host.Log(ctx, diag.Warning, "", fmt.Sprintf("provider config warning: %v", warn)); err != nil {
-			return nil, err
-		}
+	if err := p.logWarnings(ctx, "", "provider config warning", warns, nil); err != nil {
+		return nil, err
 	}
 
 	if len(errs) > 0 {
@@ -545,8 +548,24 @@ func (p *Provider) Configure(ctx context.Context,
 		return nil, validationErrors
 	}
 
+	if p.info.ProviderMeta != nil {
+		tf, ok := p.tf.(shim.ProviderWithMeta)
+		if !ok {
+			return nil, errors.Errorf("provider %s does not accept provider_meta", p.info.Name)
+		}
+		if err = tf.SetProviderMeta(p.info.ProviderMeta); err != nil {
+			return nil, errors.Wrap(err, "could not set provider_meta")
+		}
+	}
+
 	// Now actually attempt to do the configuring and return its resulting error (if any).
-	if err = p.tf.Configure(config); err != nil {
+	span := p.startTFSpan(ctx, "Configure", p.info.Name)
+	warns, err := p.tf.Configure(config)
+	endSpan(span, err)
+	if logErr := p.logWarnings(ctx, "", "provider config warning", warns, nil); logErr != nil {
+		return nil, logErr
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -561,18 +580,13 @@ var requiredFieldRegex = regexp.MustCompile("\"(.*?)\": required field is not se
 
 func (p *Provider) formatFailureReason(tokenType tokens.Type, res Resource, err error) string {
 	reason := err.Error()
-	var attributePath string
-	var d *diagnostics.ValidationError
+	var d *diagnostics.Diagnostic
 	if errors.As(err, &d) {
-		path := d.AttributePath
-		if len(path) > 0 {
-			attrPath := pathToAttributePath(path, tokenType, res)
-			attributePath = strings.Join(attrPath, "")
-		}
+		reason = formatDiagnostic(*d, resourceAttributePath(tokenType, res))
 	}
 
 	// Translate the name in missing-required-field error from TF to Pulumi naming scheme
-	parts := requiredFieldRegex.FindStringSubmatch(reason)
+	parts := requiredFieldRegex.FindStringSubmatch(err.Error())
 	if len(parts) == 2 {
 		schema := getSchema(res.TF.Schema(), parts[1])
 		info := res.Schema.Fields[parts[1]]
@@ -592,16 +606,13 @@ func (p *Provider) formatFailureReason(tokenType tokens.Type, res Resource, err
 		}
 	}
 
-	if attributePath != "" {
-		reason += fmt.Sprintf(". Examine values at '%s'.", attributePath)
-	}
 	return reason
 }
 
-// pathToAttributePath takes a cty.Path and translates it to a path compatible with the Pulumi schema.
-func pathToAttributePath(p cty.Path, tokenType tokens.Type, res Resource) []string {
-	res.Schema.GetTok()
-	ap := []string{tokenType.Name().String()}
+// pathToAttributePath takes a cty.Path and translates it to a path compatible with the Pulumi schema. The path is
+// rooted at the given name, and is resolved against the given Terraform schema and overlaid field info.
+func pathToAttributePath(p cty.Path, name string, schemaMap shim.SchemaMap, fields map[string]*SchemaInfo) []string {
+	ap := []string{name}
 	var schema shim.Schema
 	var info *SchemaInfo
 	for _, step := range p {
@@ -609,8 +620,8 @@ func pathToAttributePath(p cty.Path, tokenType tokens.Type, res Resource) []stri
 		case cty.GetAttrStep:
 			ap = append(ap, ".")
 			if schema == nil {
-				schema = getSchema(res.TF.Schema(), selector.Name)
-				info = res.Schema.Fields[selector.Name]
+				schema = getSchema(schemaMap, selector.Name)
+				info = fields[selector.Name]
 			} else {
 				schema, info = elemSchemas(schema, info)
 			}
@@ -675,8 +686,10 @@ func (p *Provider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (*pul
 	// Now fetch the default values so that (a) we can return them to the caller and (b) so that validation
 	// includes the default values.  Otherwise, the provider wouldn't be presented with its own defaults.
 	tfname := res.TFName
+	span := p.startConversionSpan(ctx, "MakeTerraformInputs", tfname, tracingURNKey.String(string(urn)))
 	inputs, assets, err := MakeTerraformInputs(
 		&PulumiResource{URN: urn, Properties: news}, p.configValues, olds, news, res.TF.Schema(), res.Schema.Fields)
+	endSpan(span, err)
 	if err != nil {
 		return nil, err
 	}
@@ -684,10 +697,9 @@ func (p *Provider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (*pul
 	// Now check with the resource provider to see if the values pass muster.
 	rescfg := MakeTerraformConfigFromInputs(p.tf, inputs)
 	warns, errs := p.tf.ValidateResource(tfname, rescfg)
-	for _, warn := range warns {
-		if err = p.host.Log(ctx, diag.Warning, urn, fmt.Sprintf("%v verification warning: %v", urn, warn)); err != nil {
-			return nil, err
-		}
+	if err = p.logWarnings(ctx, urn, fmt.Sprintf("%v verification warning", urn), warns,
+		resourceAttributePath(t, res)); err != nil {
+		return nil, err
 	}
 
 	// Now produce a return value of any properties that failed verification.
@@ -743,7 +755,13 @@ func (p *Provider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulum
 		return nil, errors.Wrapf(err, "preparing %s's new property state", urn)
 	}
 
-	diff, err := p.tf.Diff(res.TFName, state, config)
+	span := p.startTFSpan(ctx, "Diff", res.TFName, tracingURNKey.String(string(urn)))
+	diff, warns, err := p.tf.Diff(res.TFName, state, config)
+	endSpan(span, err)
+	if logErr := p.logWarnings(ctx, urn, fmt.Sprintf("%v diff warning", urn), warns,
+		resourceAttributePath(t, res)); logErr != nil {
+		return nil, logErr
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "diffing %s", urn)
 	}
@@ -831,7 +849,13 @@ func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*p
 		return nil, errors.Wrapf(err, "preparing %s's new property state", urn)
 	}
 
-	diff, err := p.tf.Diff(res.TFName, nil, config)
+	span := p.startTFSpan(ctx, "Diff", res.TFName, tracingURNKey.String(string(urn)))
+	diff, warns, err := p.tf.Diff(res.TFName, nil, config)
+	endSpan(span, err)
+	if logErr := p.logWarnings(ctx, urn, fmt.Sprintf("%v diff warning", urn), warns,
+		resourceAttributePath(t, res)); logErr != nil {
+		return nil, logErr
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "diffing %s", urn)
 	}
@@ -853,7 +877,13 @@ func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*p
 	var newstate shim.InstanceState
 	var reasons []string
 	if !req.GetPreview() {
-		newstate, err = p.tf.Apply(res.TFName, nil, diff)
+		span := p.startTFSpan(ctx, "Apply", res.TFName, tracingURNKey.String(string(urn)))
+		newstate, warns, err = p.tf.Apply(res.TFName, nil, diff)
+		endSpan(span, err)
+		if logErr := p.logWarnings(ctx, urn, fmt.Sprintf("%v create warning", urn), warns,
+			resourceAttributePath(t, res)); logErr != nil {
+			return nil, logErr
+		}
 		if newstate == nil {
 			if err == nil {
 				return nil, fmt.Errorf("expected non-nil error with nil state during Create of %s", urn)
@@ -875,7 +905,9 @@ func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*p
 	}
 
 	// Create the ID and property maps and return them.
+	span = p.startConversionSpan(ctx, "MakeTerraformResult", res.TFName, tracingURNKey.String(string(urn)))
 	props, err := MakeTerraformResult(p.tf, newstate, res.TF.Schema(), res.Schema.Fields, assets, p.supportsSecrets)
+	endSpan(span, err)
 	if err != nil {
 		reasons = append(reasons, errors.Wrapf(err, "converting result for %s", urn).Error())
 	}
@@ -938,7 +970,13 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 		}
 	}
 
-	newstate, err := p.tf.Refresh(res.TFName, state)
+	span := p.startTFSpan(ctx, "Refresh", res.TFName, tracingURNKey.String(string(urn)))
+	newstate, warns, err := p.tf.Refresh(res.TFName, state)
+	endSpan(span, err)
+	if logErr := p.logWarnings(ctx, urn, fmt.Sprintf("%v refresh warning", urn), warns,
+		resourceAttributePath(t, res)); logErr != nil {
+		return nil, logErr
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "refreshing %s", urn)
 	}
@@ -946,7 +984,9 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 	// Store the ID and properties in the output.  The ID *should* be the same as the input ID, but in the case
 	// that the resource no longer exists, we will simply return the empty string and an empty property map.
 	if newstate != nil {
+		span := p.startConversionSpan(ctx, "MakeTerraformResult", res.TFName, tracingURNKey.String(string(urn)))
 		props, err := MakeTerraformResult(p.tf, newstate, res.TF.Schema(), res.Schema.Fields, nil, p.supportsSecrets)
+		endSpan(span, err)
 		if err != nil {
 			return nil, err
 		}
@@ -1013,7 +1053,13 @@ func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*p
 		return nil, errors.Wrapf(err, "preparing %s's new property state", urn)
 	}
 
-	diff, err := p.tf.Diff(res.TFName, state, config)
+	span := p.startTFSpan(ctx, "Diff", res.TFName, tracingURNKey.String(string(urn)))
+	diff, warns, err := p.tf.Diff(res.TFName, state, config)
+	endSpan(span, err)
+	if logErr := p.logWarnings(ctx, urn, fmt.Sprintf("%v diff warning", urn), warns,
+		resourceAttributePath(t, res)); logErr != nil {
+		return nil, logErr
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "diffing %s", urn)
 	}
@@ -1039,7 +1085,13 @@ func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*p
 	var newstate shim.InstanceState
 	var reasons []string
 	if !req.GetPreview() {
-		newstate, err = p.tf.Apply(res.TFName, state, diff)
+		span := p.startTFSpan(ctx, "Apply", res.TFName, tracingURNKey.String(string(urn)))
+		newstate, warns, err = p.tf.Apply(res.TFName, state, diff)
+		endSpan(span, err)
+		if logErr := p.logWarnings(ctx, urn, fmt.Sprintf("%v update warning", urn), warns,
+			resourceAttributePath(t, res)); logErr != nil {
+			return nil, logErr
+		}
 		if newstate == nil {
 			if err != nil {
 				return nil, err
@@ -1062,7 +1114,9 @@ func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*p
 		}
 	}
 
+	span = p.startConversionSpan(ctx, "MakeTerraformResult", res.TFName, tracingURNKey.String(string(urn)))
 	props, err := MakeTerraformResult(p.tf, newstate, res.TF.Schema(), res.Schema.Fields, assets, p.supportsSecrets)
+	endSpan(span, err)
 	if err != nil {
 		reasons = append(reasons, errors.Wrapf(err, "converting result for %s", urn).Error())
 	}
@@ -1106,7 +1160,14 @@ func (p *Provider) Delete(ctx context.Context, req *pulumirpc.DeleteRequest) (*p
 		diff.SetTimeout(req.Timeout, shim.TimeoutDelete)
 	}
 
-	if _, err := p.tf.Apply(res.TFName, state, diff); err != nil {
+	span := p.startTFSpan(ctx, "Apply", res.TFName, tracingURNKey.String(string(urn)))
+	_, warns, err := p.tf.Apply(res.TFName, state, diff)
+	endSpan(span, err)
+	if logErr := p.logWarnings(ctx, urn, fmt.Sprintf("%v delete warning", urn), warns,
+		resourceAttributePath(t, res)); logErr != nil {
+		return nil, logErr
+	}
+	if err != nil {
 		return nil, errors.Wrapf(err, "deleting %s", urn)
 	}
 	return &pbempty.Empty{}, nil
@@ -1143,44 +1204,101 @@ func (p *Provider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*p
 
 	// First, create the inputs.
 	tfname := ds.TFName
+	span := p.startConversionSpan(ctx, "MakeTerraformInputs", tfname, tracingTokenKey.String(string(tok)))
 	inputs, _, err := MakeTerraformInputs(
 		&PulumiResource{Properties: args}, p.configValues, nil, args, ds.TF.Schema(), ds.Schema.Fields)
+	endSpan(span, err)
 	if err != nil {
 		return nil, errors.Wrapf(err, "couldn't prepare resource %v input state", tfname)
 	}
 
+	// The invoke protocol carries no preview flag, but the engine only sends unknown arguments during a preview, as
+	// every value is resolved before an update invokes a function. Rather than hand unknowns to the Terraform
+	// provider, which is likely to reject them, skip the read and return a result in which every property that is
+	// not a known argument is unknown. Data sources return their arguments unchanged, so the known arguments are
+	// copied into the result, as Check keeps the known inputs of a resource.
+	if args.ContainsUnknowns() {
+		p.logDebug(ctx, fmt.Sprintf("%s: arguments contain unknowns; returning unknown results", label))
+		props := makeUnknownOutputs(ds.TF.Schema(), ds.Schema.Fields)
+		for k, v := range args {
+			if _, has := props[k]; has && !v.ContainsUnknowns() {
+				props[k] = v
+			}
+		}
+		if _, has := props["id"]; !has {
+			props["id"] = resource.MakeComputed(resource.NewStringProperty(""))
+		}
+		ret, err := plugin.MarshalProperties(props, plugin.MarshalOptions{
+			Label: fmt.Sprintf("%s.returns", label), KeepUnknowns: true})
+		if err != nil {
+			return nil, err
+		}
+		return &pulumirpc.InvokeResponse{Return: ret}, nil
+	}
+
+	// If this data source opts into memoization and all of its arguments are known, check for a cached result.
+	var cacheKey string
+	if ds.Schema.CacheTTL > 0 {
+		if cacheKey, err = invokeCacheKey(tok, inputs); err != nil {
+			return nil, errors.Wrapf(err, "computing cache key for %s", tok)
+		}
+		if resp, ok := p.invokeCache.get(cacheKey); ok {
+			p.logDebug(ctx, fmt.Sprintf("%s: cache hit", label))
+			return resp, nil
+		}
+		p.logDebug(ctx, fmt.Sprintf("%s: cache miss", label))
+	}
+
 	// Next, ensure the inputs are valid before actually performing the invoaction.
 	rescfg := MakeTerraformConfigFromInputs(p.tf, inputs)
 	warns, errs := p.tf.ValidateDataSource(tfname, rescfg)
-	for _, warn := range warns {
-		if err = p.host.Log(ctx, diag.Warning, "", fmt.Sprintf("%v verification warning: %v", tok, warn)); err != nil {
-			return nil, err
-		}
+	if err = p.logWarnings(ctx, "", fmt.Sprintf("%v verification warning", tok), warns,
+		dataSourceAttributePath(tok, ds)); err != nil {
+		return nil, err
 	}
 
 	// Now produce a return value of any properties that failed verification.
 	var failures []*pulumirpc.CheckFailure
 	for _, err := range errs {
+		reason := err.Error()
+		var d *diagnostics.Diagnostic
+		if errors.As(err, &d) {
+			reason = formatDiagnostic(*d, dataSourceAttributePath(tok, ds))
+		}
 		failures = append(failures, &pulumirpc.CheckFailure{
-			Reason: err.Error(),
+			Reason: reason,
 		})
 	}
 
 	// If there are no failures in verification, go ahead and perform the invocation.
 	var ret *pbstruct.Struct
 	if len(failures) == 0 {
-		diff, err := p.tf.ReadDataDiff(tfname, rescfg)
+		span := p.startTFSpan(ctx, "ReadDataDiff", tfname, tracingTokenKey.String(string(tok)))
+		diff, warns, err := p.tf.ReadDataDiff(tfname, rescfg)
+		endSpan(span, err)
+		if logErr := p.logWarnings(ctx, "", fmt.Sprintf("%v warning", tok), warns,
+			dataSourceAttributePath(tok, ds)); logErr != nil {
+			return nil, logErr
+		}
 		if err != nil {
 			return nil, errors.Wrapf(err, "reading data source diff for %s", tok)
 		}
 
-		invoke, err := p.tf.ReadDataApply(tfname, diff)
+		span = p.startTFSpan(ctx, "ReadDataApply", tfname, tracingTokenKey.String(string(tok)))
+		invoke, warns, err := p.tf.ReadDataApply(tfname, diff)
+		endSpan(span, err)
+		if logErr := p.logWarnings(ctx, "", fmt.Sprintf("%v warning", tok), warns,
+			dataSourceAttributePath(tok, ds)); logErr != nil {
+			return nil, logErr
+		}
 		if err != nil {
 			return nil, errors.Wrapf(err, "invoking %s", tok)
 		}
 
 		// Add the special "id" attribute if it wasn't listed in the schema
+		span = p.startConversionSpan(ctx, "MakeTerraformResult", tfname, tracingTokenKey.String(string(tok)))
 		props, err := MakeTerraformResult(p.tf, invoke, ds.TF.Schema(), ds.Schema.Fields, nil, p.supportsSecrets)
+		endSpan(span, err)
 		if err != nil {
 			return nil, err
 		}
@@ -1196,10 +1314,14 @@ func (p *Provider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*p
 		}
 	}
 
-	return &pulumirpc.InvokeResponse{
+	resp := &pulumirpc.InvokeResponse{
 		Return:   ret,
 		Failures: failures,
-	}, nil
+	}
+	if cacheKey != "" && len(failures) == 0 {
+		p.invokeCache.put(cacheKey, resp, ds.Schema.CacheTTL)
+	}
+	return resp, nil
 }
 
 // StreamInvoke dynamically executes a built-in function in the provider. The result is streamed
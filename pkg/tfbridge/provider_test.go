@@ -730,3 +730,49 @@ func TestProviderReadNestedSecretV2(t *testing.T) {
 
 	testProviderReadNestedSecret(t, provider, "NestedSecretResource")
 }
+
+func TestInvokeWithUnknownArgs(t *testing.T) {
+	tf := &countingProvider{Provider: shimv2.NewProvider(testTFProviderV2)}
+	provider := &Provider{
+		tf:     tf,
+		config: shimv2.NewSchemaMap(testTFProviderV2.Schema),
+	}
+	provider.dataSources = map[tokens.ModuleMember]DataSource{
+		"tprov:index/ExampleFn:ExampleFn": {
+			TF:     shimv2.NewResource(testTFProviderV2.DataSourcesMap["example_resource"]),
+			TFName: "example_resource",
+			Schema: &DataSourceInfo{Tok: "tprov:index/ExampleFn:ExampleFn"},
+		},
+	}
+
+	args, err := plugin.MarshalProperties(resource.PropertyMap{
+		"arrayPropertyValues": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.MakeComputed(resource.NewStringProperty("")),
+		}),
+		"stringPropertyValue": resource.NewStringProperty("known"),
+	}, plugin.MarshalOptions{KeepUnknowns: true})
+	assert.NoError(t, err)
+	resp, err := provider.Invoke(context.Background(), &pulumirpc.InvokeRequest{
+		Tok:  "tprov:index/ExampleFn:ExampleFn",
+		Args: args,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.GetFailures())
+	assert.Equal(t, 0, tf.reads)
+
+	outs, err := plugin.UnmarshalProperties(resp.GetReturn(), plugin.MarshalOptions{KeepUnknowns: true})
+	assert.NoError(t, err)
+	assert.Equal(t, resource.MakeComputed(resource.NewStringProperty("")), outs["id"])
+	assert.Equal(t, resource.MakeComputed(resource.NewBoolProperty(false)), outs["boolPropertyValue"])
+	assert.Equal(t, resource.MakeComputed(resource.NewNumberProperty(0)), outs["numberPropertyValue"])
+	assert.True(t, outs["arrayPropertyValues"].IsComputed())
+	assert.True(t, outs["nestedResources"].IsComputed())
+
+	// Known arguments are copied into the result; every other property is unknown.
+	assert.Equal(t, resource.NewStringProperty("known"), outs["stringPropertyValue"])
+	for k, v := range outs {
+		if k != "stringPropertyValue" {
+			assert.True(t, v.IsComputed(), k)
+		}
+	}
+}
@@ -17,6 +17,8 @@ package tfbridge
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
 	"os"
 	"reflect"
 	"sort"
@@ -99,7 +101,10 @@ func getSchema(m shim.SchemaMap, key string) shim.Schema {
 
 func elemSchemas(sch shim.Schema, ps *SchemaInfo) (shim.Schema, *SchemaInfo) {
 	var esch shim.Schema
-	if sch != nil {
+	if isDynamic(sch) {
+		// The elements of a dynamically-typed value are themselves dynamically-typed.
+		esch = sch
+	} else if sch != nil {
 		switch e := sch.Elem().(type) {
 		case shim.Schema:
 			esch = e
@@ -147,6 +152,11 @@ func MakeTerraformInputs(instance *PulumiResource, config resource.PropertyMap,
 func (ctx *conversionContext) MakeTerraformInput(name string, old, v resource.PropertyValue,
 	tfs shim.Schema, ps *SchemaInfo, rawNames bool) (interface{}, error) {
 
+	// The names of properties within dynamically-typed values are never mangled.
+	if isDynamic(tfs) {
+		rawNames = true
+	}
+
 	// For TypeList or TypeSet with MaxItems==1, we will have projected as a scalar nested value, and need to wrap it
 	// into a single-element array before passing to Terraform.
 	if IsMaxItemsOne(tfs, ps) {
@@ -177,10 +187,15 @@ func (ctx *conversionContext) MakeTerraformInput(name string, old, v resource.Pr
 	case v.IsBool():
 		return v.BoolValue(), nil
 	case v.IsNumber():
-		if tfs != nil && tfs.Type() == shim.TypeFloat {
+		if tfs != nil && (tfs.Type() == shim.TypeFloat || tfs.Type() == shim.TypeDynamic) {
 			return v.NumberValue(), nil
 		}
-		return int(v.NumberValue()), nil // convert floats to ints.
+		n := v.NumberValue()
+		if n < math.MinInt64 || n >= math.MaxInt64 {
+			// The value does not fit in an int, so pass it through rather than truncating it.
+			return n, nil
+		}
+		return int(n), nil // convert floats to ints.
 	case v.IsString():
 		return v.StringValue(), nil
 	case v.IsArray():
@@ -237,8 +252,14 @@ func (ctx *conversionContext) MakeTerraformInput(name string, old, v resource.Pr
 		}
 		return ps.Asset.TranslateArchive(v.ArchiveValue())
 	case v.IsObject():
+		if etfs := mapOfObjectsElem(tfs); etfs != nil {
+			return ctx.makeMapOfObjectsInput(name, old, v, etfs, ps, rawNames)
+		}
+
 		var tfflds shim.SchemaMap
-		if tfs != nil {
+		if isDynamic(tfs) {
+			tfflds = dynamicSchemaMap{schema: tfs}
+		} else if tfs != nil {
 			if res, isres := tfs.Elem().(shim.Resource); isres {
 				tfflds = res.Schema()
 			}
@@ -278,6 +299,45 @@ func (ctx *conversionContext) MakeTerraformInput(name string, old, v resource.Pr
 
 }
 
+// makeMapOfObjectsInput prepares a map of objects for use by Terraform. The keys of the map are preserved as-is, and
+// each element is converted using the object type of the map's element schema.
+func (ctx *conversionContext) makeMapOfObjectsInput(name string, old, v resource.PropertyValue,
+	etfs shim.Schema, ps *SchemaInfo, rawNames bool) (interface{}, error) {
+
+	var eps *SchemaInfo
+	var epsflds map[string]*SchemaInfo
+	if ps != nil && ps.Elem != nil {
+		eps, epsflds = ps.Elem, ps.Elem.Fields
+	}
+	var oldObject resource.PropertyMap
+	if old.IsObject() {
+		oldObject = old.ObjectValue()
+	}
+
+	tfflds := etfs.Elem().(shim.Resource).Schema()
+	result := map[string]interface{}{}
+	for key, elem := range v.ObjectValue() {
+		oldElem := oldObject[key]
+
+		var input interface{}
+		var err error
+		if elem.IsObject() {
+			var oldElemObject resource.PropertyMap
+			if oldElem.IsObject() {
+				oldElemObject = oldElem.ObjectValue()
+			}
+			input, err = ctx.MakeTerraformInputs(oldElemObject, elem.ObjectValue(), tfflds, epsflds, rawNames)
+		} else {
+			input, err = ctx.MakeTerraformInput(fmt.Sprintf("%v.%v", name, key), oldElem, elem, etfs, eps, rawNames)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result[string(key)] = input
+	}
+	return result, nil
+}
+
 // MakeTerraformInputs takes a property map plus custom schema info and does whatever is necessary
 // to prepare it for use by Terraform.  Note that this function may have side effects, for instance
 // if it is necessary to spill an asset to disk in order to create a name out of it.  Please take
@@ -335,6 +395,11 @@ func (ctx *conversionContext) applyDefaults(result map[string]interface{}, olds,
 		return nil
 	}
 
+	// Dynamically-typed values have no defaults to track.
+	if _, ok := tfs.(dynamicSchemaMap); ok {
+		return nil
+	}
+
 	// Create an array to track which properties are defaults.
 	newDefaults := []interface{}{}
 
@@ -356,18 +421,26 @@ func (ctx *conversionContext) applyDefaults(result map[string]interface{}, olds,
 			if _, has := result[name]; has {
 				// `name` is present, so mark any names that are declared to
 				// conflict with `name` for exclusion.
-				for _, conflictingName := range sch.ConflictsWith() {
+				for _, conflictingName := range conflictingNames(name, sch) {
 					conflictsWith[conflictingName] = struct{}{}
 				}
 			} else {
 				// `name` is not present, so mark it for exclusion if any fields
 				// that conflict with `name` are present.
-				for _, conflictingName := range sch.ConflictsWith() {
+				for _, conflictingName := range conflictingNames(name, sch) {
 					if _, has := result[conflictingName]; has {
 						conflictsWith[name] = struct{}{}
 						break
 					}
 				}
+				// Likewise, mark it for exclusion if any of the fields that
+				// must be set alongside `name` are missing.
+				for _, requiredName := range sch.RequiredWith() {
+					if _, has := result[requiredName]; !has {
+						conflictsWith[name] = struct{}{}
+						break
+					}
+				}
 			}
 			return true
 		})
@@ -458,7 +531,7 @@ func (ctx *conversionContext) applyDefaults(result map[string]interface{}, olds,
 
 				// Expand the conflicts map
 				if sch != nil {
-					for _, conflictingName := range sch.ConflictsWith() {
+					for _, conflictingName := range conflictingNames(name, sch) {
 						conflictsWith[conflictingName] = struct{}{}
 					}
 				}
@@ -481,7 +554,7 @@ func (ctx *conversionContext) applyDefaults(result map[string]interface{}, olds,
 			}
 
 			// If a conflicting field has a default value, don't set the default for the current field
-			for _, conflictingName := range sch.ConflictsWith() {
+			for _, conflictingName := range conflictingNames(name, sch) {
 				if conflictingSchema, exists := tfs.GetOk(conflictingName); exists {
 					dv, _ := conflictingSchema.DefaultValue()
 					if dv != nil {
@@ -590,6 +663,52 @@ func makeTerraformUnknown(tfs shim.Schema) interface{} {
 	}
 }
 
+// makeUnknownOutputs returns a Pulumi property map in which every property in the given schema is unknown. Each
+// unknown carries a zero value of its property's type as its element.
+func makeUnknownOutputs(tfs shim.SchemaMap, ps map[string]*SchemaInfo) resource.PropertyMap {
+	result := make(resource.PropertyMap)
+	tfs.Range(func(key string, sch shim.Schema) bool {
+		name, _, psi := getInfoFromTerraformName(key, tfs, ps, false)
+		result[name] = makeUnknownOutput(sch, psi)
+		return true
+	})
+	return result
+}
+
+// makeUnknownOutput returns an unknown Pulumi value whose element is a zero value of the type given by the schema.
+func makeUnknownOutput(tfs shim.Schema, ps *SchemaInfo) resource.PropertyValue {
+	var elem resource.PropertyValue
+	switch tfs.Type() {
+	case shim.TypeBool:
+		elem = resource.NewBoolProperty(false)
+	case shim.TypeInt, shim.TypeFloat:
+		elem = resource.NewNumberProperty(0)
+	case shim.TypeList, shim.TypeSet:
+		if !IsMaxItemsOne(tfs, ps) {
+			elem = resource.NewArrayProperty([]resource.PropertyValue{})
+			break
+		}
+		// A flattened list takes the type of its element.
+		switch e := tfs.Elem().(type) {
+		case shim.Schema:
+			var eps *SchemaInfo
+			if ps != nil {
+				eps = ps.Elem
+			}
+			return makeUnknownOutput(e, eps)
+		case shim.Resource:
+			elem = resource.NewObjectProperty(resource.PropertyMap{})
+		default:
+			elem = resource.NewStringProperty("")
+		}
+	case shim.TypeMap:
+		elem = resource.NewObjectProperty(resource.PropertyMap{})
+	default:
+		elem = resource.NewStringProperty("")
+	}
+	return resource.MakeComputed(elem)
+}
+
 // metaKey is the key in a TF bridge result that is used to store a resource's meta-attributes.
 const metaKey = "__meta"
 
@@ -652,6 +771,11 @@ func MakeTerraformOutputs(p shim.Provider, outs map[string]interface{},
 func MakeTerraformOutput(p shim.Provider, v interface{},
 	tfs shim.Schema, ps *SchemaInfo, assets AssetTable, rawNames, supportsSecrets bool) resource.PropertyValue {
 
+	// The names of properties within dynamically-typed values are never mangled.
+	if isDynamic(tfs) {
+		rawNames = true
+	}
+
 	buildOutput := func(p shim.Provider, v interface{},
 		tfs shim.Schema, ps *SchemaInfo, assets AssetTable, rawNames, supportsSecrets bool) resource.PropertyValue {
 		if assets != nil && ps != nil && ps.Asset != nil {
@@ -674,6 +798,14 @@ func MakeTerraformOutput(p shim.Provider, v interface{},
 			v = list
 		}
 
+		// Arbitrary-precision numbers are produced by providers that keep integer precision.
+		switch n := v.(type) {
+		case *big.Int:
+			return makeNumberOutput(new(big.Float).SetInt(n), ps)
+		case *big.Float:
+			return makeNumberOutput(n, ps)
+		}
+
 		// We use reflection instead of a type switch so that we can support mapping values whose underlying type is
 		// supported into a Pulumi value, even if they stored as a wrapper type (such as a strongly-typed enum).
 		//
@@ -684,9 +816,11 @@ func MakeTerraformOutput(p shim.Provider, v interface{},
 		switch val.Kind() {
 		case reflect.Bool:
 			return resource.NewBoolProperty(val.Bool())
-		case reflect.Int:
-			return resource.NewNumberProperty(float64(val.Int()))
-		case reflect.Float64:
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return makeNumberOutput(new(big.Float).SetInt64(val.Int()), ps)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return makeNumberOutput(new(big.Float).SetUint64(val.Uint()), ps)
+		case reflect.Float32, reflect.Float64:
 			return resource.NewNumberProperty(val.Float())
 		case reflect.String:
 			// If the string is the special unknown property sentinel, reflect back an unknown computed property.  Note that
@@ -742,6 +876,18 @@ func MakeTerraformOutput(p shim.Provider, v interface{},
 				contract.Assert(key.Kind() == reflect.String)
 				outs[key.String()] = val.MapIndex(key).Interface()
 			}
+			if etfs := mapOfObjectsElem(tfs); etfs != nil {
+				var eps *SchemaInfo
+				if ps != nil {
+					eps = ps.Elem
+				}
+				obj := resource.PropertyMap{}
+				for key, elem := range outs {
+					obj[resource.PropertyKey(key)] = MakeTerraformOutput(p, elem, etfs, eps, assets, rawNames,
+						supportsSecrets)
+				}
+				return resource.NewObjectProperty(obj)
+			}
 			var tfflds shim.SchemaMap
 			if tfs != nil {
 				if res, isres := tfs.Elem().(shim.Resource); isres {
@@ -884,9 +1030,29 @@ func IsMaxItemsOne(tfs shim.Schema, info *SchemaInfo) bool {
 	return tfs.MaxItems() == 1
 }
 
+// mapOfObjectsElem returns the element schema of a map of objects, or nil if the given schema does not describe one.
+// The elements of a map of objects are described by a TypeMap schema whose Elem is a shim.Resource, which tells such
+// a map apart from a single object, whose schema has a shim.Resource Elem itself.
+func mapOfObjectsElem(tfs shim.Schema) shim.Schema {
+	if tfs == nil || tfs.Type() != shim.TypeMap {
+		return nil
+	}
+	etfs, ok := tfs.Elem().(shim.Schema)
+	if !ok || etfs.Type() != shim.TypeMap {
+		return nil
+	}
+	if _, ok := etfs.Elem().(shim.Resource); !ok {
+		return nil
+	}
+	return etfs
+}
+
 // useRawNames returns true if raw, unmangled names should be preserved.  This is only true for Terraform maps with
 // an Elem that is not a shim.Resource.
 func useRawNames(tfs shim.Schema) bool {
+	if isDynamic(tfs) {
+		return true
+	}
 	if tfs == nil || tfs.Type() != shim.TypeMap {
 		return false
 	}
@@ -894,6 +1060,58 @@ func useRawNames(tfs shim.Schema) bool {
 	return !hasResourceElem
 }
 
+// conflictingNames returns the names of the fields that may not be set alongside the named field: those it is
+// declared to conflict with, and the other members of any ExactlyOneOf group it belongs to. AtLeastOneOf groups place
+// no such restriction on their members.
+func conflictingNames(name string, sch shim.Schema) []string {
+	exactlyOneOf := sch.ExactlyOneOf()
+	if len(exactlyOneOf) == 0 {
+		return sch.ConflictsWith()
+	}
+
+	names := append([]string{}, sch.ConflictsWith()...)
+	for _, n := range exactlyOneOf {
+		if n != name {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// isDynamic returns true if the given schema describes a dynamically-typed value.
+func isDynamic(tfs shim.Schema) bool {
+	return tfs != nil && tfs.Type() == shim.TypeDynamic
+}
+
+// dynamicSchemaMap is the schema of the properties of a dynamically-typed object value: every property is itself
+// dynamically-typed.
+type dynamicSchemaMap struct {
+	schema shim.Schema
+}
+
+func (m dynamicSchemaMap) Len() int {
+	return 0
+}
+
+func (m dynamicSchemaMap) Get(key string) shim.Schema {
+	return m.schema
+}
+
+func (m dynamicSchemaMap) GetOk(key string) (shim.Schema, bool) {
+	return m.schema, true
+}
+
+func (m dynamicSchemaMap) Range(each func(key string, value shim.Schema) bool) {
+}
+
+func (m dynamicSchemaMap) Set(key string, value shim.Schema) {
+	contract.Failf("dynamicSchemaMap is read-only")
+}
+
+func (m dynamicSchemaMap) Delete(key string) {
+	contract.Failf("dynamicSchemaMap is read-only")
+}
+
 // getInfoFromTerraformName does a map lookup to find the Pulumi name and schema info, if any.
 func getInfoFromTerraformName(key string,
 	tfs shim.SchemaMap, ps map[string]*SchemaInfo, rawName bool) (resource.PropertyKey,
@@ -941,6 +1159,17 @@ func getInfoFromPulumiName(key resource.PropertyKey,
 	return name, getSchema(tfs, name), ps[name]
 }
 
+// makeNumberOutput converts a number to a Pulumi value. Pulumi numbers are float64s, so integers that cannot be
+// represented exactly as a float64 are only preserved if the property's type is overridden to be a string, in which
+// case the number is returned in its decimal form.
+func makeNumberOutput(n *big.Float, ps *SchemaInfo) resource.PropertyValue {
+	if ps != nil && strings.ToLower(ps.Type.String()) == "string" {
+		return resource.NewStringProperty(n.Text('f', -1))
+	}
+	f, _ := n.Float64()
+	return resource.NewNumberProperty(f)
+}
+
 // CoerceTerraformString coerces a string value to a Go value whose type is the type requested by the Terraform schema
 // type or the Pulumi SchemaInfo. We prefer the SchemaInfo overrides as it's an explicit call to action over the
 // Terraform Schema. Returns an error if the string can't be successfully coerced to the requested type.
@@ -80,6 +80,8 @@ func (f shimv1Factory) newSchema(m shim.Schema) *schemav1.Schema {
 		MaxItems:      m.MaxItems(),
 		MinItems:      m.MinItems(),
 		ConflictsWith: m.ConflictsWith(),
+		ExactlyOneOf:  m.ExactlyOneOf(),
+		AtLeastOneOf:  m.AtLeastOneOf(),
 		Deprecated:    m.Deprecated(),
 		Removed:       m.Removed(),
 		Sensitive:     m.Sensitive(),
@@ -187,6 +189,9 @@ func (f shimv2Factory) newSchema(m shim.Schema) *schemav2.Schema {
 		MaxItems:      m.MaxItems(),
 		MinItems:      m.MinItems(),
 		ConflictsWith: m.ConflictsWith(),
+		ExactlyOneOf:  m.ExactlyOneOf(),
+		AtLeastOneOf:  m.AtLeastOneOf(),
+		RequiredWith:  m.RequiredWith(),
 		Deprecated:    m.Deprecated(),
 		Sensitive:     m.Sensitive(),
 	}
@@ -16,6 +16,8 @@ package tfbridge
 
 import (
 	"context"
+	"math"
+	"math/big"
 	"os"
 	"sort"
 	"strconv"
@@ -506,7 +508,7 @@ func TestMetaProperties(t *testing.T) {
 			res := prov.ResourcesMap().Get(resName)
 
 			state := f.NewInstanceState("0")
-			read, err := prov.Refresh(resName, state)
+			read, _, err := prov.Refresh(resName, state)
 			assert.NoError(t, err)
 			assert.NotNil(t, read)
 
@@ -520,7 +522,7 @@ func TestMetaProperties(t *testing.T) {
 
 			assert.Equal(t, strconv.Itoa(res.SchemaVersion()), state.Meta()["schema_version"])
 
-			read2, err := prov.Refresh(resName, state)
+			read2, _, err := prov.Refresh(resName, state)
 			assert.NoError(t, err)
 			assert.NotNil(t, read2)
 			assert.Equal(t, read, read2)
@@ -546,7 +548,7 @@ func TestMetaProperties(t *testing.T) {
 			ok = clearID(state)
 			assert.True(t, ok)
 			cfg := prov.NewResourceConfig(map[string]interface{}{})
-			diff, err := prov.Diff(resName, state, cfg)
+			diff, _, err := prov.Diff(resName, state, cfg)
 			assert.NoError(t, err)
 
 			// To populate default timeouts, we take the timeouts from the resource schema and insert them into the diff
@@ -556,7 +558,7 @@ func TestMetaProperties(t *testing.T) {
 			assert.NoError(t, err)
 
 			assert.NoError(t, err)
-			create, err := prov.Apply(resName, state, diff)
+			create, _, err := prov.Apply(resName, state, diff)
 			assert.NoError(t, err)
 
 			props, err = MakeTerraformResult(prov, create, res.Schema(), nil, nil, true)
@@ -581,7 +583,7 @@ func TestInjectingCustomTimeouts(t *testing.T) {
 			res := prov.ResourcesMap().Get(resName)
 
 			state := f.NewInstanceState("0")
-			read, err := prov.Refresh(resName, state)
+			read, _, err := prov.Refresh(resName, state)
 			assert.NoError(t, err)
 			assert.NotNil(t, read)
 
@@ -595,7 +597,7 @@ func TestInjectingCustomTimeouts(t *testing.T) {
 
 			assert.Equal(t, strconv.Itoa(res.SchemaVersion()), state.Meta()["schema_version"])
 
-			read2, err := prov.Refresh(resName, state)
+			read2, _, err := prov.Refresh(resName, state)
 			assert.NoError(t, err)
 			assert.NotNil(t, read2)
 			assert.Equal(t, read, read2)
@@ -621,7 +623,7 @@ func TestInjectingCustomTimeouts(t *testing.T) {
 			ok = clearID(state)
 			assert.True(t, ok)
 			cfg := prov.NewResourceConfig(map[string]interface{}{})
-			diff, err := prov.Diff(resName, state, cfg)
+			diff, _, err := prov.Diff(resName, state, cfg)
 			assert.NoError(t, err)
 
 			// To populate default timeouts, we take the timeouts from the resource schema and insert them into the diff
@@ -633,7 +635,7 @@ func TestInjectingCustomTimeouts(t *testing.T) {
 			diff.SetTimeout(300, schemav1.TimeoutCreate)
 
 			assert.NoError(t, err)
-			create, err := prov.Apply(resName, state, diff)
+			create, _, err := prov.Apply(resName, state, diff)
 			assert.NoError(t, err)
 
 			props, err = MakeTerraformResult(prov, create, res.Schema(), nil, nil, true)
@@ -686,7 +688,7 @@ func TestResultAttributesRoundTrip(t *testing.T) {
 			res := prov.ResourcesMap().Get("example_resource")
 
 			state := f.NewInstanceState("0")
-			read, err := prov.Refresh(resName, state)
+			read, _, err := prov.Refresh(resName, state)
 			assert.NoError(t, err)
 			assert.NotNil(t, read)
 
@@ -894,6 +896,47 @@ func TestDefaults(t *testing.T) {
 	}
 }
 
+func TestDefaultsHonorCrossFieldConstraints(t *testing.T) {
+	// Tests that defaults are not applied where they would violate a field's cross-field constraints:
+	//     - aaa/aa2: ExactlyOneOf, TF defaults, input "AAA" => only "AAA"
+	//     - bbb/bb2: ExactlyOneOf, PS default for bbb, no inputs => only "PSB"
+	//     - ccc/cc2: AtLeastOneOf, TF defaults, input "CCC" => "CCC" and "TC2"
+	//     - ddd: RequiredWith "dd2", TF default, no input for dd2 => nothing
+	//     - eee: RequiredWith "ee2", TF default, input "EE2" => "TFE" and "EE2"
+	tfs := schema.SchemaMap{
+		"aaa": (&schema.Schema{Type: shim.TypeString, ExactlyOneOf: []string{"aaa", "aa2"}, Default: "TFA"}).Shim(),
+		"aa2": (&schema.Schema{Type: shim.TypeString, ExactlyOneOf: []string{"aaa", "aa2"}, Default: "TA2"}).Shim(),
+		"bbb": (&schema.Schema{Type: shim.TypeString, ExactlyOneOf: []string{"bbb", "bb2"}}).Shim(),
+		"bb2": (&schema.Schema{Type: shim.TypeString, ExactlyOneOf: []string{"bbb", "bb2"}, Default: "TB2"}).Shim(),
+		"ccc": (&schema.Schema{Type: shim.TypeString, AtLeastOneOf: []string{"ccc", "cc2"}, Default: "TFC"}).Shim(),
+		"cc2": (&schema.Schema{Type: shim.TypeString, AtLeastOneOf: []string{"ccc", "cc2"}, Default: "TC2"}).Shim(),
+		"ddd": (&schema.Schema{Type: shim.TypeString, RequiredWith: []string{"dd2"}, Default: "TFD"}).Shim(),
+		"dd2": (&schema.Schema{Type: shim.TypeString}).Shim(),
+		"eee": (&schema.Schema{Type: shim.TypeString, RequiredWith: []string{"ee2"}, Default: "TFE"}).Shim(),
+		"ee2": (&schema.Schema{Type: shim.TypeString}).Shim(),
+	}
+	ps := map[string]*SchemaInfo{
+		"bbb": {Default: &DefaultInfo{Value: "PSB"}},
+	}
+	props := resource.PropertyMap{
+		"aaa": resource.NewStringProperty("AAA"),
+		"ccc": resource.NewStringProperty("CCC"),
+		"ee2": resource.NewStringProperty("EE2"),
+	}
+
+	inputs, _, err := makeTerraformInputsWithDefaults(nil, props, tfs, ps)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		defaultsKey: []interface{}{resource.PropertyKey("bbb"), resource.PropertyKey("cc2"), resource.PropertyKey("eee")},
+		"aaa":       "AAA",
+		"bbb":       "PSB",
+		"ccc":       "CCC",
+		"cc2":       "TC2",
+		"eee":       "TFE",
+		"ee2":       "EE2",
+	}, inputs)
+}
+
 func TestComputedAsset(t *testing.T) {
 	tfs := shimv1.NewSchemaMap(map[string]*schemav1.Schema{
 		"zzz": {Type: schemav1.TypeString},
@@ -2094,3 +2137,148 @@ func TestExtractDefaultIntegerInputs(t *testing.T) {
 	})
 	assert.Equal(t, expected, ins)
 }
+
+func TestWideIntegerOutputs(t *testing.T) {
+	maxUint64 := new(big.Int).SetUint64(math.MaxUint64)
+	tfs := shimv1.NewSchemaMap(map[string]*schemav1.Schema{
+		"int64_value":   {Type: schemav1.TypeInt},
+		"int64_string":  {Type: schemav1.TypeInt},
+		"big_int_value": {Type: schemav1.TypeFloat},
+		"big_string":    {Type: schemav1.TypeFloat},
+	})
+	ps := map[string]*SchemaInfo{
+		"int64_string": {Type: "string"},
+		"big_string":   {Type: "string"},
+	}
+
+	result := MakeTerraformOutputs(
+		shimv1.NewProvider(testTFProvider),
+		map[string]interface{}{
+			"int64_value":   int64(1 << 40),
+			"int64_string":  int64(math.MaxInt64),
+			"big_int_value": maxUint64,
+			"big_string":    maxUint64,
+		},
+		tfs,
+		ps,
+		nil,   /* assets */
+		false, /* useRawNames */
+		true,
+	)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"int64Value":  float64(1 << 40),
+		"int64String": "9223372036854775807",
+		"bigIntValue": float64(math.MaxUint64),
+		"bigString":   "18446744073709551615",
+	}), result)
+
+	// Numbers that are too large for an int are passed through as-is rather than being truncated.
+	inputs, _, err := MakeTerraformInputs(nil, nil, nil, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"int64Value":  1e30,
+		"int64String": "9223372036854775807",
+	}), tfs, ps)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"int64_value":  1e30,
+		"int64_string": "9223372036854775807",
+		defaultsKey:    []interface{}{},
+	}, inputs)
+}
+
+func TestDynamicInputsAndOutputs(t *testing.T) {
+	tfs := schema.SchemaMap{
+		"dynamic_value": (&schema.Schema{Type: shim.TypeDynamic, Optional: true}).Shim(),
+	}
+
+	inputs, _, err := MakeTerraformInputs(nil, nil, nil, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"dynamicValue": map[string]interface{}{
+			"someNumber": 1.5,
+			"someInt":    2,
+			"someList":   []interface{}{map[string]interface{}{"nestedKey": "a"}, true},
+		},
+	}), tfs, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"dynamic_value": map[string]interface{}{
+			"someNumber": 1.5,
+			"someInt":    2.0,
+			"someList":   []interface{}{map[string]interface{}{"nestedKey": "a"}, true},
+		},
+		defaultsKey: []interface{}{},
+	}, inputs)
+
+	outputs := MakeTerraformOutputs(shimv1.NewProvider(testTFProvider), map[string]interface{}{
+		"dynamic_value": map[string]interface{}{
+			"some_number": int64(3),
+			"some_list":   []interface{}{map[string]interface{}{"nested_key": "a"}},
+		},
+	}, tfs, nil, nil, false, true)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"dynamicValue": map[string]interface{}{
+			"some_number": 3.0,
+			"some_list":   []interface{}{map[string]interface{}{"nested_key": "a"}},
+		},
+	}), outputs)
+}
+
+func TestEnumInputsAcceptRawStrings(t *testing.T) {
+	tfs := schema.SchemaMap{
+		"tier": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+	}
+	ps := map[string]*SchemaInfo{
+		"tier": {Enum: &EnumInfo{Values: []EnumValueInfo{{Value: "standard"}, {Value: "premium"}}}},
+	}
+
+	// Enums only constrain the SDKs: any string, including one that is not a value of the enum, is passed through.
+	for _, tier := range []string{"premium", "legacy"} {
+		inputs, _, err := MakeTerraformInputs(nil, nil, nil, resource.NewPropertyMapFromMap(map[string]interface{}{
+			"tier": tier,
+		}), tfs, ps)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"tier":      tier,
+			defaultsKey: []interface{}{},
+		}, inputs)
+	}
+}
+
+func TestMapOfObjectsInputsAndOutputs(t *testing.T) {
+	object := (&schema.Resource{Schema: schema.SchemaMap{
+		"port_number": (&schema.Schema{Type: shim.TypeInt, Optional: true}).Shim(),
+	}}).Shim()
+	tfs := schema.SchemaMap{
+		"single_object": (&schema.Schema{Type: shim.TypeMap, Optional: true, Elem: object}).Shim(),
+		"object_map": (&schema.Schema{
+			Type:     shim.TypeMap,
+			Optional: true,
+			Elem:     (&schema.Schema{Type: shim.TypeMap, Elem: object}).Shim(),
+		}).Shim(),
+	}
+
+	// The keys of a map of objects are preserved while the names of the objects' properties are mangled.
+	inputs, _, err := MakeTerraformInputs(nil, nil, nil, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"singleObject": map[string]interface{}{"portNumber": 80},
+		"objectMap": map[string]interface{}{
+			"firstKey": map[string]interface{}{"portNumber": 443},
+		},
+	}), tfs, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"single_object": []interface{}{map[string]interface{}{"port_number": 80, defaultsKey: []interface{}{}}},
+		"object_map": map[string]interface{}{
+			"firstKey": map[string]interface{}{"port_number": 443, defaultsKey: []interface{}{}},
+		},
+		defaultsKey: []interface{}{},
+	}, inputs)
+
+	outputs := MakeTerraformOutputs(shimv1.NewProvider(testTFProvider), map[string]interface{}{
+		"object_map": map[string]interface{}{
+			"first_key": map[string]interface{}{"port_number": 443},
+		},
+	}, tfs, nil, nil, false, true)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"objectMap": map[string]interface{}{
+			"first_key": map[string]interface{}{"portNumber": 443},
+		},
+	}), outputs)
+}
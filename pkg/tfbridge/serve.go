@@ -17,16 +17,46 @@ package tfbridge
 import (
 	"context"
 
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+
 	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
 	lumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 )
 
 // Serve fires up a Pulumi resource provider listening to inbound gRPC traffic,
 // and translates calls from Pulumi into actions against the provided Terraform Provider.
+//
+// If the environment variable named by GrpcLogEnvVar is set, every RPC is also recorded to the file it names. If
+// either TracingEndpointEnvVar or TracingFileEnvVar is set, every RPC is traced with OpenTelemetry.
 func Serve(module string, version string, info ProviderInfo, pulumiSchema []byte) error {
+	tracing, err := initTracing(context.Background(), module, version)
+	if err != nil {
+		return err
+	}
+	if tracing != nil {
+		defer func() { contract.IgnoreError(tracing.shutdown(context.Background())) }()
+	}
+
+	logFile, err := newGrpcLogFile()
+	if err != nil {
+		return err
+	}
+	if logFile != nil {
+		defer contract.IgnoreClose(logFile)
+	}
+
 	// Create a new resource provider server and listen for and serve incoming connections.
 	return provider.Main(module, func(host *provider.HostClient) (lumirpc.ResourceProviderServer, error) {
 		// Create a new bridge provider.
-		return NewProvider(context.TODO(), host, module, version, info.P, info, pulumiSchema), nil
+		p := NewProvider(context.TODO(), host, module, version, info.P, info, pulumiSchema)
+
+		var server lumirpc.ResourceProviderServer = p
+		if logFile != nil {
+			server = newGrpcLogProvider(server, p, logFile)
+		}
+		if tracing != nil {
+			server = newTracingProvider(server, p, tracing.tp)
+		}
+		return server, nil
 	})
 }
@@ -0,0 +1,247 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// ModuleStrategy picks the Pulumi module of a TF resource or data source that has no explicit token. It is given the
+// TF name without the provider's resource prefix, e.g. "s3_bucket" for "aws_s3_bucket", and returns the module and the
+// rest of the name, from which the Pulumi name is derived, e.g. "s3" and "bucket". If rest is empty, the Pulumi name
+// is derived from the whole name instead.
+type ModuleStrategy func(name string) (module string, rest string)
+
+// SingleModule returns a ModuleStrategy that places every resource and data source in the given module, e.g. "index".
+func SingleModule(module string) ModuleStrategy {
+	return func(name string) (string, string) {
+		return module, name
+	}
+}
+
+// FirstSegmentModule returns a ModuleStrategy that takes the module from the first segment of the name, so that
+// "aws_s3_bucket" becomes "aws:s3/bucket:Bucket". Names with a single segment are placed in defaultModule.
+func FirstSegmentModule(defaultModule string) ModuleStrategy {
+	return func(name string) (string, string) {
+		segments := strings.SplitN(name, "_", 2)
+		if len(segments) < 2 || segments[0] == "" || segments[1] == "" {
+			return defaultModule, name
+		}
+		return segments[0], segments[1]
+	}
+}
+
+// PrefixModules returns a ModuleStrategy that takes the module from a table of name prefixes, so that with the prefix
+// "ec2_transit_gateway" mapped to "ec2transitgateway", "aws_ec2_transit_gateway_route" becomes
+// "aws:ec2transitgateway/route:Route". The longest prefix that matches whole segments of the name wins. Names that
+// match no prefix are placed in defaultModule.
+func PrefixModules(defaultModule string, prefixes map[string]string) ModuleStrategy {
+	keys := make([]string, 0, len(prefixes))
+	for prefix := range prefixes {
+		keys = append(keys, prefix)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	return func(name string) (string, string) {
+		for _, prefix := range keys {
+			if name == prefix {
+				return prefixes[prefix], ""
+			}
+			if strings.HasPrefix(name, prefix+"_") {
+				return prefixes[prefix], name[len(prefix)+1:]
+			}
+		}
+		return defaultModule, name
+	}
+}
+
+// AssignTokens fills in the tokens of every TF resource and data source in the provider that is not mapped explicitly,
+// choosing modules with the given strategy. Resources are named by PascalCasing the rest of their TF names, and data
+// sources by prefixing that with "get". Explicit tokens are never changed, and entries in Resources and DataSources
+// that have other overrides but no token are given one. Names in IgnoreMappings are skipped.
+//
+// If a token is already taken, by an explicit entry or by another TF name, or differs from a taken token only in
+// case, AssignTokens leaves the name unmapped and returns an error that lists every such collision, so that a new
+// upstream resource can never silently change the token of an existing one. Names that lack the provider's prefix or
+// for which the strategy returns no module are likewise left unmapped and listed in the error. Collisions are resolved by mapping one of
+// the names explicitly. AssignTokens must be called before the ProviderInfo is used to generate or serve the package.
+func (info *ProviderInfo) AssignTokens(pkg string, strategy ModuleStrategy) error {
+	contract.Assert(info.P != nil)
+
+	ignored := map[string]bool{}
+	for _, name := range info.IgnoreMappings {
+		ignored[name] = true
+	}
+
+	var result error
+
+	if info.Resources == nil {
+		info.Resources = map[string]*ResourceInfo{}
+	}
+	explicit := map[string]string{}
+	for rawname, res := range info.Resources {
+		if res != nil && res.Tok != "" {
+			explicit[rawname] = string(res.Tok)
+		}
+	}
+	taken := newTokenOwners(explicit)
+	for _, rawname := range unmappedNames(info.P.ResourcesMap(), ignored, func(rawname string) bool {
+		res := info.Resources[rawname]
+		return res != nil && res.Tok != ""
+	}) {
+		module, name, err := info.tokenParts(rawname, strategy)
+		if err != nil {
+			result = multierror.Append(result, err)
+			continue
+		}
+		tok := string(MakeResource(pkg, module, TerraformToPulumiName(name, nil, nil, true)))
+		if err := taken.claim(tok, rawname); err != nil {
+			result = multierror.Append(result, err)
+			continue
+		}
+		if res := info.Resources[rawname]; res != nil {
+			res.Tok = tokens.Type(tok)
+		} else {
+			info.Resources[rawname] = &ResourceInfo{Tok: tokens.Type(tok)}
+		}
+	}
+
+	if info.DataSources == nil {
+		info.DataSources = map[string]*DataSourceInfo{}
+	}
+	explicit = map[string]string{}
+	for rawname, ds := range info.DataSources {
+		if ds != nil && ds.Tok != "" {
+			explicit[rawname] = string(ds.Tok)
+		}
+	}
+	taken = newTokenOwners(explicit)
+	for _, rawname := range unmappedNames(info.P.DataSourcesMap(), ignored, func(rawname string) bool {
+		ds := info.DataSources[rawname]
+		return ds != nil && ds.Tok != ""
+	}) {
+		module, name, err := info.tokenParts(rawname, strategy)
+		if err != nil {
+			result = multierror.Append(result, err)
+			continue
+		}
+		tok := string(MakeDataSource(pkg, module, "get"+TerraformToPulumiName(name, nil, nil, true)))
+		if err := taken.claim(tok, rawname); err != nil {
+			result = multierror.Append(result, err)
+			continue
+		}
+		if ds := info.DataSources[rawname]; ds != nil {
+			ds.Tok = tokens.ModuleMember(tok)
+		} else {
+			info.DataSources[rawname] = &DataSourceInfo{Tok: tokens.ModuleMember(tok)}
+		}
+	}
+
+	return result
+}
+
+// MustAssignTokens calls AssignTokens and panics if it fails.
+func (info *ProviderInfo) MustAssignTokens(pkg string, strategy ModuleStrategy) {
+	if err := info.AssignTokens(pkg, strategy); err != nil {
+		panic(err)
+	}
+}
+
+// tokenParts returns the module of the given TF resource or data source and the part of its name from which its
+// Pulumi name is derived.
+func (info *ProviderInfo) tokenParts(rawname string, strategy ModuleStrategy) (string, string, error) {
+	prefix := info.GetResourcePrefix() + "_"
+	if !strings.HasPrefix(rawname, prefix) || len(rawname) == len(prefix) {
+		return "", "", fmt.Errorf("cannot assign a token to %q: its name does not start with %q", rawname, prefix)
+	}
+	name := rawname[len(prefix):]
+
+	module, rest := strategy(name)
+	if rest == "" {
+		rest = name
+	}
+	if module == "" {
+		return "", "", fmt.Errorf("cannot assign a token to %q: the module strategy returned no module", rawname)
+	}
+	return module, rest, nil
+}
+
+// unmappedNames returns the sorted names of the TF resources or data sources that are neither ignored nor mapped.
+func unmappedNames(m shim.ResourceMap, ignored map[string]bool, mapped func(rawname string) bool) []string {
+	var names []string
+	m.Range(func(rawname string, _ shim.Resource) bool {
+		if !ignored[rawname] && !mapped(rawname) {
+			names = append(names, rawname)
+		}
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
+
+// tokenOwners maps the tokens that have been assigned, lower-cased so that tokens that differ only in case collide,
+// to the TF names they were assigned to. Such tokens would produce clashing file names on case-insensitive file
+// systems.
+type tokenOwners map[string]tokenOwner
+
+type tokenOwner struct {
+	tok     string
+	rawname string
+}
+
+// newTokenOwners returns the owners of the given explicit tokens, keyed by TF name. Collisions between explicit tokens
+// are not reported, since they are not of AssignTokens' making.
+func newTokenOwners(explicit map[string]string) tokenOwners {
+	rawnames := make([]string, 0, len(explicit))
+	for rawname := range explicit {
+		rawnames = append(rawnames, rawname)
+	}
+	sort.Strings(rawnames)
+
+	owners := tokenOwners{}
+	for _, rawname := range rawnames {
+		contract.IgnoreError(owners.claim(explicit[rawname], rawname))
+	}
+	return owners
+}
+
+// claim records that the given token is assigned to the given TF name. It returns an error if the token, or a token
+// that differs from it only in case, is already assigned to another name.
+func (owners tokenOwners) claim(tok, rawname string) error {
+	key := strings.ToLower(tok)
+	if owner, ok := owners[key]; ok {
+		if owner.tok == tok {
+			return fmt.Errorf("cannot assign the token %q to %q: it is already used by %q; map one of them "+
+				"explicitly", tok, rawname, owner.rawname)
+		}
+		return fmt.Errorf("cannot assign the token %q to %q: it differs only in case from the token %q of %q; map "+
+			"one of them explicitly", tok, rawname, owner.tok, owner.rawname)
+	}
+	owners[key] = tokenOwner{tok: tok, rawname: rawname}
+	return nil
+}
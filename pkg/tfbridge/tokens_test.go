@@ -0,0 +1,151 @@
+package tfbridge
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func testTokensProvider(resources []string, dataSources []string) *schema.Provider {
+	resourcesMap, dataSourcesMap := schema.ResourceMap{}, schema.ResourceMap{}
+	for _, name := range resources {
+		resourcesMap[name] = (&schema.Resource{Schema: schema.SchemaMap{}}).Shim()
+	}
+	for _, name := range dataSources {
+		dataSourcesMap[name] = (&schema.Resource{Schema: schema.SchemaMap{}}).Shim()
+	}
+	return &schema.Provider{
+		Schema:         schema.SchemaMap{},
+		ResourcesMap:   resourcesMap,
+		DataSourcesMap: dataSourcesMap,
+	}
+}
+
+func TestModuleStrategies(t *testing.T) {
+	tests := []struct {
+		strategy       ModuleStrategy
+		name           string
+		expectedModule string
+		expectedRest   string
+	}{
+		{SingleModule("index"), "s3_bucket", "index", "s3_bucket"},
+		{FirstSegmentModule("index"), "s3_bucket", "s3", "bucket"},
+		{FirstSegmentModule("index"), "s3_bucket_policy", "s3", "bucket_policy"},
+		{FirstSegmentModule("index"), "instance", "index", "instance"},
+		{PrefixModules("index", map[string]string{"ec2": "ec2", "ec2_transit_gateway": "ec2transitgateway"}),
+			"ec2_transit_gateway_route", "ec2transitgateway", "route"},
+		{PrefixModules("index", map[string]string{"ec2": "ec2", "ec2_transit_gateway": "ec2transitgateway"}),
+			"ec2_instance", "ec2", "instance"},
+		{PrefixModules("index", map[string]string{"ec2": "ec2"}), "ec2", "ec2", ""},
+		{PrefixModules("index", map[string]string{"ec2": "ec2"}), "ec2transit", "index", "ec2transit"},
+	}
+	for _, tt := range tests {
+		module, rest := tt.strategy(tt.name)
+		assert.Equal(t, tt.expectedModule, module, tt.name)
+		assert.Equal(t, tt.expectedRest, rest, tt.name)
+	}
+}
+
+func TestAssignTokens(t *testing.T) {
+	info := ProviderInfo{
+		Name: "test",
+		P: testTokensProvider(
+			[]string{"test_s3_bucket", "test_s3_bucket_policy", "test_instance", "test_custom", "test_fields",
+				"test_ignored", "test_s3bucket"},
+			[]string{"test_s3_bucket", "test_ami"},
+		).Shim(),
+		Resources: map[string]*ResourceInfo{
+			// Explicit tokens are never changed.
+			"test_custom": {Tok: "test:s3/custom:Bucket"},
+			// Entries with other overrides are given a token.
+			"test_fields": {Fields: map[string]*SchemaInfo{"name": {Name: "title"}}},
+		},
+		DataSources: map[string]*DataSourceInfo{
+			"test_ami": {Tok: "test:ec2/getAmi:getAmi"},
+		},
+		IgnoreMappings: []string{"test_ignored"},
+	}
+
+	require.NoError(t, info.AssignTokens("test", FirstSegmentModule("index")))
+
+	toks := map[string]tokens.Type{}
+	for name, res := range info.Resources {
+		toks[name] = res.Tok
+	}
+	assert.Equal(t, map[string]tokens.Type{
+		"test_custom":           "test:s3/custom:Bucket",
+		"test_fields":           "test:index/fields:Fields",
+		"test_instance":         "test:index/instance:Instance",
+		"test_s3_bucket":        "test:s3/bucket:Bucket",
+		"test_s3_bucket_policy": "test:s3/bucketPolicy:BucketPolicy",
+		"test_s3bucket":         "test:index/s3bucket:S3bucket",
+	}, toks)
+	assert.Equal(t, "title", info.Resources["test_fields"].Fields["name"].Name)
+
+	assert.Equal(t, tokens.ModuleMember("test:s3/getBucket:getBucket"), info.DataSources["test_s3_bucket"].Tok)
+	assert.Equal(t, tokens.ModuleMember("test:ec2/getAmi:getAmi"), info.DataSources["test_ami"].Tok)
+
+	// Assigning tokens again changes nothing.
+	before := info.Resources["test_s3_bucket"].Tok
+	require.NoError(t, info.AssignTokens("test", FirstSegmentModule("index")))
+	assert.Equal(t, before, info.Resources["test_s3_bucket"].Tok)
+}
+
+func TestAssignTokensCollisions(t *testing.T) {
+	info := ProviderInfo{
+		Name: "test",
+		P: testTokensProvider(
+			[]string{"test_index_instance", "test_instance", "test_net_s3_bucket", "test_net_s3bucket", "test_widget"},
+			nil,
+		).Shim(),
+		Resources: map[string]*ResourceInfo{
+			"test_custom": {Tok: "test:index/widget:Widget"},
+		},
+	}
+
+	err := info.AssignTokens("test", FirstSegmentModule("index"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `cannot assign the token "test:index/instance:Instance" to "test_instance": `+
+		`it is already used by "test_index_instance"`)
+	assert.Contains(t, err.Error(), `cannot assign the token "test:net/s3bucket:S3bucket" to "test_net_s3bucket": `+
+		`it differs only in case from the token "test:net/s3Bucket:S3Bucket" of "test_net_s3_bucket"`)
+	assert.Contains(t, err.Error(), `cannot assign the token "test:index/widget:Widget" to "test_widget": `+
+		`it is already used by "test_custom"`)
+
+	// Names whose tokens collide are left unmapped, so that they can be mapped explicitly.
+	assert.Equal(t, tokens.Type("test:index/instance:Instance"), info.Resources["test_index_instance"].Tok)
+	assert.Nil(t, info.Resources["test_instance"])
+	assert.Nil(t, info.Resources["test_net_s3bucket"])
+	assert.Nil(t, info.Resources["test_widget"])
+
+	info.Resources["test_instance"] = &ResourceInfo{Tok: "test:index/legacyInstance:LegacyInstance"}
+	info.Resources["test_net_s3bucket"] = &ResourceInfo{Tok: "test:net/legacyBucket:LegacyBucket"}
+	info.Resources["test_widget"] = &ResourceInfo{Tok: "test:index/gadget:Gadget"}
+	assert.NoError(t, info.AssignTokens("test", FirstSegmentModule("index")))
+}
+
+func TestAssignTokensErrors(t *testing.T) {
+	info := ProviderInfo{
+		Name: "test",
+		P:    testTokensProvider([]string{"other_widget", "test_widget"}, []string{"other_gadget"}).Shim(),
+	}
+	err := info.AssignTokens("test", SingleModule("index"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `cannot assign a token to "other_widget": its name does not start with "test_"`)
+	assert.Contains(t, err.Error(), `cannot assign a token to "other_gadget": its name does not start with "test_"`)
+
+	// The other names are still mapped.
+	assert.Nil(t, info.Resources["other_widget"])
+	assert.Equal(t, tokens.Type("test:index/widget:Widget"), info.Resources["test_widget"].Tok)
+
+	info = ProviderInfo{Name: "test", P: testTokensProvider([]string{"test_widget"}, nil).Shim()}
+	err = info.AssignTokens("test", SingleModule(""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `cannot assign a token to "test_widget": the module strategy returned no module`)
+
+	assert.Panics(t, func() { info.MustAssignTokens("test", SingleModule("")) })
+}
@@ -0,0 +1,330 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"os"
+	"time"
+
+	pbempty "github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracingEndpointEnvVar names the environment variable that, when set to a host:port, causes Serve to export
+	// OpenTelemetry spans to that OTLP gRPC endpoint. Transport security, headers and the like are configured with
+	// the standard OTEL_EXPORTER_OTLP_* environment variables.
+	TracingEndpointEnvVar = "PULUMI_TFBRIDGE_OTEL_ENDPOINT"
+	// TracingFileEnvVar names the environment variable that, when set to a file path, causes Serve to append
+	// OpenTelemetry spans to that file as JSON. This requires no collector, and so also works in air-gapped CI.
+	TracingFileEnvVar = "PULUMI_TFBRIDGE_OTEL_FILE"
+)
+
+// tracerName is the instrumentation name of all spans created by the bridge.
+const tracerName = "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+
+// Span attribute keys.
+const (
+	tracingURNKey      = attribute.Key("pulumi.urn")
+	tracingTypeKey     = attribute.Key("pulumi.type")
+	tracingTokenKey    = attribute.Key("pulumi.token")
+	tracingTFNameKey   = attribute.Key("terraform.name")
+	tracingProviderKey = attribute.Key("terraform.provider")
+)
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// tracingBatchTimeout is the longest that a finished span waits in the batch span processor before it is exported.
+// Spans are exported in the background, so the engine may terminate the provider before the last batch is exported.
+const tracingBatchTimeout = time.Second
+
+// tracing is the tracer provider installed by initTracing, together with the resources it owns.
+type tracing struct {
+	tp   *sdktrace.TracerProvider
+	file *os.File // the file that spans are written to, if any.
+}
+
+// initTracing installs a global OpenTelemetry tracer provider if one of TracingEndpointEnvVar or TracingFileEnvVar
+// is set. It returns nil if tracing is not enabled.
+func initTracing(ctx context.Context, module, version string) (*tracing, error) {
+	var exporter sdktrace.SpanExporter
+	var file *os.File
+	if endpoint := os.Getenv(TracingEndpointEnvVar); endpoint != "" {
+		otlp, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(endpoint))
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating OTLP exporter for %q", endpoint)
+		}
+		exporter = otlp
+	} else if path := os.Getenv(TracingFileEnvVar); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, errors.Wrapf(err, "opening trace file %q", path)
+		}
+		stdout, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			contract.IgnoreClose(f)
+			return nil, errors.Wrapf(err, "creating trace file exporter for %q", path)
+		}
+		exporter, file = stdout, f
+	} else {
+		return nil, nil
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(tracingBatchTimeout)),
+		sdktrace.WithResource(sdkresource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String("pulumi-resource-"+module),
+			semconv.ServiceVersionKey.String(version),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+	return &tracing{tp: tp, file: file}, nil
+}
+
+// shutdown exports any pending spans and releases the resources held by the tracer provider.
+func (t *tracing) shutdown(ctx context.Context) error {
+	err := t.tp.Shutdown(ctx)
+	if t.file != nil {
+		if closeErr := t.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// endSpan records the outcome of an operation on its span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// startTFSpan starts a span around a call into the underlying Terraform provider.
+func (p *Provider) startTFSpan(ctx context.Context, op string, tfName string,
+	attrs ...attribute.KeyValue) trace.Span {
+
+	return p.startSpan(ctx, "tf."+op, tfName, attrs...)
+}
+
+// startConversionSpan starts a span around the conversion of a resource's or data source's values between their
+// Pulumi and Terraform forms, which can take a while for large or deeply nested values.
+func (p *Provider) startConversionSpan(ctx context.Context, op string, tfName string,
+	attrs ...attribute.KeyValue) trace.Span {
+
+	return p.startSpan(ctx, "tfbridge."+op, tfName, attrs...)
+}
+
+func (p *Provider) startSpan(ctx context.Context, name string, tfName string,
+	attrs ...attribute.KeyValue) trace.Span {
+
+	attrs = append(attrs, tracingProviderKey.String(p.module), tracingTFNameKey.String(tfName))
+	_, span := tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+	return span
+}
+
+// tracingProvider wraps a bridged provider and creates a span around every RPC it handles. Calls into the underlying
+// Terraform provider made while handling an RPC are recorded as children of the RPC's span.
+type tracingProvider struct {
+	server pulumirpc.ResourceProviderServer // the server that handles the RPCs.
+	p      *Provider                        // the bridged provider, used to look up span attributes.
+	tp     *sdktrace.TracerProvider         // the tracer provider to flush when the engine cancels the provider.
+}
+
+var _ pulumirpc.ResourceProviderServer = (*tracingProvider)(nil)
+
+func newTracingProvider(server pulumirpc.ResourceProviderServer, p *Provider,
+	tp *sdktrace.TracerProvider) *tracingProvider {
+	return &tracingProvider{server: server, p: p, tp: tp}
+}
+
+// resourceAttributes returns the span attributes for an RPC that targets the resource with the given URN.
+func (t *tracingProvider) resourceAttributes(urn string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{tracingURNKey.String(urn)}
+	if urn == "" {
+		return attrs
+	}
+	typ := resource.URN(urn).Type()
+	attrs = append(attrs, tracingTypeKey.String(string(typ)))
+	if res, ok := t.p.resources[typ]; ok {
+		attrs = append(attrs, tracingTFNameKey.String(res.TFName))
+	}
+	return attrs
+}
+
+// start starts the span for an RPC.
+func (t *tracingProvider) start(ctx context.Context, method string,
+	attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+
+	attrs = append(attrs, tracingProviderKey.String(t.p.module))
+	return tracer().Start(ctx, "pulumirpc.ResourceProvider/"+method,
+		trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+}
+
+// end ends the span for an RPC. The span is exported in the background by the batch span processor, so that RPCs
+// never wait for a slow or unreachable collector.
+func (t *tracingProvider) end(span trace.Span, err error) {
+	endSpan(span, err)
+}
+
+func (t *tracingProvider) GetSchema(ctx context.Context,
+	req *pulumirpc.GetSchemaRequest) (*pulumirpc.GetSchemaResponse, error) {
+	ctx, span := t.start(ctx, "GetSchema")
+	resp, err := t.server.GetSchema(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
+
+func (t *tracingProvider) CheckConfig(ctx context.Context,
+	req *pulumirpc.CheckRequest) (*pulumirpc.CheckResponse, error) {
+	ctx, span := t.start(ctx, "CheckConfig", tracingURNKey.String(req.GetUrn()))
+	resp, err := t.server.CheckConfig(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
+
+func (t *tracingProvider) DiffConfig(ctx context.Context,
+	req *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
+	ctx, span := t.start(ctx, "DiffConfig", tracingURNKey.String(req.GetUrn()))
+	resp, err := t.server.DiffConfig(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
+
+func (t *tracingProvider) Configure(ctx context.Context,
+	req *pulumirpc.ConfigureRequest) (*pulumirpc.ConfigureResponse, error) {
+	ctx, span := t.start(ctx, "Configure")
+	resp, err := t.server.Configure(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
+
+func (t *tracingProvider) Invoke(ctx context.Context,
+	req *pulumirpc.InvokeRequest) (*pulumirpc.InvokeResponse, error) {
+	attrs := []attribute.KeyValue{tracingTokenKey.String(req.GetTok())}
+	if ds, ok := t.p.dataSources[tokens.ModuleMember(req.GetTok())]; ok {
+		attrs = append(attrs, tracingTFNameKey.String(ds.TFName))
+	}
+	ctx, span := t.start(ctx, "Invoke", attrs...)
+	resp, err := t.server.Invoke(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
+
+func (t *tracingProvider) StreamInvoke(req *pulumirpc.InvokeRequest,
+	server pulumirpc.ResourceProvider_StreamInvokeServer) error {
+	_, span := t.start(server.Context(), "StreamInvoke", tracingTokenKey.String(req.GetTok()))
+	err := t.server.StreamInvoke(req, server)
+	t.end(span, err)
+	return err
+}
+
+func (t *tracingProvider) Call(ctx context.Context,
+	req *pulumirpc.CallRequest) (*pulumirpc.CallResponse, error) {
+	ctx, span := t.start(ctx, "Call", tracingTokenKey.String(req.GetTok()))
+	resp, err := t.server.Call(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
+
+func (t *tracingProvider) Check(ctx context.Context,
+	req *pulumirpc.CheckRequest) (*pulumirpc.CheckResponse, error) {
+	ctx, span := t.start(ctx, "Check", t.resourceAttributes(req.GetUrn())...)
+	resp, err := t.server.Check(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
+
+func (t *tracingProvider) Diff(ctx context.Context,
+	req *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
+	ctx, span := t.start(ctx, "Diff", t.resourceAttributes(req.GetUrn())...)
+	resp, err := t.server.Diff(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
+
+func (t *tracingProvider) Create(ctx context.Context,
+	req *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
+	ctx, span := t.start(ctx, "Create", t.resourceAttributes(req.GetUrn())...)
+	resp, err := t.server.Create(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
+
+func (t *tracingProvider) Read(ctx context.Context,
+	req *pulumirpc.ReadRequest) (*pulumirpc.ReadResponse, error) {
+	ctx, span := t.start(ctx, "Read", t.resourceAttributes(req.GetUrn())...)
+	resp, err := t.server.Read(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
+
+func (t *tracingProvider) Update(ctx context.Context,
+	req *pulumirpc.UpdateRequest) (*pulumirpc.UpdateResponse, error) {
+	ctx, span := t.start(ctx, "Update", t.resourceAttributes(req.GetUrn())...)
+	resp, err := t.server.Update(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
+
+func (t *tracingProvider) Delete(ctx context.Context,
+	req *pulumirpc.DeleteRequest) (*pbempty.Empty, error) {
+	ctx, span := t.start(ctx, "Delete", t.resourceAttributes(req.GetUrn())...)
+	resp, err := t.server.Delete(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
+
+func (t *tracingProvider) Construct(ctx context.Context,
+	req *pulumirpc.ConstructRequest) (*pulumirpc.ConstructResponse, error) {
+	ctx, span := t.start(ctx, "Construct", tracingTypeKey.String(req.GetType()))
+	resp, err := t.server.Construct(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
+
+// Cancel is the engine's signal that the provider is about to shut down, so the spans recorded so far are exported
+// before it returns.
+func (t *tracingProvider) Cancel(ctx context.Context, req *pbempty.Empty) (*pbempty.Empty, error) {
+	ctx, span := t.start(ctx, "Cancel")
+	resp, err := t.server.Cancel(ctx, req)
+	t.end(span, err)
+	contract.IgnoreError(t.tp.ForceFlush(ctx))
+	return resp, err
+}
+
+func (t *tracingProvider) GetPluginInfo(ctx context.Context, req *pbempty.Empty) (*pulumirpc.PluginInfo, error) {
+	ctx, span := t.start(ctx, "GetPluginInfo")
+	resp, err := t.server.GetPluginInfo(ctx, req)
+	t.end(span, err)
+	return resp, err
+}
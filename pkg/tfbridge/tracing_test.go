@@ -0,0 +1,79 @@
+package tfbridge
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingProvider(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(prev)
+
+	p := newGrpcLogTestProvider()
+	p.module = "test"
+	server := newTracingProvider(p, p, tp)
+
+	urn := resource.NewURN("stack", "project", "", "NestedSecretResource", "name")
+	_, err := server.Create(context.Background(), &pulumirpc.CreateRequest{Urn: string(urn)})
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	names := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range spans {
+		names[s.Name()] = s
+	}
+
+	rpc, ok := names["pulumirpc.ResourceProvider/Create"]
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Contains(t, rpc.Attributes(), attribute.String("pulumi.urn", string(urn)))
+	assert.Contains(t, rpc.Attributes(), attribute.String("terraform.name", "nested_secret_resource"))
+
+	for _, op := range []string{"tf.Diff", "tf.Apply", "tfbridge.MakeTerraformResult"} {
+		span, ok := names[op]
+		if assert.True(t, ok, op) {
+			assert.Equal(t, rpc.SpanContext().SpanID(), span.Parent().SpanID())
+			assert.Contains(t, span.Attributes(), attribute.String("terraform.name", "nested_secret_resource"))
+		}
+	}
+}
+
+func TestTracingFileIsFlushedAndClosedOnShutdown(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prev)
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	os.Setenv(TracingFileEnvVar, path)
+	defer os.Unsetenv(TracingFileEnvVar)
+
+	tracing, err := initTracing(context.Background(), "test", "1.0.0")
+	require.NoError(t, err)
+	require.NotNil(t, tracing)
+
+	_, span := tracer().Start(context.Background(), "test-span")
+	span.End()
+	require.NoError(t, tracing.shutdown(context.Background()))
+
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "test-span")
+
+	_, err = tracing.file.WriteString("more")
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
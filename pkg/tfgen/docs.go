@@ -19,9 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -101,6 +99,9 @@ type entityDocs struct {
 
 	// Import is the import details for the resource
 	Import string
+
+	// Elided lists the parts of the docs that contained an <elided> reference and were dropped.
+	Elided []string
 }
 
 func (ed *entityDocs) getOrCreateArgumentDocs(argumentName string) (*argumentDocs, bool) {
@@ -127,66 +128,22 @@ const (
 	DataSourceDocs DocKind = "data-sources"
 )
 
-var repoPaths sync.Map
-
-func getRepoPath(gitHost string, org string, provider string, version string) (string, error) {
-	moduleCoordinates := fmt.Sprintf("%s/%s/terraform-provider-%s", gitHost, org, provider)
-	if version != "" {
-		moduleCoordinates = fmt.Sprintf("%s/%s", moduleCoordinates, version)
-	}
-
-	if path, ok := repoPaths.Load(moduleCoordinates); ok {
-		return path.(string), nil
-	}
-
-	curWd, err := os.Getwd()
-	if err != nil {
-		return "", fmt.Errorf("error finding current working directory: %w", err)
-	}
-	if filepath.Base(curWd) != "provider" {
-		curWd = filepath.Join(curWd, "provider")
-	}
-
-	command := exec.Command("go", "mod", "download", "-json", moduleCoordinates)
-	command.Dir = curWd
-	output, err := command.CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("error running 'go mod download -json' for module: %w", err)
-	}
-
-	target := struct {
-		Version string
-		Dir     string
-		Error   string
-	}{}
-
-	if err := json.Unmarshal(output, &target); err != nil {
-		return "", fmt.Errorf("error parsing output of 'go mod download -json' for module: %w", err)
-	}
-
-	if target.Error != "" {
-		return "", fmt.Errorf("error from 'go mod download -json' for module: %s", target.Error)
-	}
-
-	repoPaths.Store(moduleCoordinates, target.Dir)
-
-	return target.Dir, nil
-}
-
-func getMarkdownDetails(org string, provider string, resourcePrefix string, kind DocKind, rawname string,
-	info tfbridge.ResourceOrDataSourceInfo, providerModuleVersion string, githost string) ([]byte, string, bool) {
+// getMarkdownDetails returns the markdown for the given entity, either from its DocInfo or from the generator's
+// DocsSource. An error is returned if the DocsSource could not be opened.
+func getMarkdownDetails(g *Generator, resourcePrefix string, kind DocKind, rawname string,
+	info tfbridge.ResourceOrDataSourceInfo) ([]byte, string, bool, error) {
 
 	var docinfo *tfbridge.DocInfo
 	if info != nil {
 		docinfo = info.GetDocs()
 	}
 	if docinfo != nil && len(docinfo.Markdown) != 0 {
-		return docinfo.Markdown, "", true
+		return docinfo.Markdown, "", true, nil
 	}
 
-	repoPath, err := getRepoPath(githost, org, provider, providerModuleVersion)
+	repo, err := g.openDocsSource()
 	if err != nil {
-		return nil, "", false
+		return nil, "", false, err
 	}
 
 	possibleMarkdownNames := []string{
@@ -206,12 +163,8 @@ func getMarkdownDetails(org string, provider string, resourcePrefix string, kind
 		possibleMarkdownNames = append(possibleMarkdownNames, docinfo.Source)
 	}
 
-	markdownBytes, markdownFileName, found := readMarkdown(repoPath, kind, possibleMarkdownNames)
-	if !found {
-		return nil, "", false
-	}
-
-	return markdownBytes, markdownFileName, true
+	markdownBytes, markdownFileName, found := readMarkdown(repo, kind, possibleMarkdownNames)
+	return markdownBytes, markdownFileName, found, nil
 }
 
 func (k DocKind) String() string {
@@ -246,12 +199,16 @@ func getDocsForProvider(g *Generator, org string, provider string, resourcePrefi
 		return entityDocs{}, nil
 	}
 
-	markdownBytes, markdownFileName, found := getMarkdownDetails(org, provider, resourcePrefix, kind, rawname, info, providerModuleVersion, githost)
+	markdownBytes, markdownFileName, found, err := getMarkdownDetails(g, resourcePrefix, kind, rawname, info)
 	if !found {
-		entitiesMissingDocs++
+		g.docStats.inc(&g.docStats.entitiesMissingDocs)
+		g.report.recordDocs(kind, rawname, "", false, entityDocs{})
 		msg := fmt.Sprintf("could not find docs for %v %v. Override the Docs property in the %v mapping. See type tfbridge.DocInfo for details.", kind, formatEntityName(rawname), kind)
+		if err != nil {
+			msg = fmt.Sprintf("could not find docs for %v %v: %v", kind, formatEntityName(rawname), err)
+		}
 
-		if isTruthy(os.Getenv("PULUMI_MISSING_DOCS_ERROR")) {
+		if g.missingDocsError {
 			g.error(msg)
 			return entityDocs{}, fmt.Errorf(msg)
 		}
@@ -264,10 +221,16 @@ func getDocsForProvider(g *Generator, org string, provider string, resourcePrefi
 		return entityDocs{}, nil
 	}
 
+	markdownBytes, err = g.editMarkdown(kind, rawname, markdownFileName, info, markdownBytes)
+	if err != nil {
+		return entityDocs{}, err
+	}
+
 	doc, err := parseTFMarkdown(g, info, kind, string(markdownBytes), markdownFileName, resourcePrefix, rawname)
 	if err != nil {
 		return entityDocs{}, err
 	}
+	g.report.recordDocs(kind, rawname, markdownFileName, true, doc)
 
 	var docinfo *tfbridge.DocInfo
 	if info != nil {
@@ -294,36 +257,36 @@ func getDocsForProvider(g *Generator, org string, provider string, resourcePrefi
 }
 
 // checkIfNewDocsExist checks if the new docs root exists
-func checkIfNewDocsExist(repo string) bool {
+func checkIfNewDocsExist(repo afero.Fs) bool {
 	// Check if the new docs path exists
-	newDocsPath := filepath.Join(repo, "docs", "resources")
-	_, err := os.Stat(newDocsPath)
+	newDocsPath := filepath.Join("docs", "resources")
+	_, err := repo.Stat(newDocsPath)
 	return !os.IsNotExist(err)
 }
 
 // getDocsPath finds the correct docs path for the repo/kind
-func getDocsPath(repo string, kind DocKind) string {
+func getDocsPath(repo afero.Fs, kind DocKind) string {
 	// Check if the new docs path exists
 	newDocsExist := checkIfNewDocsExist(repo)
 
 	if !newDocsExist {
 		// If the new path doesn't exist, use the old docs path.
 		kindString := string([]rune(kind)[0]) // We only want the first letter because the old path uses "r" and "d"
-		return filepath.Join(repo, "website", "docs", kindString)
+		return filepath.Join("website", "docs", kindString)
 	}
 
 	// Otherwise use the new location path.
 	kindString := string(kind)
-	return filepath.Join(repo, "docs", kindString)
+	return filepath.Join("docs", kindString)
 }
 
 // readMarkdown searches all possible locations for the markdown content
-func readMarkdown(repo string, kind DocKind, possibleLocations []string) ([]byte, string, bool) {
+func readMarkdown(repo afero.Fs, kind DocKind, possibleLocations []string) ([]byte, string, bool) {
 	locationPrefix := getDocsPath(repo, kind)
 
 	for _, name := range possibleLocations {
 		location := filepath.Join(locationPrefix, name)
-		markdownBytes, err := ioutil.ReadFile(location)
+		markdownBytes, err := afero.ReadFile(repo, location)
 		if err == nil {
 			return markdownBytes, name, true
 		}
@@ -617,7 +580,7 @@ func (p *tfMarkdownParser) parseSection(section []string) error {
 	switch header {
 	case "Timeout", "Timeouts", "User Project Override", "User Project Overrides":
 		p.g.debug("Ignoring doc section [%v] for [%v]", header, p.rawname)
-		ignoredDocHeaders[header]++
+		p.g.docStats.ignoreHeader(header)
 		return nil
 	case "Example Usage":
 		sectionKind = sectionExampleUsage
@@ -654,7 +617,7 @@ func (p *tfMarkdownParser) parseSection(section []string) error {
 		}
 		if hasExamples && sectionKind != sectionExampleUsage && sectionKind != sectionImports {
 			p.g.warn("Unexpected code snippets in section '%v' for %v '%v'. The HCL code will be converted if possible, but may not display correctly in the generated docs.", header, p.kind, p.rawname)
-			unexpectedSnippets++
+			p.g.docStats.inc(&p.g.docStats.unexpectedSnippets)
 		}
 
 		// Now process the content based on the H2 topic. These are mostly standard across TF's docs.
@@ -909,8 +872,12 @@ func (p *tfMarkdownParser) parseFrontMatter(subsection []string) {
 	}
 }
 
-var (
-	ignoredDocHeaders      = make(map[string]int)
+// docStats counts the problems encountered while processing docs, which are summarized by printDocStats. Docs may be
+// processed concurrently, so counters must be incremented with inc.
+type docStats struct {
+	m sync.Mutex
+
+	ignoredDocHeaders      map[string]int
 	elidedDescriptions     int // i.e., we discard the entire description, including examples
 	elidedDescriptionsOnly int // we discarded the description proper, but were able to preserve the examples
 	elidedArguments        int
@@ -928,7 +895,24 @@ var (
 
 	entitiesMissingDocs int
 	unexpectedSnippets  int
-)
+}
+
+// inc increments one of the counters in s.
+func (s *docStats) inc(counter *int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	*counter++
+}
+
+// ignoreHeader records that a doc section with the given header was ignored.
+func (s *docStats) ignoreHeader(header string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.ignoredDocHeaders == nil {
+		s.ignoredDocHeaders = make(map[string]int)
+	}
+	s.ignoredDocHeaders[header]++
+}
 
 // isBlank returns true if the line is all whitespace.
 func isBlank(line string) bool {
@@ -938,52 +922,52 @@ func isBlank(line string) bool {
 // printDocStats outputs warnings and, if flags are set, stdout diagnostics pertaining to documentation conversion.
 func (g *Generator) printDocStats() {
 	// These summaries are printed on each run, to help us keep an eye on success/failure rates.
-	if entitiesMissingDocs > 0 {
-		g.warn("%d entities have missing docs.", entitiesMissingDocs)
+	if g.docStats.entitiesMissingDocs > 0 {
+		g.warn("%d entities have missing docs.", g.docStats.entitiesMissingDocs)
 	}
 
-	if unexpectedSnippets > 0 {
-		g.warn("%d entity document sections contained unexpected HCL code snippets. Examples will be converted, but may not display correctly in the registry, e.g. lacking tabs.", unexpectedSnippets)
+	if g.docStats.unexpectedSnippets > 0 {
+		g.warn("%d entity document sections contained unexpected HCL code snippets. Examples will be converted, but may not display correctly in the registry, e.g. lacking tabs.", g.docStats.unexpectedSnippets)
 	}
 
-	if elidedDescriptions > 0 {
-		g.warn("%d entity descriptions contained an <elided> reference and were dropped, including examples.", elidedDescriptions)
+	if g.docStats.elidedDescriptions > 0 {
+		g.warn("%d entity descriptions contained an <elided> reference and were dropped, including examples.", g.docStats.elidedDescriptions)
 	}
 
-	if elidedDescriptionsOnly > 0 {
-		g.warn("%d entity descriptions contained an <elided> reference and were dropped, but examples were preserved.", elidedDescriptionsOnly)
+	if g.docStats.elidedDescriptionsOnly > 0 {
+		g.warn("%d entity descriptions contained an <elided> reference and were dropped, but examples were preserved.", g.docStats.elidedDescriptionsOnly)
 	}
 
-	if elidedArguments > 0 {
-		g.warn("%d arguments contained an <elided> reference and had their descriptions dropped.", elidedArguments)
+	if g.docStats.elidedArguments > 0 {
+		g.warn("%d arguments contained an <elided> reference and had their descriptions dropped.", g.docStats.elidedArguments)
 	}
 
-	if elidedNestedArguments > 0 {
-		g.warn("%d nested arguments contained an <elided> reference and had their descriptions dropped.", elidedNestedArguments)
+	if g.docStats.elidedNestedArguments > 0 {
+		g.warn("%d nested arguments contained an <elided> reference and had their descriptions dropped.", g.docStats.elidedNestedArguments)
 	}
 
-	if elidedAttributes > 0 {
-		g.warn("%d attributes contained an <elided> reference and had their descriptions dropped.", elidedAttributes)
+	if g.docStats.elidedAttributes > 0 {
+		g.warn("%d attributes contained an <elided> reference and had their descriptions dropped.", g.docStats.elidedAttributes)
 	}
 
-	if hclAllLangsConversionFailures > 0 {
-		g.warn("%d HCL examples failed to convert in all languages", hclAllLangsConversionFailures)
+	if g.docStats.hclAllLangsConversionFailures > 0 {
+		g.warn("%d HCL examples failed to convert in all languages", g.docStats.hclAllLangsConversionFailures)
 	}
 
-	if hclTypeScriptPartialConversionFailures > 0 {
-		g.warn("%d HCL examples were converted in at least one language but failed to convert to TypeScript", hclTypeScriptPartialConversionFailures)
+	if g.docStats.hclTypeScriptPartialConversionFailures > 0 {
+		g.warn("%d HCL examples were converted in at least one language but failed to convert to TypeScript", g.docStats.hclTypeScriptPartialConversionFailures)
 	}
 
-	if hclPythonPartialConversionFailures > 0 {
-		g.warn("%d HCL examples were converted in at least one language but failed to convert to Python", hclPythonPartialConversionFailures)
+	if g.docStats.hclPythonPartialConversionFailures > 0 {
+		g.warn("%d HCL examples were converted in at least one language but failed to convert to Python", g.docStats.hclPythonPartialConversionFailures)
 	}
 
-	if hclGoPartialConversionFailures > 0 {
-		g.warn("%d HCL examples were converted in at least one language but failed to convert to Go", hclGoPartialConversionFailures)
+	if g.docStats.hclGoPartialConversionFailures > 0 {
+		g.warn("%d HCL examples were converted in at least one language but failed to convert to Go", g.docStats.hclGoPartialConversionFailures)
 	}
 
-	if hclCSharpPartialConversionFailures > 0 {
-		g.warn("%d HCL examples were converted in at least one language but failed to convert to C#", hclCSharpPartialConversionFailures)
+	if g.docStats.hclCSharpPartialConversionFailures > 0 {
+		g.warn("%d HCL examples were converted in at least one language but failed to convert to C#", g.docStats.hclCSharpPartialConversionFailures)
 	}
 }
 
@@ -1167,9 +1151,7 @@ func (g *Generator) convert(input afero.Fs, languageName string) (files map[stri
 		if v != nil {
 			files = map[string][]byte{}
 			diags = convert.Diagnostics{}
-			err = fmt.Errorf("panic converting HCL: %v", v)
-
-			g.coverageTracker.languageConversionPanic(languageName, fmt.Sprintf("%v", v))
+			err = &conversionPanic{value: v}
 		}
 	}()
 
@@ -1190,36 +1172,70 @@ func (g *Generator) convert(input afero.Fs, languageName string) (files map[stri
 	return
 }
 
+// conversionPanic is the error returned by convert when convert.Convert panics.
+type conversionPanic struct {
+	value interface{}
+}
+
+func (p *conversionPanic) Error() string {
+	return fmt.Sprintf("panic converting HCL: %v", p.value)
+}
+
 // convertHCLToString hides the implementation details of the upstream implementation for HCL conversion and provides
-// simplified parameters and return values
+// simplified parameters and return values. Conversions are looked up in and added to the generator's example cache.
 func (g *Generator) convertHCLToString(hcl, path, languageName string) (string, error) {
+	result, ok := g.exampleCache.get(hcl, languageName)
+	if !ok {
+		result = g.convertHCLToResult(hcl, path, languageName)
+		if err := g.exampleCache.put(hcl, languageName, result); err != nil {
+			g.debug("failed to cache the conversion of %s to %v: %v", path, languageName, err)
+		}
+	}
+	g.report.recordExample(path, hcl, languageName, result)
+
+	switch {
+	case result.Panic != "":
+		// By observation on the GCP provider, convert.Convert() will either panic (in which case the wrapped method
+		// above will return an error) or it will return a non-zero value for diags.
+		g.coverageTracker.languageConversionPanic(languageName, result.Panic)
+		return "", fmt.Errorf("failed to convert HCL for %s to %v: %s", path, languageName, result.Error)
+	case result.Error != "":
+		g.warn("failed to convert HCL for %s to %v: %v", path, languageName, result.Error)
+		g.coverageTracker.languageConversionFailure(languageName, result.hclDiagnostics())
+		return "", fmt.Errorf(result.Error)
+	}
+
+	g.coverageTracker.languageConversionSuccess(languageName)
+	return result.Code, nil
+}
+
+// convertHCLToResult converts an HCL example to the given language. It has no side effects, so its result may be
+// cached.
+func (g *Generator) convertHCLToResult(hclText, path, languageName string) exampleConversion {
 	input := afero.NewMemMapFs()
 	fileName := fmt.Sprintf("/%s.tf", strings.ReplaceAll(path, "/", "-"))
 	f, err := input.Create(fileName)
 	contract.AssertNoError(err)
-	_, err = f.Write([]byte(hcl))
+	_, err = f.Write([]byte(hclText))
 	contract.AssertNoError(err)
 	contract.IgnoreClose(f)
 
 	files, diags, err := g.convert(input, languageName)
-
-	// By observation on the GCP provider, convert.Convert() will either panic (in which case the wrapped method above
-	// will return an error) or it will return a non-zero value for diags.
 	if err != nil {
-		// Because this condition is presumably the result of a panic that we wrap as an error, we do not need to add
-		// anything to g.coverageTracker - that's covered in the panic recovery above.
-		return "", fmt.Errorf("failed to convert HCL for %s to %v: %w", path, languageName, err)
+		if p, ok := err.(*conversionPanic); ok {
+			return exampleConversion{Error: err.Error(), Panic: fmt.Sprintf("%v", p.value)}
+		}
+		return exampleConversion{Error: err.Error()}
 	}
 	if diags.All.HasErrors() {
 		// Remove the temp filename from the error, since it will be confusing to users of the bridge who do not know
 		// we write an example to a temp file internally in order to pass to convert.Convert().
 		//
 		// fileName starts with a "/" which is not present in the resulting error, so we need to skip the first rune.
-		errMsg := strings.Replace(diags.All.Error(), fileName[1:], "", -1)
-
-		g.warn("failed to convert HCL for %s to %v: %v", path, languageName, errMsg)
-		g.coverageTracker.languageConversionFailure(languageName, diags.All)
-		return "", fmt.Errorf(errMsg)
+		return exampleConversion{
+			Error:       strings.Replace(diags.All.Error(), fileName[1:], "", -1),
+			Diagnostics: newExampleDiagnostics(diags.All),
+		}
 	}
 
 	contract.Assert(len(files) == 1)
@@ -1228,9 +1244,7 @@ func (g *Generator) convertHCLToString(hcl, path, languageName string) (string,
 	for _, output := range files {
 		convertedHcl = strings.TrimSpace(string(output))
 	}
-
-	g.coverageTracker.languageConversionSuccess(languageName)
-	return convertedHcl, nil
+	return exampleConversion{Code: convertedHcl}
 }
 
 // So we can sort the keys of a map of examples in a deterministic order:
@@ -1344,7 +1358,7 @@ func (g *Generator) convertHCL(hcl, path, exampleTitle string, languages []strin
 	result.WriteString(hclConversionsToString(hclConversions))
 
 	if len(failedLangs) == len(languages) {
-		hclAllLangsConversionFailures++
+		g.docStats.inc(&g.docStats.hclAllLangsConversionFailures)
 
 		if exampleTitle == "" {
 			g.warn(fmt.Sprintf("unable to convert HCL example for Pulumi entity '%s'. The example will be dropped from any generated docs or SDKs.", path))
@@ -1364,13 +1378,13 @@ func (g *Generator) convertHCL(hcl, path, exampleTitle string, languages []strin
 
 			switch lang {
 			case convert.LanguageTypescript:
-				hclTypeScriptPartialConversionFailures++
+				g.docStats.inc(&g.docStats.hclTypeScriptPartialConversionFailures)
 			case convert.LanguagePython:
-				hclPythonPartialConversionFailures++
+				g.docStats.inc(&g.docStats.hclPythonPartialConversionFailures)
 			case convert.LanguageCSharp:
-				hclCSharpPartialConversionFailures++
+				g.docStats.inc(&g.docStats.hclCSharpPartialConversionFailures)
 			case convert.LanguageGo:
-				hclGoPartialConversionFailures++
+				g.docStats.inc(&g.docStats.hclGoPartialConversionFailures)
 			}
 		}
 
@@ -1415,15 +1429,17 @@ func genLanguageToSlice(input Language) []string {
 
 func cleanupDoc(name string, g *Generator, doc entityDocs, footerLinks map[string]string) (entityDocs, bool) {
 	elidedDoc := false
+	var elidedParts []string
 	newargs := make(map[string]*argumentDocs, len(doc.Arguments))
 
 	for k, v := range doc.Arguments {
 		g.debug("Cleaning up text for argument [%v] in [%v]", k, name)
 		cleanedText, elided := reformatText(g, v.description, footerLinks)
 		if elided {
-			elidedArguments++
+			g.docStats.inc(&g.docStats.elidedArguments)
 			g.warn("Found <elided> in docs for argument [%v] in [%v]. The argument's description will be dropped in the Pulumi provider.", k, name)
 			elidedDoc = true
+			elidedParts = append(elidedParts, "argument "+k)
 		}
 
 		newargs[k] = &argumentDocs{
@@ -1437,9 +1453,10 @@ func cleanupDoc(name string, g *Generator, doc entityDocs, footerLinks map[strin
 			g.debug("Cleaning up text for nested argument [%v] in [%v]", kk, name)
 			cleanedText, elided := reformatText(g, vv, footerLinks)
 			if elided {
-				elidedNestedArguments++
+				g.docStats.inc(&g.docStats.elidedNestedArguments)
 				g.warn("Found <elided> in docs for nested argument [%v] in [%v]. The argument's description will be dropped in the Pulumi provider.", kk, name)
 				elidedDoc = true
+				elidedParts = append(elidedParts, "nested argument "+k+"."+kk)
 			}
 			newargs[k].arguments[kk] = cleanedText
 		}
@@ -1450,9 +1467,10 @@ func cleanupDoc(name string, g *Generator, doc entityDocs, footerLinks map[strin
 		g.debug("Cleaning up text for attribute [%v] in [%v]", k, name)
 		cleanedText, elided := reformatText(g, v, footerLinks)
 		if elided {
-			elidedAttributes++
+			g.docStats.inc(&g.docStats.elidedAttributes)
 			g.warn("Found <elided> in docs for attribute [%v] in [%v]. The attribute's description will be dropped in the Pulumi provider.", k, name)
 			elidedDoc = true
+			elidedParts = append(elidedParts, "attribute "+k)
 		}
 		newattrs[k] = cleanedText
 	}
@@ -1468,20 +1486,23 @@ func cleanupDoc(name string, g *Generator, doc entityDocs, footerLinks map[strin
 		if examples == "" {
 			g.debug("Unable to find any examples in the description text. The entire description will be discarded.")
 
-			elidedDescriptions++
+			g.docStats.inc(&g.docStats.elidedDescriptions)
 			g.warn("Found <elided> in description for [%v]. The description and any examples will be dropped in the Pulumi provider.", name)
 			elidedDoc = true
+			elidedParts = append(elidedParts, "description")
 		} else {
 			g.debug("Found examples in the description text. Attempting to reformat the examples.")
 
 			cleanedupExamples, examplesElided := reformatText(g, examples, footerLinks)
 			if examplesElided {
-				elidedDescriptions++
+				g.docStats.inc(&g.docStats.elidedDescriptions)
 				g.warn("Found <elided> in description for [%v]. The description and any examples will be dropped in the Pulumi provider.", name)
 				elidedDoc = true
+				elidedParts = append(elidedParts, "description")
 			} else {
-				elidedDescriptionsOnly++
+				g.docStats.inc(&g.docStats.elidedDescriptionsOnly)
 				g.warn("Found <elided> in description for [%v], but was able to preserve the examples. The description proper will be dropped in the Pulumi provider.", name)
+				elidedParts = append(elidedParts, "description (examples preserved)")
 				cleanupText = cleanedupExamples
 			}
 		}
@@ -1492,6 +1513,7 @@ func cleanupDoc(name string, g *Generator, doc entityDocs, footerLinks map[strin
 		Arguments:   newargs,
 		Attributes:  newattrs,
 		Import:      doc.Import,
+		Elided:      elidedParts,
 	}, elidedDoc
 }
 
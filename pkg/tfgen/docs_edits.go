@@ -0,0 +1,208 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// docsEditTracker records which of the provider-wide docs edits changed at least one file, so that stale edits can be
+// reported once all docs have been processed.
+type docsEditTracker struct {
+	m       sync.Mutex
+	changed map[int]bool
+}
+
+func (t *docsEditTracker) markChanged(i int) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.changed == nil {
+		t.changed = make(map[int]bool)
+	}
+	t.changed[i] = true
+}
+
+func (t *docsEditTracker) wasChanged(i int) bool {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	return t.changed[i]
+}
+
+// editMarkdown applies the provider's docs edits and then the entity's docs edits to the markdown read from the file
+// with the given name. An entity edit that does not change the markdown is reported immediately; provider edits are
+// reported by warnUnusedDocsEdits.
+func (g *Generator) editMarkdown(kind DocKind, rawname, fileName string, info tfbridge.ResourceOrDataSourceInfo,
+	markdown []byte) ([]byte, error) {
+
+	for i, edit := range g.info.DocsEdits {
+		edited, changed, err := applyDocsEdit(edit, fileName, markdown)
+		if err != nil {
+			return nil, errors.Wrapf(err, "applying %v to %v", describeDocsEdit(i, edit), fileName)
+		}
+		if changed {
+			g.docsEdits.markChanged(i)
+		}
+		markdown = edited
+	}
+
+	var docinfo *tfbridge.DocInfo
+	if info != nil {
+		docinfo = info.GetDocs()
+	}
+	if docinfo == nil {
+		return markdown, nil
+	}
+	for i, edit := range docinfo.Edits {
+		edited, changed, err := applyDocsEdit(edit, fileName, markdown)
+		if err != nil {
+			return nil, errors.Wrapf(err, "applying %v for %v %v", describeDocsEdit(i, edit), kind,
+				formatEntityName(rawname))
+		}
+		if !changed {
+			g.warn("%v for %v %v did not change its docs and may be stale", describeDocsEdit(i, edit), kind,
+				formatEntityName(rawname))
+		}
+		markdown = edited
+	}
+	return markdown, nil
+}
+
+// warnUnusedDocsEdits warns about each of the provider's docs edits that did not change any file.
+func (g *Generator) warnUnusedDocsEdits() {
+	if g.skipDocs {
+		return
+	}
+	for i, edit := range g.info.DocsEdits {
+		if !g.docsEdits.wasChanged(i) {
+			g.warn("%v did not change the docs of any resource or data source and may be stale",
+				describeDocsEdit(i, edit))
+		}
+	}
+}
+
+// describeDocsEdit returns a short description of the i'th edit in a list of docs edits for use in messages.
+func describeDocsEdit(i int, edit tfbridge.DocsEdit) string {
+	var what string
+	switch {
+	case edit.Literal != "":
+		what = fmt.Sprintf("replacement of %q", edit.Literal)
+	case edit.Regexp != nil:
+		what = fmt.Sprintf("replacement of /%v/", edit.Regexp)
+	case edit.RemoveSection != "":
+		what = fmt.Sprintf("removal of section %q", edit.RemoveSection)
+	case edit.Patch != nil:
+		what = "patch"
+	default:
+		what = "empty edit"
+	}
+	if edit.Path != "" {
+		what += fmt.Sprintf(" in %q", edit.Path)
+	}
+	return fmt.Sprintf("docs edit #%d (%s)", i, what)
+}
+
+// applyDocsEdit applies a single docs edit to the markdown read from the file with the given name. It returns the
+// edited markdown and whether the edit changed it. Edits whose Path does not match the file name leave the markdown
+// unchanged.
+func applyDocsEdit(edit tfbridge.DocsEdit, fileName string, markdown []byte) ([]byte, bool, error) {
+	actions := 0
+	for _, set := range []bool{edit.Literal != "", edit.Regexp != nil, edit.RemoveSection != "", edit.Patch != nil} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return nil, false, errors.New("a docs edit must set exactly one of Literal, Regexp, RemoveSection or Patch")
+	}
+
+	if edit.Path != "" {
+		match, err := path.Match(edit.Path, fileName)
+		if err != nil {
+			return nil, false, err
+		}
+		if !match {
+			return markdown, false, nil
+		}
+	}
+
+	var edited []byte
+	switch {
+	case edit.Literal != "":
+		edited = bytes.ReplaceAll(markdown, []byte(edit.Literal), []byte(edit.Replacement))
+	case edit.Regexp != nil:
+		edited = edit.Regexp.ReplaceAll(markdown, []byte(edit.Replacement))
+	case edit.RemoveSection != "":
+		edited = removeMarkdownSection(markdown, edit.RemoveSection)
+	default:
+		patched, err := edit.Patch(fileName, markdown)
+		if err != nil {
+			return nil, false, err
+		}
+		edited = patched
+	}
+	return edited, !bytes.Equal(edited, markdown), nil
+}
+
+// removeMarkdownSection removes every section with the given title from the markdown. A section extends from its
+// heading to the next heading of the same or a higher level. Headings are matched case-insensitively, and lines in
+// fenced code blocks are never treated as headings.
+func removeMarkdownSection(markdown []byte, title string) []byte {
+	lines := strings.Split(string(markdown), "\n")
+	kept := make([]string, 0, len(lines))
+
+	inCode, removing, level := false, false, 0
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+		} else if !inCode {
+			if l, text, ok := parseMarkdownHeading(line); ok {
+				if removing && l <= level {
+					removing = false
+				}
+				if !removing && strings.EqualFold(text, strings.TrimSpace(title)) {
+					removing, level = true, l
+				}
+			}
+		}
+		if !removing {
+			kept = append(kept, line)
+		}
+	}
+	return []byte(strings.Join(kept, "\n"))
+}
+
+// parseMarkdownHeading returns the level and text of an ATX heading such as "## Import".
+func parseMarkdownHeading(line string) (int, string, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level == len(line) || (line[level] != ' ' && line[level] != '\t') {
+		return 0, "", false
+	}
+	text := strings.TrimSpace(line[level:])
+	text = strings.TrimSpace(strings.TrimRight(text, "#"))
+	return level, text, true
+}
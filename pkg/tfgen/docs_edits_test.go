@@ -0,0 +1,156 @@
+package tfgen
+
+import (
+	"bytes"
+	"io/ioutil"
+	"regexp"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+func TestApplyDocsEdit(t *testing.T) {
+	const markdown = "# test_widget\n" +
+		"\n" +
+		"Run `terraform plan` to see the widget.\n" +
+		"\n" +
+		"## Timeouts\n" +
+		"\n" +
+		"```\n" +
+		"# Not a heading\n" +
+		"```\n" +
+		"\n" +
+		"### Create\n" +
+		"\n" +
+		"10 minutes.\n" +
+		"\n" +
+		"## Import\n" +
+		"\n" +
+		"See the [registry](https://registry.terraform.io/providers/test/test).\n"
+
+	tests := []struct {
+		name     string
+		edit     tfbridge.DocsEdit
+		expected string
+		err      string
+	}{
+		{
+			name:     "literal",
+			edit:     tfbridge.DocsEdit{Literal: "terraform plan", Replacement: "pulumi preview"},
+			expected: "Run `pulumi preview` to see the widget.",
+		},
+		{
+			name: "regexp",
+			edit: tfbridge.DocsEdit{
+				Regexp:      regexp.MustCompile(`\[(\w+)\]\(https://registry\.terraform\.io[^)]*\)`),
+				Replacement: "$1",
+			},
+			expected: "See the registry.",
+		},
+		{
+			name: "remove section",
+			edit: tfbridge.DocsEdit{RemoveSection: "timeouts"},
+			expected: "Run `terraform plan` to see the widget.\n" +
+				"\n" +
+				"## Import\n",
+		},
+		{
+			name: "patch",
+			edit: tfbridge.DocsEdit{Patch: func(fileName string, markdown []byte) ([]byte, error) {
+				return append(markdown, []byte("Patched "+fileName+".\n")...), nil
+			}},
+			expected: "Patched widget.html.markdown.",
+		},
+		{
+			name:     "path matches",
+			edit:     tfbridge.DocsEdit{Path: "widget.*", Literal: "widget", Replacement: "gadget"},
+			expected: "# test_gadget",
+		},
+		{
+			name: "no action",
+			edit: tfbridge.DocsEdit{Path: "widget.*"},
+			err:  "a docs edit must set exactly one of Literal, Regexp, RemoveSection or Patch",
+		},
+		{
+			name: "several actions",
+			edit: tfbridge.DocsEdit{Literal: "widget", RemoveSection: "Import"},
+			err:  "a docs edit must set exactly one of Literal, Regexp, RemoveSection or Patch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edited, changed, err := applyDocsEdit(tt.edit, "widget.html.markdown", []byte(markdown))
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, changed)
+			assert.Contains(t, string(edited), tt.expected)
+		})
+	}
+
+	// Edits that do not apply to a file, or find nothing to edit in it, leave it unchanged.
+	for _, edit := range []tfbridge.DocsEdit{
+		{Path: "gadget.*", Literal: "widget", Replacement: "gadget"},
+		{Literal: "terraform apply", Replacement: "pulumi up"},
+		{RemoveSection: "Create Timeout"},
+	} {
+		edited, changed, err := applyDocsEdit(edit, "widget.html.markdown", []byte(markdown))
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, markdown, string(edited))
+	}
+}
+
+func TestDocsEditsWarnWhenStale(t *testing.T) {
+	repo := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(repo, "docs/resources/widget.html.markdown",
+		[]byte("# test_widget\n\nProvides a widget. Run `terraform plan` to see it.\n"), 0600))
+
+	info := tfbridge.ProviderInfo{
+		Name: "test",
+		DocsEdits: []tfbridge.DocsEdit{
+			{Literal: "terraform plan", Replacement: "pulumi preview"},
+			{Literal: "terraform apply", Replacement: "pulumi up"},
+		},
+	}
+	resourceInfo := &tfbridge.ResourceInfo{
+		Tok: "test:index:Widget",
+		Docs: &tfbridge.DocInfo{
+			Edits: []tfbridge.DocsEdit{
+				{Literal: "Provides", Replacement: "Manages"},
+				{RemoveSection: "Timeouts"},
+			},
+		},
+	}
+
+	var stderr bytes.Buffer
+	g, err := NewGenerator(GeneratorOptions{
+		Package:      "test",
+		Version:      "0.0.1",
+		Language:     Schema,
+		ProviderInfo: info,
+		Root:         afero.NewMemMapFs(),
+		Sink:         diag.DefaultSink(ioutil.Discard, &stderr, diag.FormatOptions{Color: "never"}),
+		DocsSource:   memDocsSource{repo: repo},
+	})
+	require.NoError(t, err)
+
+	docs, err := getDocsForProvider(g, "", "test", "test", ResourceDocs, "test_widget", resourceInfo, "", "")
+	require.NoError(t, err)
+	assert.Contains(t, docs.Description, "Manages a widget. Run `pulumi preview` to see it.")
+	assert.Contains(t, stderr.String(),
+		`docs edit #1 (removal of section "Timeouts") for resource 'test_widget' did not change its docs`)
+
+	stderr.Reset()
+	g.warnUnusedDocsEdits()
+	assert.NotContains(t, stderr.String(), "docs edit #0")
+	assert.Contains(t, stderr.String(),
+		`docs edit #1 (replacement of "terraform apply") did not change the docs of any resource or data source`)
+}
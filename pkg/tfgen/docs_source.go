@@ -0,0 +1,377 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/spf13/afero"
+	gomodule "golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// DocsSource locates the upstream documentation of the Terraform provider that is being bridged.
+type DocsSource interface {
+	// Open returns a read-only filesystem rooted at the provider's repository, i.e. the directory that contains
+	// either `docs/` or `website/docs/`.
+	Open() (afero.Fs, error)
+}
+
+// NewDirDocsSource returns a DocsSource that reads the provider's documentation from a checkout of its repository in
+// the given local directory.
+func NewDirDocsSource(dir string) DocsSource {
+	return &dirDocsSource{dir: dir}
+}
+
+type dirDocsSource struct {
+	dir string
+}
+
+func (s *dirDocsSource) Open() (afero.Fs, error) {
+	info, err := os.Stat(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading docs directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("docs source %q is not a directory", s.dir)
+	}
+	return afero.NewReadOnlyFs(afero.NewBasePathFs(afero.NewOsFs(), s.dir)), nil
+}
+
+// NewArchiveDocsSource returns a DocsSource that reads the provider's documentation from an archive of its repository,
+// e.g. a release tarball downloaded ahead of time. Zip files and (optionally gzipped) tarballs are supported; the
+// format is chosen by the file's extension. If the repository is nested in a single top-level directory of the
+// archive, as is the case for archives of GitHub releases, that directory is used as the root.
+func NewArchiveDocsSource(path string) DocsSource {
+	return &archiveDocsSource{path: path}
+}
+
+type archiveDocsSource struct {
+	path string
+}
+
+func (s *archiveDocsSource) Open() (afero.Fs, error) {
+	fs := afero.NewMemMapFs()
+
+	var err error
+	switch name := strings.ToLower(s.path); {
+	case strings.HasSuffix(name, ".zip"):
+		err = s.readZip(fs)
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		err = s.readTar(fs, true)
+	case strings.HasSuffix(name, ".tar"):
+		err = s.readTar(fs, false)
+	default:
+		return nil, fmt.Errorf("unsupported docs archive %q: expected a .zip, .tar, .tar.gz or .tgz file", s.path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading docs archive %q: %w", s.path, err)
+	}
+
+	return afero.NewReadOnlyFs(archiveRoot(fs)), nil
+}
+
+func (s *archiveDocsSource) readZip(fs afero.Fs) error {
+	r, err := zip.OpenReader(s.path)
+	if err != nil {
+		return err
+	}
+	defer contract.IgnoreClose(r)
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || !isArchivedMarkdown(f.Name) {
+			continue
+		}
+		contents, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = writeArchivedFile(fs, f.Name, contents)
+		contract.IgnoreClose(contents)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *archiveDocsSource) readTar(fs afero.Fs, gzipped bool) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer contract.IgnoreClose(f)
+
+	var r io.Reader = f
+	if gzipped {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer contract.IgnoreClose(gz)
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg || !isArchivedMarkdown(hdr.Name) {
+			continue
+		}
+		if err = writeArchivedFile(fs, hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// isArchivedMarkdown returns true if the archive entry with the given name may be a documentation page. Only these
+// entries are extracted, which keeps the in-memory copy of the archive small.
+func isArchivedMarkdown(name string) bool {
+	return strings.HasSuffix(name, ".md") || strings.HasSuffix(name, ".markdown")
+}
+
+func writeArchivedFile(fs afero.Fs, name string, contents io.Reader) error {
+	// Archive entries always use forward slashes; reject entries that would escape the archive's root.
+	name = path.Clean("/" + name)
+	if err := fs.MkdirAll(filepath.FromSlash(path.Dir(name)), 0700); err != nil {
+		return err
+	}
+	bytes, err := ioutil.ReadAll(contents)
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(fs, filepath.FromSlash(name), bytes, 0600)
+}
+
+// archiveRoot returns the root of the repository in an extracted archive: the archive's root if it contains the docs,
+// or the single top-level directory of the archive otherwise.
+func archiveRoot(fs afero.Fs) afero.Fs {
+	root := string(filepath.Separator)
+	for _, docs := range []string{"docs", "website"} {
+		if ok, _ := afero.DirExists(fs, root+docs); ok {
+			return afero.NewBasePathFs(fs, root)
+		}
+	}
+	if entries, err := afero.ReadDir(fs, root); err == nil && len(entries) == 1 && entries[0].IsDir() {
+		root += entries[0].Name()
+	}
+	return afero.NewBasePathFs(fs, root)
+}
+
+// NewModuleCacheDocsSource returns a DocsSource that reads the provider's documentation from the Go module cache
+// ($GOMODCACHE, or $GOPATH/pkg/mod) without accessing the network. modulePath is the path of the provider's Go
+// module, e.g. `github.com/hashicorp/terraform-provider-aws`. If version is empty, the highest version of the module
+// in the cache is used.
+func NewModuleCacheDocsSource(modulePath, version string) DocsSource {
+	return &moduleCacheDocsSource{modulePath: modulePath, version: version}
+}
+
+type moduleCacheDocsSource struct {
+	modulePath string
+	version    string
+}
+
+func (s *moduleCacheDocsSource) Open() (afero.Fs, error) {
+	cache, err := goModCache()
+	if err != nil {
+		return nil, err
+	}
+
+	escapedPath, err := gomodule.EscapePath(s.modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	version := s.version
+	if version == "" {
+		if version, err = latestCachedVersion(cache, escapedPath); err != nil {
+			return nil, err
+		}
+	}
+	escapedVersion, err := gomodule.EscapeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(cache, filepath.FromSlash(escapedPath)+"@"+escapedVersion)
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("module %s@%s is not in the module cache: %w", s.modulePath, version, err)
+	}
+	return NewDirDocsSource(dir).Open()
+}
+
+func goModCache() (string, error) {
+	if cache := os.Getenv("GOMODCACHE"); cache != "" {
+		return cache, nil
+	}
+	gopath := filepath.SplitList(os.Getenv("GOPATH"))
+	if len(gopath) != 0 && gopath[0] != "" {
+		return filepath.Join(gopath[0], "pkg", "mod"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error locating the module cache: %w", err)
+	}
+	return filepath.Join(home, "go", "pkg", "mod"), nil
+}
+
+// latestCachedVersion returns the highest version of the module with the given escaped path in the module cache.
+func latestCachedVersion(cache, escapedPath string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(cache, filepath.FromSlash(escapedPath)+"@*"))
+	if err != nil {
+		return "", err
+	}
+
+	var versions []string
+	for _, m := range matches {
+		escaped := m[strings.LastIndex(m, "@")+1:]
+		if v, err := gomodule.UnescapeVersion(escaped); err == nil && semver.IsValid(v) {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no version of module %s is in the module cache", escapedPath)
+	}
+	sort.Slice(versions, func(i, j int) bool { return semver.Compare(versions[i], versions[j]) < 0 })
+	return versions[len(versions)-1], nil
+}
+
+// NewGoModDocsSource returns a DocsSource that locates the provider's documentation by running
+// `go mod download -json <modulePath>` in dir, which resolves the version of the module that dir's go.mod requires.
+// This may access the network. If dir is empty, the `provider` directory beneath the current working directory is
+// used, unless the working directory is itself named `provider`.
+func NewGoModDocsSource(modulePath, dir string) DocsSource {
+	return &goModDocsSource{modulePath: modulePath, dir: dir}
+}
+
+// defaultDocsSource returns the DocsSource used when the generator options do not specify one: the provider's module
+// is located with `go mod download`.
+func defaultDocsSource(info tfbridge.ProviderInfo) DocsSource {
+	return NewGoModDocsSource(providerModulePath(info), "")
+}
+
+// providerModulePath returns the path of the Terraform provider's Go module as named by its ProviderInfo, e.g.
+// `github.com/hashicorp/terraform-provider-aws/v4`.
+func providerModulePath(info tfbridge.ProviderInfo) string {
+	modulePath := fmt.Sprintf("%s/%s/terraform-provider-%s", info.GetGitHubHost(), info.GetGitHubOrg(), info.Name)
+	if version := info.GetProviderModuleVersion(); version != "" {
+		modulePath = fmt.Sprintf("%s/%s", modulePath, version)
+	}
+	return modulePath
+}
+
+// parseDocsSource interprets the value of tfgen's --docs-source flag: `go-mod` for the default behavior,
+// `module-cache` or `module-cache@<version>` to read the provider's module from the module cache, or the path of a
+// directory or archive that holds the provider's repository.
+func parseDocsSource(spec string, info tfbridge.ProviderInfo) (DocsSource, error) {
+	switch {
+	case spec == "go-mod":
+		return defaultDocsSource(info), nil
+	case spec == "module-cache":
+		return NewModuleCacheDocsSource(providerModulePath(info), ""), nil
+	case strings.HasPrefix(spec, "module-cache@"):
+		return NewModuleCacheDocsSource(providerModulePath(info), strings.TrimPrefix(spec, "module-cache@")), nil
+	}
+
+	stat, err := os.Stat(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docs source %q: %w", spec, err)
+	}
+	if stat.IsDir() {
+		return NewDirDocsSource(spec), nil
+	}
+	return NewArchiveDocsSource(spec), nil
+}
+
+type goModDocsSource struct {
+	modulePath string
+	dir        string
+}
+
+// repoPaths caches the directories that `go mod download` resolved, keyed by module path and working directory.
+var repoPaths sync.Map
+
+func (s *goModDocsSource) Open() (afero.Fs, error) {
+	dir := s.dir
+	if dir == "" {
+		curWd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("error finding current working directory: %w", err)
+		}
+		if filepath.Base(curWd) != "provider" {
+			curWd = filepath.Join(curWd, "provider")
+		}
+		dir = curWd
+	}
+
+	key := s.modulePath + "\x00" + dir
+	repoPath, ok := repoPaths.Load(key)
+	if !ok {
+		downloaded, err := goModDownload(s.modulePath, dir)
+		if err != nil {
+			return nil, err
+		}
+		repoPaths.Store(key, downloaded)
+		repoPath = downloaded
+	}
+	return NewDirDocsSource(repoPath.(string)).Open()
+}
+
+func goModDownload(modulePath, dir string) (string, error) {
+	command := exec.Command("go", "mod", "download", "-json", modulePath)
+	command.Dir = dir
+	output, err := command.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error running 'go mod download -json' for module %s in %s: %w", modulePath, dir, err)
+	}
+
+	target := struct {
+		Version string
+		Dir     string
+		Error   string
+	}{}
+
+	if err := json.Unmarshal(output, &target); err != nil {
+		return "", fmt.Errorf("error parsing output of 'go mod download -json' for module %s: %w", modulePath, err)
+	}
+
+	if target.Error != "" {
+		return "", fmt.Errorf("error from 'go mod download -json' for module %s: %s", modulePath, target.Error)
+	}
+
+	return target.Dir, nil
+}
@@ -0,0 +1,171 @@
+package tfgen
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+const testWidgetMarkdown = "# test_widget\n\nProvides a widget.\n"
+
+func writeTestDocs(t *testing.T, dir string) {
+	docs := filepath.Join(dir, "docs", "resources")
+	require.NoError(t, os.MkdirAll(docs, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(docs, "widget.html.markdown"), []byte(testWidgetMarkdown), 0600))
+}
+
+func assertReadsTestDocs(t *testing.T, src DocsSource) {
+	repo, err := src.Open()
+	require.NoError(t, err)
+
+	markdown, name, found := readMarkdown(repo, ResourceDocs, []string{"widget.md", "widget.html.markdown"})
+	assert.True(t, found)
+	assert.Equal(t, "widget.html.markdown", name)
+	assert.Equal(t, testWidgetMarkdown, string(markdown))
+}
+
+func TestDirDocsSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestDocs(t, dir)
+	assertReadsTestDocs(t, NewDirDocsSource(dir))
+
+	_, err = NewDirDocsSource(filepath.Join(dir, "missing")).Open()
+	assert.Error(t, err)
+}
+
+func TestArchiveDocsSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "docs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	const entry = "terraform-provider-test-1.0.0/docs/resources/widget.html.markdown"
+
+	t.Run("zip", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		f, err := w.Create(entry)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(testWidgetMarkdown))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		path := filepath.Join(dir, "docs.zip")
+		require.NoError(t, ioutil.WriteFile(path, buf.Bytes(), 0600))
+		assertReadsTestDocs(t, NewArchiveDocsSource(path))
+	})
+
+	t.Run("tar.gz", func(t *testing.T) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		w := tar.NewWriter(gz)
+		require.NoError(t, w.WriteHeader(&tar.Header{
+			Name:     entry,
+			Typeflag: tar.TypeReg,
+			Mode:     0600,
+			Size:     int64(len(testWidgetMarkdown)),
+		}))
+		_, err := w.Write([]byte(testWidgetMarkdown))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		require.NoError(t, gz.Close())
+
+		path := filepath.Join(dir, "docs.tar.gz")
+		require.NoError(t, ioutil.WriteFile(path, buf.Bytes(), 0600))
+		assertReadsTestDocs(t, NewArchiveDocsSource(path))
+	})
+
+	_, err = NewArchiveDocsSource(filepath.Join(dir, "docs.rar")).Open()
+	assert.Error(t, err)
+}
+
+func TestModuleCacheDocsSource(t *testing.T) {
+	cache, err := ioutil.TempDir("", "modcache")
+	require.NoError(t, err)
+	defer os.RemoveAll(cache)
+
+	oldCache, hadCache := os.LookupEnv("GOMODCACHE")
+	require.NoError(t, os.Setenv("GOMODCACHE", cache))
+	defer func() {
+		if hadCache {
+			os.Setenv("GOMODCACHE", oldCache)
+		} else {
+			os.Unsetenv("GOMODCACHE")
+		}
+	}()
+
+	// Upper-case letters in module paths are escaped in the module cache.
+	modDir := filepath.Join(cache, "github.com", "!test-!org", "terraform-provider-test")
+	writeTestDocs(t, modDir+"@v1.10.0")
+	require.NoError(t, os.MkdirAll(modDir+"@v1.9.0", 0700))
+
+	src := NewModuleCacheDocsSource("github.com/Test-Org/terraform-provider-test", "v1.10.0")
+	assertReadsTestDocs(t, src)
+
+	// Without a version, the highest cached version is used.
+	assertReadsTestDocs(t, NewModuleCacheDocsSource("github.com/Test-Org/terraform-provider-test", ""))
+
+	_, err = NewModuleCacheDocsSource("github.com/Test-Org/terraform-provider-test", "v2.0.0").Open()
+	assert.Error(t, err)
+	_, err = NewModuleCacheDocsSource("github.com/Test-Org/terraform-provider-other", "").Open()
+	assert.Error(t, err)
+}
+
+type memDocsSource struct {
+	repo afero.Fs
+}
+
+func (s memDocsSource) Open() (afero.Fs, error) {
+	return s.repo, nil
+}
+
+func TestMissingDocs(t *testing.T) {
+	repo := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(repo, "docs/resources/widget.html.markdown", []byte(testWidgetMarkdown), 0600))
+
+	newGenerator := func(missingDocsError bool, stderr *bytes.Buffer) *Generator {
+		g, err := NewGenerator(GeneratorOptions{
+			Package:          "test",
+			Version:          "0.0.1",
+			Language:         Schema,
+			ProviderInfo:     tfbridge.ProviderInfo{Name: "test"},
+			Root:             afero.NewMemMapFs(),
+			Sink:             diag.DefaultSink(ioutil.Discard, stderr, diag.FormatOptions{Color: "never"}),
+			DocsSource:       memDocsSource{repo: repo},
+			MissingDocsError: missingDocsError,
+		})
+		require.NoError(t, err)
+		return g
+	}
+
+	var stderr bytes.Buffer
+	g := newGenerator(false, &stderr)
+	docs, err := getDocsForProvider(g, "", "test", "test", ResourceDocs, "test_widget", nil, "", "")
+	assert.NoError(t, err)
+	assert.Contains(t, docs.Description, "Provides a widget.")
+	assert.Empty(t, stderr.String())
+
+	_, err = getDocsForProvider(g, "", "test", "test", ResourceDocs, "test_gadget", nil, "", "")
+	assert.NoError(t, err)
+	assert.Contains(t, stderr.String(), "warning: could not find docs for resource 'test_gadget'")
+
+	stderr.Reset()
+	g = newGenerator(true, &stderr)
+	_, err = getDocsForProvider(g, "", "test", "test", ResourceDocs, "test_gadget", nil, "", "")
+	assert.Error(t, err)
+	assert.Contains(t, stderr.String(), "error: could not find docs for resource 'test_gadget'")
+}
@@ -0,0 +1,242 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// exampleCacheFormat is bumped whenever the format of cached conversion results changes.
+const exampleCacheFormat = "1"
+
+// bridgeModulePath is the path of the bridge's Go module, used to find its version in the running binary.
+const bridgeModulePath = "github.com/pulumi/pulumi-terraform-bridge/v3"
+
+// exampleConversion is the result of converting an HCL example to a single language. Results are pure data so that
+// they can be cached; the generator reports them (warnings, coverage) whether they were cached or not.
+type exampleConversion struct {
+	// Code is the converted example, if the conversion succeeded.
+	Code string `json:"code,omitempty"`
+	// Error describes why the conversion failed, if it did.
+	Error string `json:"error,omitempty"`
+	// Panic is set if the converter panicked.
+	Panic string `json:"panic,omitempty"`
+	// Diagnostics holds the error diagnostics reported by the converter, if any.
+	Diagnostics []exampleDiagnostic `json:"diagnostics,omitempty"`
+}
+
+type exampleDiagnostic struct {
+	Summary string `json:"summary"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// newExampleDiagnostics records the error diagnostics in diags.
+func newExampleDiagnostics(diags hcl.Diagnostics) []exampleDiagnostic {
+	var result []exampleDiagnostic
+	for _, d := range diags {
+		if d.Severity == hcl.DiagError {
+			result = append(result, exampleDiagnostic{Summary: d.Summary, Detail: d.Detail})
+		}
+	}
+	return result
+}
+
+func (c exampleConversion) hclDiagnostics() hcl.Diagnostics {
+	diags := make(hcl.Diagnostics, len(c.Diagnostics))
+	for i, d := range c.Diagnostics {
+		diags[i] = &hcl.Diagnostic{Severity: hcl.DiagError, Summary: d.Summary, Detail: d.Detail}
+	}
+	return diags
+}
+
+// exampleCache is a content-addressed, on-disk cache of example conversions. Entries are keyed by the HCL text and
+// target language, and are stored beneath a directory that is specific to the package, the bridge build, the
+// Terraform version and the provider's schema and mapping. The mappings of other providers whose resources an example
+// uses are not part of the key. Directories that have not been used for exampleCacheMaxAge are deleted when the cache
+// is opened. A nil *exampleCache caches nothing.
+type exampleCache struct {
+	dir string // the directory that holds the entries for the current provider.
+}
+
+// exampleCacheMaxAge is how long the entries for a version of a provider are kept after they were last used. Entries
+// are pruned by age rather than as soon as the provider changes so that concurrent tfgen runs for different versions,
+// e.g. on different branches, do not delete each other's entries.
+const exampleCacheMaxAge = 7 * 24 * time.Hour
+
+// newExampleCache opens the example cache for the given provider beneath root. Entries for other versions of the
+// provider that have not been used for exampleCacheMaxAge are removed.
+func newExampleCache(root, pkg, terraformVersion string, info tfbridge.ProviderInfo) (*exampleCache, error) {
+	marshalledInfo, err := json.Marshal(tfbridge.MarshalProviderInfo(&info))
+	if err != nil {
+		return nil, err
+	}
+	bridge, err := bridgeVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	for _, part := range [][]byte{
+		[]byte(exampleCacheFormat), []byte(bridge), []byte(terraformVersion), marshalledInfo,
+	} {
+		_, err = h.Write(append(part, 0))
+		contract.AssertNoError(err)
+	}
+	providerHash := hex.EncodeToString(h.Sum(nil))
+
+	pkgDir := filepath.Join(root, "examples", pkg)
+	dir := filepath.Join(pkgDir, providerHash)
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	// Mark the entries for this version as used before pruning so that they are not removed by concurrent runs.
+	now := time.Now()
+	if err = os.Chtimes(dir, now, now); err != nil {
+		return nil, err
+	}
+	if err = pruneExampleCache(pkgDir, now.Add(-exampleCacheMaxAge)); err != nil {
+		return nil, err
+	}
+	return &exampleCache{dir: dir}, nil
+}
+
+// pruneExampleCache removes the entries for every version of the provider that has not been used since the given
+// time. Entries that vanish while pruning, e.g. because a concurrent run pruned them first, are ignored.
+func pruneExampleCache(pkgDir string, usedSince time.Time) error {
+	entries, err := ioutil.ReadDir(pkgDir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ModTime().Before(usedSince) {
+			if err := os.RemoveAll(filepath.Join(pkgDir, e.Name())); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bridgeVersion returns a string that identifies the build of the bridge that is linked into the running binary. If
+// the bridge module was resolved from the module cache, this is its version and checksum. Otherwise, e.g. if it was
+// replaced by a local directory or is the main module, its version says nothing about its contents, so the running
+// binary is identified by the hash of its executable instead.
+func bridgeVersion() (string, error) {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, m := range append([]*debug.Module{&info.Main}, info.Deps...) {
+			if m.Path != bridgeModulePath {
+				continue
+			}
+			if m.Replace != nil {
+				m = m.Replace
+			}
+			if m.Sum != "" {
+				return m.Path + "@" + m.Version + " " + m.Sum, nil
+			}
+			break
+		}
+	}
+	return executableHash()
+}
+
+// executableHash returns the SHA-256 hash of the running executable.
+func executableHash() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer contract.IgnoreClose(f)
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "executable " + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *exampleCache) entryPath(hclText, language string) string {
+	sum := sha256.Sum256([]byte(language + "\x00" + hclText))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// get returns the cached conversion of the given HCL to the given language, if any. Unreadable entries are treated as
+// misses.
+func (c *exampleCache) get(hclText, language string) (exampleConversion, bool) {
+	if c == nil {
+		return exampleConversion{}, false
+	}
+
+	bytes, err := ioutil.ReadFile(c.entryPath(hclText, language))
+	if err != nil {
+		return exampleConversion{}, false
+	}
+	var result exampleConversion
+	if err = json.Unmarshal(bytes, &result); err != nil {
+		return exampleConversion{}, false
+	}
+	return result, true
+}
+
+// put records the conversion of the given HCL to the given language. The entry is written to a temporary file and
+// renamed into place so that concurrent tfgen runs never observe partial entries.
+func (c *exampleCache) put(hclText, language string, result exampleConversion) error {
+	if c == nil {
+		return nil
+	}
+
+	bytes, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	path := c.entryPath(hclText, language)
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(bytes)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		contract.IgnoreError(os.Remove(f.Name()))
+	}
+	return err
+}
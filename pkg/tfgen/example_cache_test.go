@@ -0,0 +1,148 @@
+package tfgen
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimschema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func testCacheProviderInfo(attr string) tfbridge.ProviderInfo {
+	str := (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim()
+	return tfbridge.ProviderInfo{
+		Name: "test",
+		P: (&shimschema.Provider{
+			Schema: shimschema.SchemaMap{},
+			ResourcesMap: shimschema.ResourceMap{
+				"test_widget": (&shimschema.Resource{Schema: shimschema.SchemaMap{attr: str}}).Shim(),
+			},
+			DataSourcesMap: shimschema.ResourceMap{},
+		}).Shim(),
+		Resources: map[string]*tfbridge.ResourceInfo{"test_widget": {Tok: "test:index:Widget"}},
+	}
+}
+
+func TestExampleCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	const hcl = `resource "test_widget" "w" {}`
+
+	cache, err := newExampleCache(dir, "test", "", testCacheProviderInfo("name"))
+	require.NoError(t, err)
+
+	_, ok := cache.get(hcl, "typescript")
+	assert.False(t, ok)
+
+	failure := exampleConversion{
+		Error:       "unsupported attribute",
+		Diagnostics: []exampleDiagnostic{{Summary: "unsupported attribute", Detail: "foo is not supported"}},
+	}
+	require.NoError(t, cache.put(hcl, "typescript", exampleConversion{Code: "new test.Widget(\"w\");"}))
+	require.NoError(t, cache.put(hcl, "python", failure))
+
+	result, ok := cache.get(hcl, "typescript")
+	assert.True(t, ok)
+	assert.Equal(t, exampleConversion{Code: "new test.Widget(\"w\");"}, result)
+
+	result, ok = cache.get(hcl, "python")
+	assert.True(t, ok)
+	assert.Equal(t, failure, result)
+
+	_, ok = cache.get(hcl, "go")
+	assert.False(t, ok)
+
+	// Reopening the cache for the same provider keeps its entries.
+	cache, err = newExampleCache(dir, "test", "", testCacheProviderInfo("name"))
+	require.NoError(t, err)
+	_, ok = cache.get(hcl, "typescript")
+	assert.True(t, ok)
+
+	// A change to the provider's schema invalidates every entry.
+	cache, err = newExampleCache(dir, "test", "", testCacheProviderInfo("label"))
+	require.NoError(t, err)
+	_, ok = cache.get(hcl, "typescript")
+	assert.False(t, ok)
+
+	// The entries for the previous schema are kept until they have not been used for exampleCacheMaxAge, so that
+	// concurrent runs for either schema do not delete each other's entries.
+	pkgDir := filepath.Join(dir, "examples", "test")
+	entries, err := ioutil.ReadDir(pkgDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	stale := time.Now().Add(-exampleCacheMaxAge - time.Hour)
+	for _, e := range entries {
+		require.NoError(t, os.Chtimes(filepath.Join(pkgDir, e.Name()), stale, stale))
+	}
+	_, err = newExampleCache(dir, "test", "", testCacheProviderInfo("label"))
+	require.NoError(t, err)
+	entries, err = ioutil.ReadDir(pkgDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, filepath.Dir(cache.dir), pkgDir)
+	assert.Equal(t, filepath.Base(cache.dir), entries[0].Name())
+
+	// A nil cache caches nothing.
+	var nilCache *exampleCache
+	assert.NoError(t, nilCache.put(hcl, "typescript", exampleConversion{Code: "code"}))
+	_, ok = nilCache.get(hcl, "typescript")
+	assert.False(t, ok)
+}
+
+func TestBridgeVersion(t *testing.T) {
+	// Test binaries are built from the bridge's own source tree, so they are identified by their executable.
+	version, err := bridgeVersion()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(version, "executable "), version)
+
+	again, err := bridgeVersion()
+	require.NoError(t, err)
+	assert.Equal(t, version, again)
+}
+
+func TestConvertHCLToStringUsesCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var stderr bytes.Buffer
+	g, err := NewGenerator(GeneratorOptions{
+		Package:      "test",
+		Version:      "0.0.1",
+		Language:     Schema,
+		ProviderInfo: testCacheProviderInfo("name"),
+		Root:         afero.NewMemMapFs(),
+		Sink:         diag.DefaultSink(ioutil.Discard, &stderr, diag.FormatOptions{Color: "never"}),
+		CacheDir:     dir,
+	})
+	require.NoError(t, err)
+
+	// Seed the cache so that the results below could not have come from the converter.
+	const hcl = `resource "test_widget" "w" {}`
+	require.NoError(t, g.exampleCache.put(hcl, "typescript", exampleConversion{Code: "cached code"}))
+	require.NoError(t, g.exampleCache.put(hcl, "python", exampleConversion{Error: "cached failure"}))
+
+	code, err := g.convertHCLToString(hcl, "#/resources/test:index:Widget", "typescript")
+	assert.NoError(t, err)
+	assert.Equal(t, "cached code", code)
+
+	// Cached failures are reported just like fresh ones.
+	_, err = g.convertHCLToString(hcl, "#/resources/test:index:Widget", "python")
+	assert.EqualError(t, err, "cached failure")
+	assert.Contains(t, stderr.String(),
+		"failed to convert HCL for #/resources/test:index:Widget to python: cached failure")
+}
@@ -21,7 +21,9 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
 
@@ -68,6 +70,19 @@ type Generator struct {
 	skipDocs         bool
 	skipExamples     bool
 	coverageTracker  *CoverageTracker
+	missingDocsError bool // whether docs that cannot be found are fatal
+
+	docsSource DocsSource // where to find the upstream docs
+	docsOnce   sync.Once
+	docsRepo   afero.Fs // the opened docsSource
+	docsErr    error    // the error that opening docsSource returned, if any
+
+	exampleCache *exampleCache   // the cache of example conversions, if any
+	parallel     int             // the maximum number of docs pages or examples to process concurrently
+	docStats     docStats        // counts of the problems encountered while processing docs
+	docsEdits    docsEditTracker // which of the provider's docs edits changed any docs
+	report       *docsReport     // the docs and examples report, if any
+	reportPath   string          // the file to which to write the report
 
 	convertedCode map[string][]byte
 }
@@ -290,6 +305,7 @@ const (
 	kindMap
 	kindSet
 	kindObject
+	kindAny
 )
 
 // Avoid an unused warning from varcheck.
@@ -307,6 +323,12 @@ type propertyType struct {
 	nestedType tokens.Type
 	altTypes   []tokens.Type
 	asset      *tfbridge.AssetTranslation
+
+	// enumValues, if non-empty, lists the values a string property is restricted to by its Terraform validator or
+	// its enum overlay.
+	enumValues []string
+	// enum is the enum overlay of a string property, if any.
+	enum *tfbridge.EnumInfo
 }
 
 func makePropertyType(objectName string, sch shim.Schema, info *tfbridge.SchemaInfo, out bool,
@@ -337,12 +359,22 @@ func makePropertyType(objectName string, sch shim.Schema, info *tfbridge.SchemaI
 		t.kind = kindFloat
 	case shim.TypeString:
 		t.kind = kindString
+		if info != nil && info.Enum != nil && len(info.Enum.Values) != 0 {
+			t.enum = info.Enum
+			for _, v := range info.Enum.Values {
+				t.enumValues = append(t.enumValues, v.Value)
+			}
+		} else if v := sch.Validation(); v != nil && !v.IgnoreCase && isSafeEnum(v.AllowedValues) {
+			t.enumValues = v.AllowedValues
+		}
 	case shim.TypeList:
 		t.kind = kindList
 	case shim.TypeMap:
 		t.kind = kindMap
 	case shim.TypeSet:
 		t.kind = kindSet
+	case shim.TypeDynamic:
+		t.kind = kindAny
 	}
 
 	// We should carry across any of the deprecation messages, to Pulumi, as per Terraform schema
@@ -364,8 +396,8 @@ func makePropertyType(objectName string, sch shim.Schema, info *tfbridge.SchemaI
 		}
 	case kindMap:
 		// If this map has a "resource" element type, just use the generated element type. This works around a bug in
-		// TF that effectively forces this behavior.
-		if t.element != nil && t.element.kind == kindObject {
+		// TF that effectively forces this behavior. A map whose element is an object schema is a map of objects.
+		if _, isResource := sch.Elem().(shim.Resource); isResource && t.element != nil && t.element.kind == kindObject {
 			t = t.element
 		}
 	}
@@ -373,6 +405,33 @@ func makePropertyType(objectName string, sch shim.Schema, info *tfbridge.SchemaI
 	return t
 }
 
+// isSafeEnum returns true if the given values can be emitted as a Pulumi enum: each value must begin with a letter and
+// the values must remain distinct once reduced to the identifiers the SDK code generators derive from them.
+func isSafeEnum(values []string) bool {
+	if len(values) == 0 {
+		return false
+	}
+
+	identifiers := make(map[string]bool)
+	for _, v := range values {
+		if v == "" || !unicode.IsLetter([]rune(v)[0]) {
+			return false
+		}
+
+		id := strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, v)
+		if identifiers[id] {
+			return false
+		}
+		identifiers[id] = true
+	}
+	return true
+}
+
 func makeObjectPropertyType(objectName string, res shim.Resource, info *tfbridge.SchemaInfo, out bool,
 	entityDocs entityDocs) *propertyType {
 
@@ -455,6 +514,17 @@ func (t *propertyType) equals(other *propertyType) bool {
 	if len(t.altTypes) != len(other.altTypes) {
 		return false
 	}
+	if !enumInfoEqual(t.enum, other.enum) {
+		return false
+	}
+	if len(t.enumValues) != len(other.enumValues) {
+		return false
+	}
+	for i, v := range t.enumValues {
+		if v != other.enumValues[i] {
+			return false
+		}
+	}
 	for i, t := range t.altTypes {
 		if t != other.altTypes[i] {
 			return false
@@ -464,6 +534,22 @@ func (t *propertyType) equals(other *propertyType) bool {
 	return true
 }
 
+// enumInfoEqual returns true if the given enum overlays describe the same enum type.
+func enumInfoEqual(a, b *tfbridge.EnumInfo) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Tok != b.Tok || a.Description != b.Description || len(a.Values) != len(b.Values) {
+		return false
+	}
+	for i, v := range a.Values {
+		if v != b.Values[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // variable is a schematized variable, property, argument, or return type.
 type variable struct {
 	name   string
@@ -475,6 +561,7 @@ type variable struct {
 
 	schema shim.Schema
 	info   *tfbridge.SchemaInfo
+	scope  *constraintScope // scope translates the paths in the variable's cross-field constraints.
 
 	typ *propertyType
 }
@@ -494,6 +581,200 @@ func (v *variable) deprecationMessage() string {
 	return ""
 }
 
+// constraintsDoc documents the cross-field and validation constraints that Terraform places on the variable's value.
+func (v *variable) constraintsDoc() string {
+	if v.schema == nil {
+		return ""
+	}
+
+	names := func(keys []string) string {
+		quoted := make([]string, len(keys))
+		for i, key := range keys {
+			quoted[i] = "`" + v.scope.pulumiPath(key) + "`"
+		}
+		return strings.Join(quoted, ", ")
+	}
+	number := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	var sentences []string
+	if keys := v.schema.ConflictsWith(); len(keys) != 0 {
+		sentences = append(sentences, fmt.Sprintf("Conflicts with %s.", names(keys)))
+	}
+	if keys := v.schema.ExactlyOneOf(); len(keys) != 0 {
+		sentences = append(sentences, fmt.Sprintf("Exactly one of %s must be set.", names(keys)))
+	}
+	if keys := v.schema.AtLeastOneOf(); len(keys) != 0 {
+		sentences = append(sentences, fmt.Sprintf("At least one of %s must be set.", names(keys)))
+	}
+	if keys := v.schema.RequiredWith(); len(keys) != 0 {
+		sentences = append(sentences, fmt.Sprintf("If set, %s must also be set.", names(keys)))
+	}
+	if validation := v.schema.Validation(); validation != nil {
+		if len(validation.AllowedValues) != 0 {
+			values := make([]string, len(validation.AllowedValues))
+			for i, value := range validation.AllowedValues {
+				values[i] = "`" + value + "`"
+			}
+			if validation.IgnoreCase {
+				sentences = append(sentences, fmt.Sprintf("Must be one of %s, in any case.",
+					strings.Join(values, ", ")))
+			} else {
+				sentences = append(sentences, fmt.Sprintf("Must be one of %s.", strings.Join(values, ", ")))
+			}
+		}
+		switch min, max := validation.Min, validation.Max; {
+		case min != nil && max != nil:
+			sentences = append(sentences, fmt.Sprintf("Must be between %s and %s.", number(*min), number(*max)))
+		case min != nil:
+			sentences = append(sentences, fmt.Sprintf("Must be at least %s.", number(*min)))
+		case max != nil:
+			sentences = append(sentences, fmt.Sprintf("Must be at most %s.", number(*max)))
+		}
+	}
+	return strings.Join(sentences, " ")
+}
+
+// forceNew returns true if a change to the variable's value forces the replacement of its resource, either due to
+// Terraform or an overlay. Config values never force replacement.
+func (v *variable) forceNew() bool {
+	if v.config {
+		return false
+	}
+	if v.info != nil && v.info.ForceNew != nil {
+		return *v.info.ForceNew
+	}
+	return v.schema != nil && v.schema.ForceNew()
+}
+
+// forceNewDoc notes that a change to the variable's value forces the replacement of its resource, unless the
+// description already says so.
+func (v *variable) forceNewDoc(description string) string {
+	if !v.forceNew() {
+		return ""
+	}
+	lower := strings.ToLower(description)
+	if strings.Contains(lower, "forces a new") || strings.Contains(lower, "force a new") {
+		return ""
+	}
+	return "Changing this forces a new resource to be created."
+}
+
+// defaultsDoc documents the value that the variable takes if it is not set, whether that is a default read from the
+// environment or from the provider's configuration, a Terraform default or a value computed by the provider. Defaults
+// are documented in the order in which the bridge applies them.
+func (v *variable) defaultsDoc() string {
+	var defaults *tfbridge.DefaultInfo
+	if v.info != nil {
+		defaults = v.info.Default
+	}
+	if defaults != nil {
+		switch {
+		case len(defaults.EnvVars) != 0:
+			names := make([]string, len(defaults.EnvVars))
+			for i, name := range defaults.EnvVars {
+				names[i] = "`" + name + "`"
+			}
+			doc := "If not set, defaults to the value of the " + names[0] + " environment variable"
+			if len(names) > 1 {
+				doc = "If not set, defaults to the value of the first of the " + strings.Join(names, ", ") +
+					" environment variables that is set"
+			}
+			if defaults.Value != nil {
+				doc += fmt.Sprintf(", or `%v` if none is set", defaults.Value)
+			}
+			return doc + "."
+		case defaults.Config != "" && !v.config:
+			return fmt.Sprintf("If not set, defaults to the value of the `%s` provider configuration setting.",
+				defaults.Config)
+		case defaults.Value != nil:
+			// The default is recorded in the schema itself.
+			return ""
+		}
+	}
+
+	if v.schema == nil {
+		return ""
+	}
+	if value := v.schema.Default(); value != nil {
+		if value == "" {
+			return "Defaults to an empty string."
+		}
+		return fmt.Sprintf("Defaults to `%v`.", value)
+	}
+	if !v.out && !v.config && defaults == nil && v.schema.Optional() && v.schema.Computed() {
+		return "If not set, the provider computes a value."
+	}
+	return ""
+}
+
+// constraintScope translates the Terraform attribute paths used in the cross-field constraints of an entity, such as
+// "network_interface.0.subnet_id", into the corresponding Pulumi property paths. Paths are relative to the entity's
+// top-level schema, so the scope holds that schema and its overlays in order to honor name overrides.
+type constraintScope struct {
+	schema shim.SchemaMap
+	info   map[string]*tfbridge.SchemaInfo
+}
+
+// pulumiPath returns the Pulumi property path of the given Terraform attribute path. The scope may be nil, in which
+// case only the default name mangling is applied.
+func (s *constraintScope) pulumiPath(key string) string {
+	var schemaMap shim.SchemaMap
+	var infos map[string]*tfbridge.SchemaInfo
+	if s != nil {
+		schemaMap, infos = s.schema, s.info
+	}
+
+	var parts []string
+	var parent shim.Schema
+	var parentInfo *tfbridge.SchemaInfo
+	for _, part := range strings.Split(key, ".") {
+		if _, err := strconv.Atoi(part); err == nil {
+			// Elements of lists that are flattened into single values have no index in Pulumi.
+			if parent == nil || !tfbridge.IsMaxItemsOne(parent, parentInfo) {
+				parts = append(parts, part)
+			}
+			continue
+		}
+
+		var sch shim.Schema
+		var info *tfbridge.SchemaInfo
+		if schemaMap != nil {
+			sch, _ = schemaMap.GetOk(part)
+		}
+		if infos != nil {
+			info = infos[part]
+		}
+		parts = append(parts, propertyName(part, sch, info))
+
+		schemaMap, infos = nil, nil
+		if sch != nil {
+			if res, ok := sch.Elem().(shim.Resource); ok {
+				schemaMap = res.Schema()
+			}
+		}
+		if info != nil && info.Elem != nil {
+			infos = info.Elem.Fields
+		}
+		parent, parentInfo = sch, info
+	}
+	return strings.Join(parts, ".")
+}
+
+// setConstraintScope sets the scope of the given variables and of the properties of their types, recursively.
+func setConstraintScope(scope *constraintScope, vars []*variable) {
+	for _, v := range vars {
+		if v == nil {
+			continue
+		}
+		v.scope = scope
+		for t := v.typ; t != nil; t = t.element {
+			setConstraintScope(scope, t.properties)
+		}
+	}
+}
+
 // optional checks whether the given property is optional, either due to Terraform or an overlay.
 func (v *variable) optional() bool {
 	if v.opt {
@@ -584,14 +865,19 @@ func (of *overlayFile) Doc() string  { return "" }
 func (of *overlayFile) Copy() bool   { return of.src != "" }
 
 func GenerateSchema(info tfbridge.ProviderInfo, sink diag.Sink) (pschema.PackageSpec, error) {
-	g, err := NewGenerator(GeneratorOptions{
+	return GenerateSchemaWithOptions(GeneratorOptions{
 		Package:      info.Name,
 		Version:      info.Version,
-		Language:     Schema,
 		ProviderInfo: info,
-		Root:         afero.NewMemMapFs(),
 		Sink:         sink,
 	})
+}
+
+// GenerateSchemaWithOptions generates the Pulumi schema for a provider using the given generator options. The
+// Language and Root options are ignored: the schema is always generated in memory.
+func GenerateSchemaWithOptions(opts GeneratorOptions) (pschema.PackageSpec, error) {
+	opts.Language, opts.Root = Schema, afero.NewMemMapFs()
+	g, err := NewGenerator(opts)
 	if err != nil {
 		return pschema.PackageSpec{}, errors.Wrapf(err, "failed to create generator")
 	}
@@ -618,6 +904,21 @@ type GeneratorOptions struct {
 	SkipDocs           bool
 	SkipExamples       bool
 	CoverageTracker    *CoverageTracker
+	// DocsSource locates the upstream docs of the Terraform provider. If it is nil, the provider's Go module is
+	// located with `go mod download`.
+	DocsSource DocsSource
+	// MissingDocsError makes it an error, rather than a warning, if the docs for a resource or data source cannot be
+	// found. Setting the PULUMI_MISSING_DOCS_ERROR environment variable to a truthy value has the same effect.
+	MissingDocsError bool
+	// CacheDir is the directory in which to cache the results of converting examples across runs. If it is empty,
+	// conversions are not cached.
+	CacheDir string
+	// Parallel is the maximum number of docs pages or examples to process concurrently. If it is less than 2, they are
+	// processed serially. The generated code does not depend on this setting.
+	Parallel int
+	// ReportPath is the file to which Generate writes a JSON report of the docs and examples of every resource and
+	// data source. If it is empty, no report is written.
+	ReportPath string
 }
 
 // NewGenerator returns a code-generator for the given language runtime and package info.
@@ -679,6 +980,25 @@ func NewGenerator(opts GeneratorOptions) (*Generator, error) {
 		provider:           providerShim,
 	}
 
+	docsSource := opts.DocsSource
+	if docsSource == nil {
+		docsSource = defaultDocsSource(info)
+	}
+
+	var cache *exampleCache
+	if opts.CacheDir != "" {
+		c, err := newExampleCache(opts.CacheDir, pkg, opts.TerraformVersion, info)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open the example cache")
+		}
+		cache = c
+	}
+
+	var report *docsReport
+	if opts.ReportPath != "" {
+		report = newDocsReport(pkg, version)
+	}
+
 	return &Generator{
 		pkg:          pkg,
 		version:      version,
@@ -697,9 +1017,32 @@ func NewGenerator(opts GeneratorOptions) (*Generator, error) {
 		skipDocs:         opts.SkipDocs,
 		skipExamples:     opts.SkipExamples,
 		coverageTracker:  opts.CoverageTracker,
+		missingDocsError: opts.MissingDocsError || isTruthy(os.Getenv("PULUMI_MISSING_DOCS_ERROR")),
+		docsSource:       docsSource,
+		exampleCache:     cache,
+		parallel:         opts.Parallel,
+		report:           report,
+		reportPath:       opts.ReportPath,
 	}, nil
 }
 
+// openDocsSource opens the generator's DocsSource the first time it is called and returns the result of doing so on
+// every call. If the source cannot be opened, the failure is reported once.
+func (g *Generator) openDocsSource() (afero.Fs, error) {
+	g.docsOnce.Do(func() {
+		g.docsRepo, g.docsErr = g.docsSource.Open()
+		if g.docsErr != nil {
+			g.docsErr = fmt.Errorf("unable to open the provider's docs: %w", g.docsErr)
+			if g.missingDocsError {
+				g.error("%v", g.docsErr)
+			} else {
+				g.warn("%v", g.docsErr)
+			}
+		}
+	})
+	return g.docsRepo, g.docsErr
+}
+
 func (g *Generator) error(f string, args ...interface{}) {
 	g.sink.Errorf(diag.Message("", f), args...)
 }
@@ -810,8 +1153,16 @@ func (g *Generator) Generate() error {
 	}
 
 	// Print out some documentation stats as a summary afterwards.
+	g.warnUnusedDocsEdits()
 	g.printDocStats()
 
+	// Write the docs and examples report, if requested.
+	if g.report != nil {
+		if err = g.report.write(g.reportPath); err != nil {
+			return errors.Wrapf(err, "failed to write report")
+		}
+	}
+
 	// Close the plugin host.
 	g.pluginHost.Close()
 
@@ -888,6 +1239,7 @@ func (g *Generator) gatherConfig() *module {
 		prop := propertyVariable(key, sch, custom[key], "", sch.Description(), true /*out*/, entityDocs{})
 		if prop != nil {
 			prop.config = true
+			setConstraintScope(&constraintScope{schema: cfg, info: custom}, []*variable{prop})
 			config.addMember(prop)
 		}
 	}
@@ -942,6 +1294,7 @@ func (g *Generator) gatherResources() (moduleMap, error) {
 
 	// For each resource, create its own dedicated type and module export.
 	var reserr error
+	var mapped []string
 	seen := make(map[string]bool)
 	for _, r := range stableResources(resources) {
 		info := g.info.Resources[r]
@@ -960,14 +1313,28 @@ func (g *Generator) gatherResources() (moduleMap, error) {
 			continue
 		}
 		seen[r] = true
+		mapped = append(mapped, r)
+	}
 
-		module, res, err := g.gatherResource(r, resources.Get(r), info, false)
-		if err != nil {
+	// Gather the mapped resources, whose docs may be processed concurrently, and add them in a stable order.
+	type gathered struct {
+		module string
+		res    *resourceType
+		err    error
+	}
+	results := make([]gathered, len(mapped))
+	g.parallelDo(len(mapped), func(i int) {
+		r := mapped[i]
+		module, res, err := g.gatherResource(r, resources.Get(r), g.info.Resources[r], false)
+		results[i] = gathered{module: module, res: res, err: err}
+	})
+	for _, result := range results {
+		if result.err != nil {
 			// Keep track of the error, but keep going, so we can expose more at once.
-			reserr = multierror.Append(reserr, err)
+			reserr = multierror.Append(reserr, result.err)
 		} else {
 			// Add any members returned to the specified module.
-			modules.ensureModule(module).addMember(res)
+			modules.ensureModule(result.module).addMember(result.res)
 		}
 	}
 	if reserr != nil {
@@ -1032,6 +1399,7 @@ func (g *Generator) gatherResource(rawname string,
 
 	// Next, gather up all properties.
 	var stateVars []*variable
+	var argumentsMissingDocs, attributesMissingDocs []string
 	for _, key := range stableSchemas(schema.Schema()) {
 		propschema := schema.Schema().Get(key)
 		if propschema.Removed() != "" {
@@ -1044,6 +1412,14 @@ func (g *Generator) gatherResource(rawname string,
 
 		propinfo := info.Fields[key]
 
+		if (doc == "" || doc == elidedDocComment) && rawdoc == "" {
+			if input(propschema, propinfo) {
+				argumentsMissingDocs = append(argumentsMissingDocs, key)
+			} else {
+				attributesMissingDocs = append(attributesMissingDocs, key)
+			}
+		}
+
 		// If we are generating a provider, we do not emit output property definitions as provider outputs are not
 		// yet implemented.
 		if !isProvider {
@@ -1071,6 +1447,14 @@ func (g *Generator) gatherResource(rawname string,
 		stateVar.opt = true
 		stateVars = append(stateVars, stateVar)
 	}
+	scope := &constraintScope{schema: schema.Schema(), info: info.Fields}
+	setConstraintScope(scope, res.outprops)
+	setConstraintScope(scope, res.inprops)
+	setConstraintScope(scope, stateVars)
+
+	if !isProvider {
+		g.report.recordEntity(ResourceDocs, rawname, string(info.Tok), argumentsMissingDocs, attributesMissingDocs)
+	}
 
 	className := res.name
 
@@ -1123,6 +1507,7 @@ func (g *Generator) gatherDataSources() (moduleMap, error) {
 
 	// For each data source, create its own dedicated function and module export.
 	var dserr error
+	var mapped []string
 	seen := make(map[string]bool)
 	for _, ds := range stableResources(sources) {
 		dsinfo := g.info.DataSources[ds]
@@ -1141,14 +1526,28 @@ func (g *Generator) gatherDataSources() (moduleMap, error) {
 			continue
 		}
 		seen[ds] = true
+		mapped = append(mapped, ds)
+	}
 
-		module, fun, err := g.gatherDataSource(ds, sources.Get(ds), dsinfo)
-		if err != nil {
+	// Gather the mapped data sources, whose docs may be processed concurrently, and add them in a stable order.
+	type gathered struct {
+		module string
+		fun    *resourceFunc
+		err    error
+	}
+	results := make([]gathered, len(mapped))
+	g.parallelDo(len(mapped), func(i int) {
+		ds := mapped[i]
+		module, fun, err := g.gatherDataSource(ds, sources.Get(ds), g.info.DataSources[ds])
+		results[i] = gathered{module: module, fun: fun, err: err}
+	})
+	for _, result := range results {
+		if result.err != nil {
 			// Keep track of the error, but keep going, so we can expose more at once.
-			dserr = multierror.Append(dserr, err)
+			dserr = multierror.Append(dserr, result.err)
 		} else {
 			// Add any members returned to the specified module.
-			modules.ensureModule(module).addMember(fun)
+			modules.ensureModule(result.module).addMember(result.fun)
 		}
 	}
 	if dserr != nil {
@@ -1209,6 +1608,7 @@ func (g *Generator) gatherDataSource(rawname string,
 	}
 
 	// See if arguments for this function are optional, and generate detailed metadata.
+	var argumentsMissingDocs, attributesMissingDocs []string
 	for _, arg := range stableSchemas(ds.Schema()) {
 		sch := ds.Schema().Get(arg)
 		if sch.Removed() != "" {
@@ -1219,6 +1619,9 @@ func (g *Generator) gatherDataSource(rawname string,
 		// Remember detailed information for every input arg (we will use it below).
 		if input(sch, cust) {
 			doc := getDescriptionFromParsedDocs(entityDocs, arg)
+			if doc == "" || doc == elidedDocComment {
+				argumentsMissingDocs = append(argumentsMissingDocs, arg)
+			}
 			argvar := propertyVariable(arg, sch, cust, doc, "", false /*out*/, entityDocs)
 			fun.args = append(fun.args, argvar)
 			if !argvar.optional() {
@@ -1228,9 +1631,16 @@ func (g *Generator) gatherDataSource(rawname string,
 
 		// Also remember properties for the resulting return data structure.
 		// Emit documentation for the property if available
+		if !input(sch, cust) && entityDocs.Attributes[arg] == "" {
+			attributesMissingDocs = append(attributesMissingDocs, arg)
+		}
 		fun.rets = append(fun.rets,
 			propertyVariable(arg, sch, cust, entityDocs.Attributes[arg], "", true /*out*/, entityDocs))
 	}
+	scope := &constraintScope{schema: ds.Schema(), info: info.Fields}
+	setConstraintScope(scope, fun.args)
+	setConstraintScope(scope, fun.rets)
+	g.report.recordEntity(DataSourceDocs, rawname, string(info.Tok), argumentsMissingDocs, attributesMissingDocs)
 
 	// If the data source's schema doesn't expose an id property, make one up since we'd like to expose it for data
 	// sources.
@@ -23,6 +23,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/go-multierror"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -175,6 +176,11 @@ func (nt *schemaNestedTypes) gatherFromPropertyType(declarer declarer, namePrefi
 	case kindObject:
 		baseName := nt.declareType(declarer, namePrefix, name, typ, isInput, pyMapCase)
 		nt.gatherFromProperties(declarer, baseName, typ.properties, isInput, pyMapCase)
+	case kindString:
+		// Enums are only used for inputs, and config variables are not strongly typed, so they do not use enums.
+		if _, isConfig := declarer.(*variable); isInput && !isConfig && len(typ.enumValues) != 0 {
+			nt.declareType(declarer, namePrefix, name, typ, isInput, pyMapCase)
+		}
 	}
 }
 
@@ -225,9 +231,9 @@ func (g *schemaGenerator) genPackageSpec(pack *pkg) (pschema.PackageSpec, error)
 	for _, mod := range pack.modules.values() {
 		// Generate nested types.
 		for _, t := range gatherSchemaNestedTypesForModule(mod) {
-			tok, ts := g.genObjectType(mod.name, t)
-			spec.Types[tok] = pschema.ComplexTypeSpec{
-				ObjectTypeSpec: ts,
+			tok, ts := g.genNestedType(mod.name, t)
+			if err := defineType(spec.Types, tok, ts); err != nil {
+				return pschema.PackageSpec{}, err
 			}
 		}
 
@@ -251,9 +257,9 @@ func (g *schemaGenerator) genPackageSpec(pack *pkg) (pschema.PackageSpec, error)
 
 	if pack.provider != nil {
 		for _, t := range gatherSchemaNestedTypesForMember(pack.provider) {
-			tok, ts := g.genObjectType("index", t)
-			spec.Types[tok] = pschema.ComplexTypeSpec{
-				ObjectTypeSpec: ts,
+			tok, ts := g.genNestedType("index", t)
+			if err := defineType(spec.Types, tok, ts); err != nil {
+				return pschema.PackageSpec{}, err
 			}
 		}
 		spec.Provider = g.genResourceType("index", pack.provider)
@@ -386,6 +392,23 @@ func (g *schemaGenerator) genProperty(mod string, prop *variable, pyMapCase bool
 	} else if prop.rawdoc != "" {
 		description = g.genRawDocComment(prop.rawdoc)
 	}
+	// Replacement and defaults are only documented along with replaceOnChanges, and never for outputs.
+	var candidates []string
+	if g.info.EmitReplaceOnChanges && !prop.out {
+		candidates = append(candidates, prop.forceNewDoc(description), prop.defaultsDoc())
+	}
+	var notes []string
+	for _, note := range append(candidates, prop.constraintsDoc()) {
+		if note != "" {
+			notes = append(notes, note)
+		}
+	}
+	if len(notes) != 0 {
+		if description != "" {
+			description = strings.TrimRight(description, "\n") + "\n\n"
+		}
+		description += strings.Join(notes, " ") + "\n"
+	}
 
 	language := map[string]pschema.RawMessage{}
 	if prop.info != nil && prop.info.CSharpName != "" {
@@ -426,6 +449,7 @@ func (g *schemaGenerator) genProperty(mod string, prop *variable, pyMapCase bool
 		DeprecationMessage: prop.deprecationMessage(),
 		Language:           language,
 		Secret:             secret,
+		ReplaceOnChanges:   g.info.EmitReplaceOnChanges && !prop.out && prop.forceNew(),
 	}
 }
 
@@ -543,9 +567,29 @@ func setEquals(a, b codegen.StringSet) bool {
 	return true
 }
 
-func (g *schemaGenerator) genObjectType(mod string, typInfo *schemaNestedType) (string, pschema.ObjectTypeSpec) {
-	typ := typInfo.typ
-	contract.Assert(typ.kind == kindObject)
+// defineType adds the given type to types. Nested types that share a token, such as enums that set the same
+// EnumInfo.Tok, are only defined once, and redefining a token with a different type is an error.
+func defineType(types map[string]pschema.ComplexTypeSpec, tok string, ts pschema.ComplexTypeSpec) error {
+	if existing, defined := types[tok]; defined && !reflect.DeepEqual(existing, ts) {
+		return fmt.Errorf("failed to define nested types: %v is already defined with a different type", tok)
+	}
+	types[tok] = ts
+	return nil
+}
+
+// genNestedType generates the spec for a nested type, which is either an object type or a string enum.
+func (g *schemaGenerator) genNestedType(mod string, typInfo *schemaNestedType) (string, pschema.ComplexTypeSpec) {
+	if typInfo.typ.kind == kindString {
+		return g.genEnumType(mod, typInfo)
+	}
+	tok, ts := g.genObjectType(mod, typInfo)
+	return tok, pschema.ComplexTypeSpec{ObjectTypeSpec: ts}
+}
+
+func (g *schemaGenerator) nestedTypeToken(mod string, typ *propertyType) string {
+	if typ.enum != nil && typ.enum.Tok != "" {
+		return string(typ.enum.Tok)
+	}
 
 	name := typ.name
 	if typ.nestedType != "" {
@@ -556,7 +600,39 @@ func (g *schemaGenerator) genObjectType(mod string, typInfo *schemaNestedType) (
 		mod = "index"
 	}
 
-	token := fmt.Sprintf("%s:%s/%s:%s", g.pkg, mod, name, name)
+	return fmt.Sprintf("%s:%s/%s:%s", g.pkg, mod, name, name)
+}
+
+func (g *schemaGenerator) genEnumType(mod string, typInfo *schemaNestedType) (string, pschema.ComplexTypeSpec) {
+	typ := typInfo.typ
+	contract.Assert(typ.kind == kindString && len(typ.enumValues) != 0)
+
+	spec := pschema.ComplexTypeSpec{
+		ObjectTypeSpec: pschema.ObjectTypeSpec{Type: "string"},
+	}
+	if typ.enum != nil {
+		spec.Description = typ.enum.Description
+		for _, v := range typ.enum.Values {
+			spec.Enum = append(spec.Enum, pschema.EnumValueSpec{
+				Name:               v.Name,
+				Description:        v.Description,
+				Value:              v.Value,
+				DeprecationMessage: v.DeprecationMessage,
+			})
+		}
+		return g.nestedTypeToken(mod, typ), spec
+	}
+	for _, v := range typ.enumValues {
+		spec.Enum = append(spec.Enum, pschema.EnumValueSpec{Value: v})
+	}
+	return g.nestedTypeToken(mod, typ), spec
+}
+
+func (g *schemaGenerator) genObjectType(mod string, typInfo *schemaNestedType) (string, pschema.ObjectTypeSpec) {
+	typ := typInfo.typ
+	contract.Assert(typ.kind == kindObject)
+
+	token := g.nestedTypeToken(mod, typ)
 
 	spec := pschema.ObjectTypeSpec{
 		Type: "object",
@@ -681,7 +757,21 @@ func (g *schemaGenerator) schemaType(mod string, typ *propertyType, out bool) ps
 
 	// First figure out the raw type.
 	switch typ.kind {
-	case kindBool, kindInt, kindFloat, kindString:
+	case kindString:
+		// Enums are only used for inputs: the values Terraform reports back need not be normalized to the listed
+		// values. Inputs accept either the enum or a plain string, so that programs written before the enum was
+		// introduced keep compiling.
+		if len(typ.enumValues) != 0 && typ.name != "" && !out {
+			return pschema.TypeSpec{
+				Type: "string",
+				OneOf: []pschema.TypeSpec{
+					{Type: "string"},
+					{Type: "string", Ref: "#/types/" + g.nestedTypeToken(mod, typ)},
+				},
+			}
+		}
+		return pschema.TypeSpec{Type: "string"}
+	case kindBool, kindInt, kindFloat:
 		t := g.schemaPrimitiveType(typ.kind)
 		contract.Assert(t != "")
 		return pschema.TypeSpec{Type: t}
@@ -693,73 +783,135 @@ func (g *schemaGenerator) schemaType(mod string, typ *propertyType, out bool) ps
 		return pschema.TypeSpec{Type: "object", AdditionalProperties: &additionalProperties}
 	case kindObject:
 		return pschema.TypeSpec{Ref: fmt.Sprintf("#/types/%s:%s/%s:%s", g.pkg, mod, typ.name, typ.name)}
+	case kindAny:
+		return pschema.TypeSpec{Ref: "pulumi.json#/Any"}
 	default:
 		contract.Failf("Unrecognized type kind: %v", typ.kind)
 		return pschema.TypeSpec{}
 	}
 }
 
-func (g *Generator) convertExamplesInPropertySpec(path string, spec pschema.PropertySpec) pschema.PropertySpec {
-	spec.Description = g.convertExamples(spec.Description, path, false)
-	spec.DeprecationMessage = g.convertExamples(spec.DeprecationMessage, path, false)
+// exampleConverter converts the examples in the docs at the given path in the schema.
+type exampleConverter func(docs, path string, stripSubsectionsWithErrors bool) string
+
+func convertExamplesInPropertySpec(convert exampleConverter, path string,
+	spec pschema.PropertySpec) pschema.PropertySpec {
+
+	spec.Description = convert(spec.Description, path, false)
+	spec.DeprecationMessage = convert(spec.DeprecationMessage, path, false)
 	return spec
 }
 
-func (g *Generator) convertExamplesInObjectSpec(path string, spec pschema.ObjectTypeSpec) pschema.ObjectTypeSpec {
-	spec.Description = g.convertExamples(spec.Description, path, false)
+func convertExamplesInObjectSpec(convert exampleConverter, path string,
+	spec pschema.ObjectTypeSpec) pschema.ObjectTypeSpec {
+
+	spec.Description = convert(spec.Description, path, false)
 	for name, prop := range spec.Properties {
-		spec.Properties[name] = g.convertExamplesInPropertySpec(fmt.Sprintf("%s/%s", path, name), prop)
+		spec.Properties[name] = convertExamplesInPropertySpec(convert, fmt.Sprintf("%s/%s", path, name), prop)
 	}
 	return spec
 }
 
-func (g *Generator) convertExamplesInResourceSpec(path string, spec pschema.ResourceSpec) pschema.ResourceSpec {
-	spec.Description = g.convertExamples(spec.Description, path, true)
-	spec.DeprecationMessage = g.convertExamples(spec.DeprecationMessage, path, false)
+func convertExamplesInResourceSpec(convert exampleConverter, path string,
+	spec pschema.ResourceSpec) pschema.ResourceSpec {
+
+	spec.Description = convert(spec.Description, path, true)
+	spec.DeprecationMessage = convert(spec.DeprecationMessage, path, false)
 	for name, prop := range spec.Properties {
-		spec.Properties[name] = g.convertExamplesInPropertySpec(fmt.Sprintf("%s/%s", path, name), prop)
+		spec.Properties[name] = convertExamplesInPropertySpec(convert, fmt.Sprintf("%s/%s", path, name), prop)
 	}
 	for name, prop := range spec.InputProperties {
-		spec.InputProperties[name] = g.convertExamplesInPropertySpec(fmt.Sprintf("%s/%s", path, name), prop)
+		spec.InputProperties[name] = convertExamplesInPropertySpec(convert, fmt.Sprintf("%s/%s", path, name), prop)
 	}
 	if spec.StateInputs != nil {
-		stateInputs := g.convertExamplesInObjectSpec(path+"/stateInputs", *spec.StateInputs)
+		stateInputs := convertExamplesInObjectSpec(convert, path+"/stateInputs", *spec.StateInputs)
 		spec.StateInputs = &stateInputs
 	}
 	return spec
 }
 
-func (g *Generator) convertExamplesInFunctionSpec(path string, spec pschema.FunctionSpec) pschema.FunctionSpec {
-	spec.Description = g.convertExamples(spec.Description, path, true)
+func convertExamplesInFunctionSpec(convert exampleConverter, path string,
+	spec pschema.FunctionSpec) pschema.FunctionSpec {
+
+	spec.Description = convert(spec.Description, path, true)
 	if spec.Inputs != nil {
-		inputs := g.convertExamplesInObjectSpec(path+"/inputs", *spec.Inputs)
+		inputs := convertExamplesInObjectSpec(convert, path+"/inputs", *spec.Inputs)
 		spec.Inputs = &inputs
 	}
 	if spec.Outputs != nil {
-		outputs := g.convertExamplesInObjectSpec(path+"/outputs", *spec.Outputs)
+		outputs := convertExamplesInObjectSpec(convert, path+"/outputs", *spec.Outputs)
 		spec.Outputs = &outputs
 	}
 	return spec
 }
 
-func (g *Generator) convertExamplesInSchema(spec pschema.PackageSpec) pschema.PackageSpec {
+func convertExamplesInPackageSpec(convert exampleConverter, spec pschema.PackageSpec) pschema.PackageSpec {
 	for name, variable := range spec.Config.Variables {
-		spec.Config.Variables[name] = g.convertExamplesInPropertySpec(name, variable)
+		spec.Config.Variables[name] = convertExamplesInPropertySpec(convert, name, variable)
 	}
 	for token, object := range spec.Types {
-		object.ObjectTypeSpec = g.convertExamplesInObjectSpec("#/types/"+token, object.ObjectTypeSpec)
+		object.ObjectTypeSpec = convertExamplesInObjectSpec(convert, "#/types/"+token, object.ObjectTypeSpec)
 		spec.Types[token] = object
 	}
-	spec.Provider = g.convertExamplesInResourceSpec("#/provider", spec.Provider)
+	spec.Provider = convertExamplesInResourceSpec(convert, "#/provider", spec.Provider)
 	for token, resource := range spec.Resources {
-		spec.Resources[token] = g.convertExamplesInResourceSpec("#/resources/"+token, resource)
+		spec.Resources[token] = convertExamplesInResourceSpec(convert, "#/resources/"+token, resource)
 	}
 	for token, function := range spec.Functions {
-		spec.Functions[token] = g.convertExamplesInFunctionSpec("#/functions/"+token, function)
+		spec.Functions[token] = convertExamplesInFunctionSpec(convert, "#/functions/"+token, function)
 	}
 	return spec
 }
 
+// exampleDocs identifies a piece of documentation whose examples are to be converted.
+type exampleDocs struct {
+	docs, path                 string
+	stripSubsectionsWithErrors bool
+}
+
+// convertExamplesInSchema converts the examples in every description in the schema. The descriptions are gathered
+// first and converted by up to g.parallel workers in order of their paths; the results are then substituted by path
+// and text, so the schema does not depend on the order in which conversions finish.
+func (g *Generator) convertExamplesInSchema(spec pschema.PackageSpec) pschema.PackageSpec {
+	var pending []exampleDocs
+	seen := map[exampleDocs]bool{}
+	spec = convertExamplesInPackageSpec(func(docs, path string, strip bool) string {
+		key := exampleDocs{docs: docs, path: path, stripSubsectionsWithErrors: strip}
+		if docs != "" && !seen[key] {
+			seen[key] = true
+			pending = append(pending, key)
+		}
+		return docs
+	}, spec)
+
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].path != pending[j].path {
+			return pending[i].path < pending[j].path
+		}
+		if pending[i].docs != pending[j].docs {
+			return pending[i].docs < pending[j].docs
+		}
+		return !pending[i].stripSubsectionsWithErrors && pending[j].stripSubsectionsWithErrors
+	})
+
+	results := make([]string, len(pending))
+	g.parallelDo(len(pending), func(i int) {
+		d := pending[i]
+		results[i] = g.convertExamples(d.docs, d.path, d.stripSubsectionsWithErrors)
+	})
+
+	converted := make(map[exampleDocs]string, len(pending))
+	for i, d := range pending {
+		converted[d] = results[i]
+	}
+	return convertExamplesInPackageSpec(func(docs, path string, strip bool) string {
+		if docs == "" {
+			return ""
+		}
+		return converted[exampleDocs{docs: docs, path: path, stripSubsectionsWithErrors: strip}]
+	}, spec)
+}
+
 func addExtraHclExamplesToResources(extraExamples []tfbridge.HclExampler, spec *pschema.PackageSpec) error {
 	var err error
 	for _, ex := range extraExamples {
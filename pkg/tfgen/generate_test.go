@@ -4,8 +4,12 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimschema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 	shimv1 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v1"
 )
 
@@ -18,3 +22,324 @@ func Test_DeprecationFromTFSchema(t *testing.T) {
 	deprecationMessage := v.deprecationMessage()
 	assert.Equal(t, "This is deprecated", deprecationMessage)
 }
+
+func Test_DynamicPropertyType(t *testing.T) {
+	sch := (&shimschema.Schema{Type: shim.TypeDynamic, Optional: true}).Shim()
+	typ := makePropertyType("obj", sch, nil, false, entityDocs{})
+	assert.Equal(t, typeKind(kindAny), typ.kind)
+
+	g := &schemaGenerator{pkg: "test"}
+	assert.Equal(t, pschema.TypeSpec{Ref: "pulumi.json#/Any"}, g.schemaType("index", typ, false))
+}
+
+func Test_EnumFromValidation(t *testing.T) {
+	sch := (&shimschema.Schema{
+		Type:       shim.TypeString,
+		Optional:   true,
+		Validation: &shim.SchemaValidation{AllowedValues: []string{"Standard", "Premium"}},
+	}).Shim()
+	prop := propertyVariable("sku_tier", sch, nil, "", "", false, entityDocs{})
+	assert.Equal(t, []string{"Standard", "Premium"}, prop.typ.enumValues)
+
+	nt := &schemaNestedTypes{nameToType: make(map[string]*schemaNestedType)}
+	nt.gatherFromProperties(&resourceType{name: "Account"}, "Account", []*variable{prop}, true, true)
+	enum, ok := nt.nameToType["AccountSkuTier"]
+	assert.True(t, ok)
+
+	g := &schemaGenerator{pkg: "test"}
+	tok, spec := g.genNestedType("index", enum)
+	assert.Equal(t, "test:index/AccountSkuTier:AccountSkuTier", tok)
+	assert.Equal(t, pschema.ComplexTypeSpec{
+		ObjectTypeSpec: pschema.ObjectTypeSpec{Type: "string"},
+		Enum:           []pschema.EnumValueSpec{{Value: "Standard"}, {Value: "Premium"}},
+	}, spec)
+
+	// Inputs accept plain strings as well as the enum.
+	assert.Equal(t, pschema.TypeSpec{
+		Type: "string",
+		OneOf: []pschema.TypeSpec{
+			{Type: "string"},
+			{Type: "string", Ref: "#/types/test:index/AccountSkuTier:AccountSkuTier"},
+		},
+	}, g.schemaType("index", prop.typ, false))
+	assert.Equal(t, pschema.TypeSpec{Type: "string"}, g.schemaType("index", prop.typ, true))
+
+	// Outputs do not declare enum types.
+	out := propertyVariable("sku_tier", sch, nil, "", "", true, entityDocs{})
+	nt = &schemaNestedTypes{nameToType: make(map[string]*schemaNestedType)}
+	nt.gatherFromProperties(&resourceType{name: "Account"}, "Account", []*variable{out}, false, true)
+	assert.Empty(t, nt.nameToType)
+
+	// Validators that ignore case are not emitted as enums.
+	sch = (&shimschema.Schema{
+		Type:       shim.TypeString,
+		Optional:   true,
+		Validation: &shim.SchemaValidation{AllowedValues: []string{"Standard", "Premium"}, IgnoreCase: true},
+	}).Shim()
+	prop = propertyVariable("sku_tier", sch, nil, "", "", false, entityDocs{})
+	assert.Empty(t, prop.typ.enumValues)
+	assert.Equal(t, "Must be one of `Standard`, `Premium`, in any case.", prop.constraintsDoc())
+
+	// Values that cannot be mapped to distinct identifiers are not emitted as enums.
+	assert.False(t, isSafeEnum([]string{"*", "a"}))
+	assert.False(t, isSafeEnum([]string{"a-b", "A_B"}))
+	assert.True(t, isSafeEnum([]string{"a-b", "c"}))
+}
+
+func Test_ConstraintsDoc(t *testing.T) {
+	min, max := 1.0, 65535.0
+	sch := (&shimschema.Schema{
+		Type:          shim.TypeInt,
+		Optional:      true,
+		ConflictsWith: []string{"port_range"},
+		ExactlyOneOf:  []string{"port", "named_port.0.port_name"},
+		RequiredWith:  []string{"protocol"},
+		Validation:    &shim.SchemaValidation{Min: &min, Max: &max},
+	}).Shim()
+	prop := propertyVariable("port", sch, nil, "", "The port to listen on.", false, entityDocs{})
+
+	g := &schemaGenerator{pkg: "test"}
+	assert.Equal(t, "The port to listen on.\n\n"+
+		"Conflicts with `portRange`. Exactly one of `port`, `namedPort.0.portName` must be set. "+
+		"If set, `protocol` must also be set. Must be between 1 and 65535.\n",
+		g.genProperty("index", prop, true).Description)
+
+	// Paths honor name overrides and flattened lists.
+	str := (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim()
+	scope := &constraintScope{
+		schema: shimschema.SchemaMap{
+			"port_range": str,
+			"protocol":   str,
+			"named_port": (&shimschema.Schema{
+				Type:     shim.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: (&shimschema.Resource{Schema: shimschema.SchemaMap{
+					"port_name": str,
+				}}).Shim(),
+			}).Shim(),
+		},
+		info: map[string]*tfbridge.SchemaInfo{
+			"protocol":   {Name: "transport"},
+			"named_port": {Elem: &tfbridge.SchemaInfo{Fields: map[string]*tfbridge.SchemaInfo{"port_name": {Name: "label"}}}},
+		},
+	}
+	setConstraintScope(scope, []*variable{prop})
+	assert.Equal(t, "Conflicts with `portRange`. Exactly one of `port`, `namedPort.label` must be set. "+
+		"If set, `transport` must also be set. Must be between 1 and 65535.", prop.constraintsDoc())
+}
+
+func Test_ReplaceOnChanges(t *testing.T) {
+	g := &schemaGenerator{pkg: "test"}
+
+	// Without the opt-in, ForceNew is neither emitted nor documented.
+	sch := (&shimschema.Schema{Type: shim.TypeString, Required: true, ForceNew: true}).Shim()
+	prop := propertyVariable("name", sch, nil, "", "The name.", false, entityDocs{})
+	spec := g.genProperty("index", prop, true)
+	assert.False(t, spec.ReplaceOnChanges)
+	assert.Equal(t, "The name.\n", spec.Description)
+
+	g.info.EmitReplaceOnChanges = true
+	spec = g.genProperty("index", prop, true)
+	assert.True(t, spec.ReplaceOnChanges)
+	assert.Equal(t, "The name.\n\nChanging this forces a new resource to be created.\n", spec.Description)
+
+	// Descriptions that already say so are left alone.
+	prop = propertyVariable("name", sch, nil, "", "The name. Changing this forces a new widget to be created.", false,
+		entityDocs{})
+	assert.Equal(t, "The name. Changing this forces a new widget to be created.\n",
+		g.genProperty("index", prop, true).Description)
+
+	// Outputs are skipped.
+	prop = propertyVariable("name", sch, nil, "", "The name.", true, entityDocs{})
+	spec = g.genProperty("index", prop, true)
+	assert.False(t, spec.ReplaceOnChanges)
+	assert.Equal(t, "The name.\n", spec.Description)
+
+	// Overlays take precedence over the TF schema.
+	prop = propertyVariable("name", sch, &tfbridge.SchemaInfo{ForceNew: tfbridge.BoolRef(false)}, "", "", false,
+		entityDocs{})
+	assert.False(t, g.genProperty("index", prop, true).ReplaceOnChanges)
+
+	sch = (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim()
+	prop = propertyVariable("name", sch, &tfbridge.SchemaInfo{ForceNew: tfbridge.BoolRef(true)}, "", "", false,
+		entityDocs{})
+	assert.True(t, g.genProperty("index", prop, true).ReplaceOnChanges)
+
+	// Config values never force replacement.
+	prop.config = true
+	assert.False(t, g.genProperty("config", prop, true).ReplaceOnChanges)
+}
+
+func Test_DefaultsDoc(t *testing.T) {
+	g := &schemaGenerator{pkg: "test"}
+	description := func(sch *shimschema.Schema, info *tfbridge.SchemaInfo, out bool) string {
+		prop := propertyVariable("size", sch.Shim(), info, "", "The size.", out, entityDocs{})
+		return g.genProperty("index", prop, true).Description
+	}
+
+	// Defaults are only documented along with replaceOnChanges.
+	assert.Equal(t, "The size.\n",
+		description(&shimschema.Schema{Type: shim.TypeInt, Optional: true, Default: 10}, nil, false))
+
+	g.info.EmitReplaceOnChanges = true
+	assert.Equal(t, "The size.\n\nDefaults to `10`.\n",
+		description(&shimschema.Schema{Type: shim.TypeInt, Optional: true, Default: 10}, nil, false))
+	assert.Equal(t, "The size.\n\nDefaults to an empty string.\n",
+		description(&shimschema.Schema{Type: shim.TypeString, Optional: true, Default: ""}, nil, false))
+	assert.Equal(t, "The size.\n\nIf not set, the provider computes a value.\n",
+		description(&shimschema.Schema{Type: shim.TypeInt, Optional: true, Computed: true}, nil, false))
+	assert.Equal(t, "The size.\n",
+		description(&shimschema.Schema{Type: shim.TypeInt, Optional: true, Computed: true}, nil, true))
+
+	// Defaults from the provider's configuration are documented; literal Pulumi defaults are part of the schema.
+	configDefault := &tfbridge.SchemaInfo{Default: &tfbridge.DefaultInfo{Config: "defaultSize"}}
+	assert.Equal(t, "The size.\n\nIf not set, defaults to the value of the `defaultSize` provider configuration "+
+		"setting.\n",
+		description(&shimschema.Schema{Type: shim.TypeInt, Optional: true, Default: 10}, configDefault, false))
+	valueDefault := &tfbridge.SchemaInfo{Default: &tfbridge.DefaultInfo{Value: 20}}
+	assert.Equal(t, "The size.\n",
+		description(&shimschema.Schema{Type: shim.TypeInt, Optional: true, Default: 10}, valueDefault, false))
+
+	// Environment variables take precedence over every other default.
+	envDefault := &tfbridge.SchemaInfo{Default: &tfbridge.DefaultInfo{
+		EnvVars: []string{"TEST_SIZE"},
+		Config:  "defaultSize",
+	}}
+	assert.Equal(t, "The size.\n\nIf not set, defaults to the value of the `TEST_SIZE` environment variable.\n",
+		description(&shimschema.Schema{Type: shim.TypeInt, Optional: true}, envDefault, false))
+	envDefault = &tfbridge.SchemaInfo{Default: &tfbridge.DefaultInfo{
+		EnvVars: []string{"TEST_SIZE", "SIZE"},
+		Value:   20,
+	}}
+	assert.Equal(t, "The size.\n\nIf not set, defaults to the value of the first of the `TEST_SIZE`, `SIZE` "+
+		"environment variables that is set, or `20` if none is set.\n",
+		description(&shimschema.Schema{Type: shim.TypeInt, Optional: true}, envDefault, false))
+
+	// Defaults of provider configuration variables are documented too.
+	prop := propertyVariable("region", (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		&tfbridge.SchemaInfo{Default: &tfbridge.DefaultInfo{EnvVars: []string{"TEST_REGION"}}}, "", "The region.",
+		false, entityDocs{})
+	prop.config = true
+	assert.Equal(t, "The region.\n\nIf not set, defaults to the value of the `TEST_REGION` environment variable.\n",
+		g.genProperty("config", prop, true).Description)
+	prop = propertyVariable("retries", (&shimschema.Schema{Type: shim.TypeInt, Optional: true, Default: 3}).Shim(),
+		nil, "", "The retries.", false, entityDocs{})
+	prop.config = true
+	assert.Equal(t, "The retries.\n\nDefaults to `3`.\n", g.genProperty("config", prop, true).Description)
+
+	// Defaults are documented before constraints.
+	max := 100.0
+	assert.Equal(t, "The size.\n\nDefaults to `10`. Must be at most 100.\n",
+		description(&shimschema.Schema{
+			Type:       shim.TypeInt,
+			Optional:   true,
+			Default:    10,
+			Validation: &shim.SchemaValidation{Max: &max},
+		}, nil, false))
+}
+
+func Test_EnumFromSchemaInfo(t *testing.T) {
+	sch := (&shimschema.Schema{
+		Type:       shim.TypeString,
+		Optional:   true,
+		Validation: &shim.SchemaValidation{AllowedValues: []string{"Standard", "Premium"}},
+	}).Shim()
+	info := &tfbridge.SchemaInfo{Enum: &tfbridge.EnumInfo{
+		Description: "The tier of an account.",
+		Values: []tfbridge.EnumValueInfo{
+			{Value: "Standard", Description: "The standard tier."},
+			{Value: "premium-v2", Name: "Premium"},
+			{Value: "Legacy", DeprecationMessage: "Use Standard instead."},
+		},
+	}}
+
+	// The overlay takes precedence over the Terraform validator.
+	prop := propertyVariable("sku_tier", sch, info, "", "", false, entityDocs{})
+	assert.Equal(t, []string{"Standard", "premium-v2", "Legacy"}, prop.typ.enumValues)
+
+	nt := &schemaNestedTypes{nameToType: make(map[string]*schemaNestedType)}
+	nt.gatherFromProperties(&resourceType{name: "Account"}, "Account", []*variable{prop}, true, true)
+	enum, ok := nt.nameToType["AccountSkuTier"]
+	assert.True(t, ok)
+
+	g := &schemaGenerator{pkg: "test"}
+	tok, spec := g.genNestedType("index", enum)
+	assert.Equal(t, "test:index/AccountSkuTier:AccountSkuTier", tok)
+	assert.Equal(t, pschema.ComplexTypeSpec{
+		ObjectTypeSpec: pschema.ObjectTypeSpec{Type: "string", Description: "The tier of an account."},
+		Enum: []pschema.EnumValueSpec{
+			{Value: "Standard", Description: "The standard tier."},
+			{Value: "premium-v2", Name: "Premium"},
+			{Value: "Legacy", DeprecationMessage: "Use Standard instead."},
+		},
+	}, spec)
+	assert.Equal(t, pschema.TypeSpec{
+		Type: "string",
+		OneOf: []pschema.TypeSpec{
+			{Type: "string"},
+			{Type: "string", Ref: "#/types/test:index/AccountSkuTier:AccountSkuTier"},
+		},
+	}, g.schemaType("index", prop.typ, false))
+	assert.Equal(t, pschema.TypeSpec{Type: "string"}, g.schemaType("index", prop.typ, true))
+
+	// Properties that share an enum token reference the same type.
+	info.Enum.Tok = "test:index/Tier:Tier"
+	str := (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim()
+	skuTier := propertyVariable("sku_tier", str, info, "", "", false, entityDocs{})
+	backupTier := propertyVariable("backup_tier", str, info, "", "", false, entityDocs{})
+
+	nt = &schemaNestedTypes{nameToType: make(map[string]*schemaNestedType)}
+	nt.gatherFromProperties(&resourceType{name: "Account"}, "Account", []*variable{skuTier, backupTier}, true, true)
+	for _, name := range []string{"AccountSkuTier", "AccountBackupTier"} {
+		tok, _ := g.genNestedType("index", nt.nameToType[name])
+		assert.Equal(t, "test:index/Tier:Tier", tok)
+	}
+	assert.Equal(t, pschema.TypeSpec{
+		Type:  "string",
+		OneOf: []pschema.TypeSpec{{Type: "string"}, {Type: "string", Ref: "#/types/test:index/Tier:Tier"}},
+	}, g.schemaType("index", backupTier.typ, false))
+
+	// Overlays are compared by value, so copies of an enum describe the same type.
+	copied := *info.Enum
+	copied.Values = append([]tfbridge.EnumValueInfo(nil), info.Enum.Values...)
+	original := propertyVariable("sku_tier", str, info, "", "", false, entityDocs{})
+	assert.True(t, original.typ.equals(propertyVariable("sku_tier", str, &tfbridge.SchemaInfo{Enum: &copied}, "", "",
+		false, entityDocs{}).typ))
+
+	// Redefining a shared token with different values is an error.
+	types := map[string]pschema.ComplexTypeSpec{}
+	_, spec = g.genNestedType("index", nt.nameToType["AccountSkuTier"])
+	assert.NoError(t, defineType(types, "test:index/Tier:Tier", spec))
+	assert.NoError(t, defineType(types, "test:index/Tier:Tier", spec))
+	copied.Values = copied.Values[:1]
+	other := propertyVariable("other_tier", str, &tfbridge.SchemaInfo{Enum: &copied}, "", "", false, entityDocs{})
+	nt = &schemaNestedTypes{nameToType: make(map[string]*schemaNestedType)}
+	nt.gatherFromProperties(&resourceType{name: "Backup"}, "Backup", []*variable{other}, true, true)
+	_, spec = g.genNestedType("index", nt.nameToType["BackupOtherTier"])
+	assert.EqualError(t, defineType(types, "test:index/Tier:Tier", spec),
+		"failed to define nested types: test:index/Tier:Tier is already defined with a different type")
+}
+
+func Test_MapOfObjectsPropertyType(t *testing.T) {
+	object := (&shimschema.Resource{Schema: shimschema.SchemaMap{
+		"name": (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+	}}).Shim()
+
+	// A map whose Elem is a resource is a single object.
+	single := (&shimschema.Schema{Type: shim.TypeMap, Optional: true, Elem: object}).Shim()
+	typ := makePropertyType("obj", single, nil, false, entityDocs{})
+	assert.Equal(t, typeKind(kindObject), typ.kind)
+
+	// A map whose Elem is an object schema is a map of objects.
+	m := (&shimschema.Schema{
+		Type:     shim.TypeMap,
+		Optional: true,
+		Elem:     (&shimschema.Schema{Type: shim.TypeMap, Elem: object}).Shim(),
+	}).Shim()
+	typ = makePropertyType("obj", m, nil, false, entityDocs{})
+	assert.Equal(t, typeKind(kindMap), typ.kind)
+	if assert.NotNil(t, typ.element) {
+		assert.Equal(t, typeKind(kindObject), typ.element.kind)
+	}
+}
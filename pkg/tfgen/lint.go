@@ -0,0 +1,380 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// lintIssue is a single inconsistency between a ProviderInfo and the schema of the Terraform provider it describes.
+// Location identifies the offending part of the ProviderInfo using Go field and map-key syntax, e.g.
+// `Resources["aws_s3_bucket"].Fields["acl"]`.
+type lintIssue struct {
+	Location string
+	Message  string
+}
+
+func (i lintIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Location, i.Message)
+}
+
+// linter walks a ProviderInfo against its Terraform provider schema and collects the inconsistencies it finds.
+type linter struct {
+	pkg    string
+	info   tfbridge.ProviderInfo
+	issues []lintIssue
+
+	configNames    map[string]bool                    // the Pulumi names of all config variables.
+	resourceToks   map[tokens.Type]string             // the location that first used each resource token.
+	dataSourceToks map[tokens.ModuleMember]string     // the location that first used each data source token.
+	enumToks       map[tokens.Type]string             // the location that first used each enum token.
+	enums          map[tokens.Type]*tfbridge.EnumInfo // the first enum declared with each enum token.
+}
+
+// lintProviderInfo checks the given ProviderInfo against the schema of its Terraform provider, ProviderInfo.P, and
+// returns every inconsistency found, in a stable order. pkg is the name of the Pulumi package that all tokens must
+// belong to.
+func lintProviderInfo(pkg string, info tfbridge.ProviderInfo) []lintIssue {
+	l := &linter{
+		pkg:            pkg,
+		info:           info,
+		configNames:    map[string]bool{},
+		resourceToks:   map[tokens.Type]string{},
+		dataSourceToks: map[tokens.ModuleMember]string{},
+		enumToks:       map[tokens.Type]string{},
+		enums:          map[tokens.Type]*tfbridge.EnumInfo{},
+	}
+	if info.P == nil {
+		l.errorf("P", "the Terraform provider is not set")
+		return l.issues
+	}
+
+	l.lintConfig()
+	l.lintResources()
+	l.lintDataSources()
+	return l.issues
+}
+
+func (l *linter) errorf(location, format string, args ...interface{}) {
+	l.issues = append(l.issues, lintIssue{Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) lintConfig() {
+	cfg := l.info.P.Schema()
+	if cfg != nil {
+		cfg.Range(func(key string, sch shim.Schema) bool {
+			name := tfbridge.TerraformToPulumiName(key, sch, l.info.Config[key], false)
+			l.configNames[name] = true
+			return true
+		})
+	}
+	for name := range l.info.ExtraConfig {
+		l.configNames[name] = true
+	}
+
+	l.lintFields("Config", cfg, l.info.Config)
+}
+
+func (l *linter) lintResources() {
+	resources := l.info.P.ResourcesMap()
+	for _, name := range sortedKeys(l.info.Resources) {
+		loc := fmt.Sprintf("Resources[%q]", name)
+		info := l.info.Resources[name]
+
+		res, ok := resources.GetOk(name)
+		if !ok {
+			l.errorf(loc, "resource %q does not exist in the Terraform provider", name)
+		}
+		if info == nil {
+			continue
+		}
+
+		if info.Tok != "" {
+			l.lintToken(loc+".Tok", string(info.Tok))
+			if first, seen := l.resourceToks[info.Tok]; seen {
+				l.errorf(loc+".Tok", "token %q is already used by %s", info.Tok, first)
+			} else {
+				l.resourceToks[info.Tok] = loc
+			}
+		}
+
+		if res != nil {
+			l.lintFields(loc+".Fields", res.Schema(), info.Fields)
+		}
+	}
+}
+
+func (l *linter) lintDataSources() {
+	dataSources := l.info.P.DataSourcesMap()
+	for _, name := range sortedKeys(l.info.DataSources) {
+		loc := fmt.Sprintf("DataSources[%q]", name)
+		info := l.info.DataSources[name]
+
+		ds, ok := dataSources.GetOk(name)
+		if !ok {
+			l.errorf(loc, "data source %q does not exist in the Terraform provider", name)
+		}
+		if info == nil {
+			continue
+		}
+
+		if info.Tok != "" {
+			l.lintToken(loc+".Tok", string(info.Tok))
+			if first, seen := l.dataSourceToks[info.Tok]; seen {
+				l.errorf(loc+".Tok", "token %q is already used by %s", info.Tok, first)
+			} else {
+				l.dataSourceToks[info.Tok] = loc
+			}
+		}
+
+		if ds != nil {
+			l.lintFields(loc+".Fields", ds.Schema(), info.Fields)
+		}
+	}
+}
+
+// lintToken checks that a resource, data source or type token is well-formed and belongs to the package.
+func (l *linter) lintToken(loc, tok string) {
+	parts := strings.Split(tok, tokens.TokenDelimiter)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		l.errorf(loc, "token %q is not of the form <package>:<module>:<name>", tok)
+		return
+	}
+	if parts[0] != l.pkg {
+		l.errorf(loc, "token %q is outside of package %q", tok, l.pkg)
+	}
+}
+
+// lintFields checks a map of field overlays against the Terraform schema of the object they describe.
+func (l *linter) lintFields(loc string, schemaMap shim.SchemaMap, fields map[string]*tfbridge.SchemaInfo) {
+	for _, key := range sortedKeys(fields) {
+		fieldLoc := fmt.Sprintf("%s[%q]", loc, key)
+
+		var sch shim.Schema
+		if schemaMap != nil {
+			sch, _ = schemaMap.GetOk(key)
+		}
+		if sch == nil {
+			l.errorf(fieldLoc, "field %q does not exist in the Terraform schema", key)
+			continue
+		}
+		if info := fields[key]; info != nil {
+			l.lintSchema(fieldLoc, sch, info)
+		}
+	}
+}
+
+// lintSchema checks the overlay for a single field against the field's Terraform schema.
+func (l *linter) lintSchema(loc string, sch shim.Schema, info *tfbridge.SchemaInfo) {
+	typ := valueTypeName(sch.Type())
+	isCollection := sch.Type() == shim.TypeList || sch.Type() == shim.TypeSet
+
+	if info.MaxItemsOne != nil && !isCollection {
+		l.errorf(loc+".MaxItemsOne", "MaxItemsOne is set on a field of type %s, which is not a list or set", typ)
+	}
+	if info.Asset != nil && sch.Type() != shim.TypeString {
+		l.errorf(loc+".Asset", "Asset is set on a field of type %s; assets can only be translated to strings", typ)
+	}
+	l.lintDefault(loc, info.Default)
+	l.lintEnum(loc, sch, info.Enum)
+
+	res, elemIsResource := sch.Elem().(shim.Resource)
+	switch {
+	case elemIsResource && sch.Type() == shim.TypeMap:
+		l.lintObjectFields(loc, res, info)
+	case info.Fields == nil:
+	case elemIsResource:
+		l.errorf(loc+".Fields", "Fields has no effect on a field of type %s; set Elem.Fields instead", typ)
+	default:
+		l.errorf(loc+".Fields", "Fields is set on a field of type %s, which has no nested fields", typ)
+	}
+
+	if info.Elem == nil {
+		return
+	}
+	switch elem := sch.Elem().(type) {
+	case shim.Schema:
+		l.lintSchema(loc+".Elem", elem, info.Elem)
+	case shim.Resource:
+		l.lintObject(loc+".Elem", elem, info.Elem)
+	default:
+		l.errorf(loc+".Elem", "Elem is set on a field of type %s, which has no element type", typ)
+	}
+}
+
+// lintObjectFields checks the field overlays of a single nested object, i.e. a map-typed field whose element is a
+// resource. The schema generator reads the overlays of such a field from Elem.Fields, whereas the runtime reads them
+// from Fields, so both must be set to the same overlays.
+func (l *linter) lintObjectFields(loc string, res shim.Resource, info *tfbridge.SchemaInfo) {
+	var elemFields map[string]*tfbridge.SchemaInfo
+	if info.Elem != nil {
+		elemFields = info.Elem.Fields
+	}
+	switch {
+	case reflect.DeepEqual(info.Fields, elemFields):
+	case elemFields == nil:
+		l.errorf(loc+".Fields", "Fields is only read at runtime on a nested object; set Elem.Fields to the same "+
+			"overlays for the schema generator")
+		l.lintFields(loc+".Fields", res.Schema(), info.Fields)
+	case info.Fields == nil:
+		l.errorf(loc+".Elem.Fields", "Elem.Fields is only read by the schema generator on a nested object; set "+
+			"Fields to the same overlays for the runtime")
+	default:
+		l.errorf(loc+".Fields", "Fields and Elem.Fields differ on a nested object; the schema generator reads "+
+			"Elem.Fields but the runtime reads Fields")
+	}
+}
+
+// lintObject checks the overlay for the element of a list, set or map of nested blocks.
+func (l *linter) lintObject(loc string, res shim.Resource, info *tfbridge.SchemaInfo) {
+	if info.MaxItemsOne != nil {
+		l.errorf(loc+".MaxItemsOne", "MaxItemsOne is set on a nested block, which is not a list or set")
+	}
+	if info.Asset != nil {
+		l.errorf(loc+".Asset", "Asset is set on a nested block; assets can only be translated to strings")
+	}
+	if info.Elem != nil {
+		l.errorf(loc+".Elem", "Elem is set on a nested block, which has no element type")
+	}
+	l.lintDefault(loc, info.Default)
+	l.lintFields(loc+".Fields", res.Schema(), info.Fields)
+}
+
+func (l *linter) lintEnum(loc string, sch shim.Schema, info *tfbridge.EnumInfo) {
+	if info == nil {
+		return
+	}
+	if sch.Type() != shim.TypeString {
+		l.errorf(loc+".Enum", "Enum is set on a field of type %s; enums can only be used for strings",
+			valueTypeName(sch.Type()))
+	}
+	if len(info.Values) == 0 {
+		l.errorf(loc+".Enum.Values", "Enum has no values")
+	}
+	seen := make(map[string]bool)
+	for i, v := range info.Values {
+		if seen[v.Value] {
+			l.errorf(fmt.Sprintf("%s.Enum.Values[%d]", loc, i), "enum value %q is listed more than once", v.Value)
+		}
+		seen[v.Value] = true
+	}
+	if info.Tok != "" {
+		l.lintToken(loc+".Enum.Tok", string(info.Tok))
+		if first, seen := l.enumToks[info.Tok]; !seen {
+			l.enumToks[info.Tok], l.enums[info.Tok] = loc+".Enum", info
+		} else if !enumInfoEqual(l.enums[info.Tok], info) {
+			l.errorf(loc+".Enum.Tok", "enum token %q is already used by %s, which describes a different enum",
+				info.Tok, first)
+		}
+	}
+}
+
+func (l *linter) lintDefault(loc string, info *tfbridge.DefaultInfo) {
+	if info == nil || info.Config == "" {
+		return
+	}
+	if !l.configNames[info.Config] {
+		l.errorf(loc+".Default.Config", "Default.Config names unknown config key %q", info.Config)
+	}
+}
+
+// valueTypeName returns the name of a Terraform value type for use in lint messages.
+func valueTypeName(t shim.ValueType) string {
+	switch t {
+	case shim.TypeBool:
+		return "bool"
+	case shim.TypeInt:
+		return "int"
+	case shim.TypeFloat:
+		return "float"
+	case shim.TypeString:
+		return "string"
+	case shim.TypeList:
+		return "list"
+	case shim.TypeMap:
+		return "map"
+	case shim.TypeSet:
+		return "set"
+	case shim.TypeDynamic:
+		return "dynamic"
+	default:
+		return "invalid"
+	}
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]*tfbridge.ResourceInfo:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*tfbridge.DataSourceInfo:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*tfbridge.SchemaInfo:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// printLintIssues writes one line per issue to w.
+func printLintIssues(w io.Writer, issues []lintIssue) error {
+	for _, issue := range issues {
+		if _, err := fmt.Fprintln(w, issue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newLintCmd(pkg string, prov tfbridge.ProviderInfo) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint",
+		Args:  cmdutil.NoArgs,
+		Short: "Check the provider's mapping against its Terraform schema",
+		Long: "Check the provider's mapping against its Terraform schema.\n" +
+			"\n" +
+			"Every inconsistency between the ProviderInfo and the schema of the Terraform provider it\n" +
+			"describes is reported along with its location, e.g. overlays for fields that do not exist,\n" +
+			"MaxItemsOne on a field that is not a list, or tokens that are duplicated or lie outside of\n" +
+			"the package. The command exits with a non-zero status if any issues are found.\n",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			issues := lintProviderInfo(pkg, prov)
+			if err := printLintIssues(cmd.OutOrStdout(), issues); err != nil {
+				return err
+			}
+			if len(issues) > 0 {
+				return fmt.Errorf("found %d issue(s) in the provider mapping", len(issues))
+			}
+			return nil
+		}),
+	}
+}
@@ -0,0 +1,211 @@
+package tfgen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimschema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func testLintProvider() shim.Provider {
+	str := (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim()
+	num := (&shimschema.Schema{Type: shim.TypeInt, Optional: true}).Shim()
+	block := (&shimschema.Schema{
+		Type:     shim.TypeList,
+		Optional: true,
+		Elem: (&shimschema.Resource{Schema: shimschema.SchemaMap{
+			"inner": str,
+		}}).Shim(),
+	}).Shim()
+	tags := (&shimschema.Schema{Type: shim.TypeMap, Optional: true, Elem: str}).Shim()
+	object := (&shimschema.Resource{Schema: shimschema.SchemaMap{"mode": str}}).Shim()
+	settings := (&shimschema.Schema{Type: shim.TypeMap, Optional: true, Elem: object}).Shim()
+	rules := (&shimschema.Schema{
+		Type:     shim.TypeMap,
+		Optional: true,
+		Elem:     (&shimschema.Schema{Type: shim.TypeMap, Elem: object}).Shim(),
+	}).Shim()
+
+	return (&shimschema.Provider{
+		Schema: shimschema.SchemaMap{"region": str},
+		ResourcesMap: shimschema.ResourceMap{
+			"test_widget": (&shimschema.Resource{Schema: shimschema.SchemaMap{
+				"name":     str,
+				"count":    num,
+				"block":    block,
+				"tags":     tags,
+				"settings": settings,
+				"rules":    rules,
+			}}).Shim(),
+			"test_gadget": (&shimschema.Resource{Schema: shimschema.SchemaMap{}}).Shim(),
+		},
+		DataSourcesMap: shimschema.ResourceMap{
+			"test_widget": (&shimschema.Resource{Schema: shimschema.SchemaMap{"name": str, "kind": str}}).Shim(),
+		},
+	}).Shim()
+}
+
+func TestLintProviderInfo(t *testing.T) {
+	t.Run("clean", func(t *testing.T) {
+		settingsFields := map[string]*tfbridge.SchemaInfo{"mode": {Name: "settingsMode"}}
+		info := tfbridge.ProviderInfo{
+			P:      testLintProvider(),
+			Name:   "test",
+			Config: map[string]*tfbridge.SchemaInfo{"region": {}},
+			Resources: map[string]*tfbridge.ResourceInfo{
+				"test_widget": {
+					Tok: "test:index:Widget",
+					Fields: map[string]*tfbridge.SchemaInfo{
+						"name": {
+							Default: &tfbridge.DefaultInfo{Config: "region"},
+							Enum: &tfbridge.EnumInfo{
+								Tok:    "test:index:WidgetName",
+								Values: []tfbridge.EnumValueInfo{{Value: "a"}, {Value: "b"}},
+							},
+						},
+						"block": {MaxItemsOne: tfbridge.True(), Elem: &tfbridge.SchemaInfo{
+							Fields: map[string]*tfbridge.SchemaInfo{"inner": {Name: "innerValue"}},
+						}},
+						"tags": {Elem: &tfbridge.SchemaInfo{}},
+						"settings": {
+							Fields: settingsFields,
+							Elem:   &tfbridge.SchemaInfo{Fields: settingsFields},
+						},
+						"rules": {Elem: &tfbridge.SchemaInfo{
+							Fields: map[string]*tfbridge.SchemaInfo{"mode": {Name: "ruleMode"}},
+							Elem: &tfbridge.SchemaInfo{
+								Fields: map[string]*tfbridge.SchemaInfo{"mode": {Name: "ruleMode"}},
+							},
+						}},
+					},
+				},
+				"test_gadget": {Tok: "test:index:Gadget"},
+			},
+			DataSources: map[string]*tfbridge.DataSourceInfo{
+				"test_widget": {
+					Tok: "test:index:getWidget",
+					Fields: map[string]*tfbridge.SchemaInfo{
+						"name": {Enum: &tfbridge.EnumInfo{
+							Tok:    "test:index:WidgetName",
+							Values: []tfbridge.EnumValueInfo{{Value: "a"}, {Value: "b"}},
+						}},
+					},
+				},
+			},
+		}
+		assert.Empty(t, lintProviderInfo("test", info))
+	})
+
+	t.Run("issues", func(t *testing.T) {
+		info := tfbridge.ProviderInfo{
+			P:      testLintProvider(),
+			Name:   "test",
+			Config: map[string]*tfbridge.SchemaInfo{"zone": {}},
+			Resources: map[string]*tfbridge.ResourceInfo{
+				"test_widget": {
+					Tok: "test:index:Widget",
+					Fields: map[string]*tfbridge.SchemaInfo{
+						"missing": {},
+						"name":    {MaxItemsOne: tfbridge.True(), Default: &tfbridge.DefaultInfo{Config: "zone"}},
+						"count": {
+							Asset: &tfbridge.AssetTranslation{Kind: tfbridge.FileAsset},
+							Enum: &tfbridge.EnumInfo{
+								Tok:    "other:index:Count",
+								Values: []tfbridge.EnumValueInfo{{Value: "one"}, {Value: "one"}},
+							},
+						},
+						"block": {
+							Fields: map[string]*tfbridge.SchemaInfo{"inner": {}},
+							Elem: &tfbridge.SchemaInfo{Fields: map[string]*tfbridge.SchemaInfo{
+								"other": {},
+							}},
+						},
+						"tags":     {Elem: &tfbridge.SchemaInfo{Elem: &tfbridge.SchemaInfo{}}},
+						"settings": {Fields: map[string]*tfbridge.SchemaInfo{"missing": {}}},
+						"rules": {Elem: &tfbridge.SchemaInfo{
+							Fields: map[string]*tfbridge.SchemaInfo{"mode": {Name: "ruleMode"}},
+							Elem: &tfbridge.SchemaInfo{
+								Fields: map[string]*tfbridge.SchemaInfo{"mode": {Name: "mode"}},
+							},
+						}},
+					},
+				},
+				"test_gadget":  {Tok: "test:index:Widget"},
+				"test_missing": {Tok: "other:index:Missing"},
+			},
+			DataSources: map[string]*tfbridge.DataSourceInfo{
+				"test_widget": {
+					Tok: "test:getWidget",
+					Fields: map[string]*tfbridge.SchemaInfo{
+						"name": {Enum: &tfbridge.EnumInfo{
+							Tok:    "other:index:Count",
+							Values: []tfbridge.EnumValueInfo{{Value: "one"}},
+						}},
+						"kind": {Enum: &tfbridge.EnumInfo{
+							Tok:    "other:index:Count",
+							Values: []tfbridge.EnumValueInfo{{Value: "one"}, {Value: "one"}},
+						}},
+					},
+				},
+			},
+		}
+
+		issues := lintProviderInfo("test", info)
+		var buf bytes.Buffer
+		assert.NoError(t, printLintIssues(&buf, issues))
+		assert.Equal(t, []string{
+			`Config["zone"]: ` +
+				`field "zone" does not exist in the Terraform schema`,
+			`Resources["test_missing"]: ` +
+				`resource "test_missing" does not exist in the Terraform provider`,
+			`Resources["test_missing"].Tok: ` +
+				`token "other:index:Missing" is outside of package "test"`,
+			`Resources["test_widget"].Tok: ` +
+				`token "test:index:Widget" is already used by Resources["test_gadget"]`,
+			`Resources["test_widget"].Fields["block"].Fields: ` +
+				`Fields has no effect on a field of type list; set Elem.Fields instead`,
+			`Resources["test_widget"].Fields["block"].Elem.Fields["other"]: ` +
+				`field "other" does not exist in the Terraform schema`,
+			`Resources["test_widget"].Fields["count"].Asset: ` +
+				`Asset is set on a field of type int; assets can only be translated to strings`,
+			`Resources["test_widget"].Fields["count"].Enum: ` +
+				`Enum is set on a field of type int; enums can only be used for strings`,
+			`Resources["test_widget"].Fields["count"].Enum.Values[1]: ` +
+				`enum value "one" is listed more than once`,
+			`Resources["test_widget"].Fields["count"].Enum.Tok: ` +
+				`token "other:index:Count" is outside of package "test"`,
+			`Resources["test_widget"].Fields["missing"]: ` +
+				`field "missing" does not exist in the Terraform schema`,
+			`Resources["test_widget"].Fields["name"].MaxItemsOne: ` +
+				`MaxItemsOne is set on a field of type string, which is not a list or set`,
+			`Resources["test_widget"].Fields["name"].Default.Config: ` +
+				`Default.Config names unknown config key "zone"`,
+			`Resources["test_widget"].Fields["rules"].Elem.Fields: ` +
+				`Fields and Elem.Fields differ on a nested object; the schema generator reads Elem.Fields but the ` +
+				`runtime reads Fields`,
+			`Resources["test_widget"].Fields["settings"].Fields: ` +
+				`Fields is only read at runtime on a nested object; set Elem.Fields to the same overlays for the ` +
+				`schema generator`,
+			`Resources["test_widget"].Fields["settings"].Fields["missing"]: ` +
+				`field "missing" does not exist in the Terraform schema`,
+			`Resources["test_widget"].Fields["tags"].Elem.Elem: ` +
+				`Elem is set on a field of type string, which has no element type`,
+			`DataSources["test_widget"].Tok: ` +
+				`token "test:getWidget" is not of the form <package>:<module>:<name>`,
+			`DataSources["test_widget"].Fields["kind"].Enum.Values[1]: ` +
+				`enum value "one" is listed more than once`,
+			`DataSources["test_widget"].Fields["kind"].Enum.Tok: ` +
+				`token "other:index:Count" is outside of package "test"`,
+			`DataSources["test_widget"].Fields["name"].Enum.Tok: ` +
+				`token "other:index:Count" is outside of package "test"`,
+			`DataSources["test_widget"].Fields["name"].Enum.Tok: ` +
+				`enum token "other:index:Count" is already used by Resources["test_widget"].Fields["count"].Enum, ` +
+				`which describes a different enum`,
+		}, strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"))
+	})
+}
@@ -52,6 +52,11 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo) *cobra.
 	var debug bool
 	var skipDocs bool
 	var skipExamples bool
+	var docsSourceSpec string
+	var missingDocsError bool
+	var cacheDir string
+	var parallel int
+	var reportPath string
 	cmd := &cobra.Command{
 		Use:   os.Args[0] + " <LANGUAGE>",
 		Args:  cmdutil.SpecificArgs([]string{"language"}),
@@ -124,17 +129,31 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo) *cobra.
 				coverageTracker = newCoverageTracker(prov.Name, prov.Version)
 			}
 
+			var docsSource DocsSource
+			if docsSourceSpec != "" {
+				src, err := parseDocsSource(docsSourceSpec, prov)
+				if err != nil {
+					return err
+				}
+				docsSource = src
+			}
+
 			// Create a generator with the specified settings.
 			g, err := NewGenerator(GeneratorOptions{
-				Package:         pkg,
-				Version:         version,
-				Language:        Language(args[0]),
-				ProviderInfo:    prov,
-				Root:            root,
-				Debug:           debug,
-				SkipDocs:        skipDocs,
-				SkipExamples:    skipExamples,
-				CoverageTracker: coverageTracker,
+				Package:          pkg,
+				Version:          version,
+				Language:         Language(args[0]),
+				ProviderInfo:     prov,
+				Root:             root,
+				Debug:            debug,
+				SkipDocs:         skipDocs,
+				SkipExamples:     skipExamples,
+				CoverageTracker:  coverageTracker,
+				DocsSource:       docsSource,
+				MissingDocsError: missingDocsError,
+				CacheDir:         cacheDir,
+				Parallel:         parallel,
+				ReportPath:       reportPath,
 			})
 			if err != nil {
 				return err
@@ -178,6 +197,18 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo) *cobra.
 		&skipDocs, "skip-docs", false, "Do not convert docs from TF Markdown")
 	cmd.PersistentFlags().BoolVar(
 		&skipExamples, "skip-examples", false, "Do not convert examples from HCL")
+	cmd.PersistentFlags().StringVar(
+		&docsSourceSpec, "docs-source", "",
+		"Read the TF docs from this directory or archive, or from module-cache[@<version>] or go-mod (the default)")
+	cmd.PersistentFlags().BoolVar(
+		&missingDocsError, "missing-docs-error", false, "Fail if docs cannot be found for a resource or data source")
+	cmd.PersistentFlags().StringVar(
+		&cacheDir, "cache-dir", "", "Cache the results of converting examples in this directory across runs")
+	cmd.PersistentFlags().IntVar(
+		&parallel, "parallel", 1, "Process up to this many docs pages or examples concurrently")
+	cmd.PersistentFlags().StringVar(
+		&reportPath, "report", "",
+		"Write a JSON report of the docs and examples of each resource and data source to this file")
 
 	cmd.PersistentFlags().StringVar(
 		&overlaysDir, "overlays", "",
@@ -185,5 +216,8 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo) *cobra.
 	err := cmd.PersistentFlags().MarkHidden("overlays")
 	contract.AssertNoError(err)
 
+	cmd.AddCommand(newLintCmd(pkg, prov))
+	cmd.AddCommand(newCompareSchemasCmd())
+
 	return cmd
 }
@@ -0,0 +1,139 @@
+package tfgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tf2pulumi/convert"
+)
+
+func TestParallelDo(t *testing.T) {
+	for _, parallel := range []int{0, 1, 4, 100} {
+		g := &Generator{parallel: parallel}
+
+		var calls int32
+		counts := make([]int32, 50)
+		g.parallelDo(len(counts), func(i int) {
+			atomic.AddInt32(&calls, 1)
+			atomic.AddInt32(&counts[i], 1)
+		})
+		assert.Equal(t, int32(len(counts)), calls)
+		for i, count := range counts {
+			assert.Equal(t, int32(1), count, "index %d with parallel = %d", i, parallel)
+		}
+	}
+}
+
+func TestConvertExamplesInSchemaIsDeterministic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	newGenerator := func(parallel int) *Generator {
+		g, err := NewGenerator(GeneratorOptions{
+			Package:      "test",
+			Version:      "0.0.1",
+			Language:     Schema,
+			ProviderInfo: testCacheProviderInfo("name"),
+			Root:         afero.NewMemMapFs(),
+			Sink:         diag.DefaultSink(ioutil.Discard, ioutil.Discard, diag.FormatOptions{Color: "never"}),
+			CacheDir:     dir,
+			Parallel:     parallel,
+		})
+		require.NoError(t, err)
+		return g
+	}
+
+	// Seed the example cache so that the examples convert without a plugin host.
+	languages := genLanguageToSlice(Schema)
+	newSpec := func() pschema.PackageSpec {
+		spec := pschema.PackageSpec{Resources: map[string]pschema.ResourceSpec{}}
+		for i := 0; i < 20; i++ {
+			hcl := fmt.Sprintf("resource \"test_widget\" \"w%d\" {}", i)
+			spec.Resources[fmt.Sprintf("test:index/widget%d:Widget%d", i, i)] = pschema.ResourceSpec{
+				ObjectTypeSpec: pschema.ObjectTypeSpec{
+					Description: fmt.Sprintf("Widget %d.\n\n## Example Usage\n\n```hcl\n%s\n```\n", i, hcl),
+					Properties: map[string]pschema.PropertySpec{
+						"name": {Description: fmt.Sprintf("The name of widget %d.", i)},
+					},
+				},
+			}
+		}
+		return spec
+	}
+
+	seed := newGenerator(1)
+	for i := 0; i < 20; i++ {
+		hcl := fmt.Sprintf("resource \"test_widget\" \"w%d\" {}", i)
+		for _, lang := range languages {
+			code := fmt.Sprintf("// widget %d in %s", i, lang)
+			if lang == convert.LanguageGo && i%3 == 0 {
+				require.NoError(t, seed.exampleCache.put(hcl, lang, exampleConversion{Error: "unsupported"}))
+				continue
+			}
+			require.NoError(t, seed.exampleCache.put(hcl, lang, exampleConversion{Code: code}))
+		}
+	}
+
+	serial := newGenerator(1).convertExamplesInSchema(newSpec())
+	parallel := newGenerator(8).convertExamplesInSchema(newSpec())
+	assert.Equal(t, serial, parallel)
+
+	widget := parallel.Resources["test:index/widget3:Widget3"]
+	assert.Contains(t, widget.Description, "// widget 3 in typescript")
+	assert.NotContains(t, widget.Description, "// widget 3 in go")
+	assert.Equal(t, "The name of widget 3.", widget.Properties["name"].Description)
+}
+
+func TestConvertExamplesInSchemaWithoutCache(t *testing.T) {
+	// Without a cache, every example is converted by the worker pool. Run with -race to check that concurrent
+	// conversions do not share mutable state.
+	convertWidgets := func(parallel int) pschema.PackageSpec {
+		g, err := NewGenerator(GeneratorOptions{
+			Package:      "test",
+			Version:      "0.0.1",
+			Language:     Schema,
+			ProviderInfo: testCacheProviderInfo("name"),
+			Root:         afero.NewMemMapFs(),
+			Sink:         diag.DefaultSink(ioutil.Discard, ioutil.Discard, diag.FormatOptions{Color: "never"}),
+			Parallel:     parallel,
+		})
+		require.NoError(t, err)
+
+		// The converter reads the provider's schema from the generator's in-memory provider.
+		pack, err := g.gatherPackage()
+		require.NoError(t, err)
+		spec, err := genPulumiSchema(pack, g.pkg, g.version, g.info)
+		require.NoError(t, err)
+		g.providerShim.schema, err = json.Marshal(spec)
+		require.NoError(t, err)
+
+		widget := spec.Resources["test:index:Widget"]
+		for i := 0; i < 10; i++ {
+			hcl := fmt.Sprintf("resource \"test_widget\" \"w\" {\n  name = \"widget-%d\"\n}", i)
+			widget.Description = fmt.Sprintf("Widget %d.\n\n## Example Usage\n\n```hcl\n%s\n```\n", i, hcl)
+			spec.Resources[fmt.Sprintf("test:index/widget%d:Widget%d", i, i)] = widget
+		}
+		return g.convertExamplesInSchema(spec)
+	}
+
+	serial := convertWidgets(1)
+	parallel := convertWidgets(8)
+	assert.Equal(t, serial, parallel)
+
+	widget := parallel.Resources["test:index/widget3:Widget3"]
+	assert.Contains(t, widget.Description, `const widget = new test.Widget("w", {`)
+	assert.Contains(t, widget.Description, `name: "widget-3",`)
+	assert.Contains(t, widget.Description, `widget = test.Widget("widget", name="widget-3")`)
+	assert.Contains(t, widget.Description, `Name: pulumi.String("widget-3"),`)
+}
@@ -0,0 +1,231 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// docsReport is a machine-readable account of the docs and examples of every resource and data source in a
+// package, written by `tfgen --report`. Entities are keyed by their Terraform names. A nil *docsReport records
+// nothing. The report may be updated concurrently.
+type docsReport struct {
+	m sync.Mutex
+
+	Package     string                   `json:"package"`
+	Version     string                   `json:"version,omitempty"`
+	Resources   map[string]*entityReport `json:"resources"`
+	DataSources map[string]*entityReport `json:"dataSources"`
+	// OtherExamples holds the examples that do not belong to a resource or data source, e.g. those in the docs of
+	// the provider or its types.
+	OtherExamples []*exampleReport `json:"otherExamples,omitempty"`
+
+	examples map[exampleReportKey]*exampleReport
+}
+
+// entityReport describes the docs and examples of a single resource or data source.
+type entityReport struct {
+	// Token is the Pulumi token of the resource or function.
+	Token string `json:"token"`
+	// DocsFound is true if the entity's upstream docs were found.
+	DocsFound bool `json:"docsFound"`
+	// DocsFile is the name of the upstream docs file, if it was found.
+	DocsFile string `json:"docsFile,omitempty"`
+	// ArgumentsMissingDescriptions lists the Terraform names of the arguments that have no description.
+	ArgumentsMissingDescriptions []string `json:"argumentsMissingDescriptions,omitempty"`
+	// AttributesMissingDescriptions lists the Terraform names of the attributes that have no description.
+	AttributesMissingDescriptions []string `json:"attributesMissingDescriptions,omitempty"`
+	// Elided lists the parts of the docs that contained an <elided> reference and were dropped.
+	Elided []string `json:"elided,omitempty"`
+	// HasImport is true if the docs of a resource have an import section. It is omitted for data sources.
+	HasImport *bool `json:"hasImport,omitempty"`
+	// Examples holds the results of converting each of the entity's examples.
+	Examples []*exampleReport `json:"examples,omitempty"`
+}
+
+// exampleReport describes the conversion of a single HCL example to each target language.
+type exampleReport struct {
+	// Path is the path of the example's docs in the Pulumi schema.
+	Path string `json:"path"`
+	// HCL is the text of the example.
+	HCL string `json:"hcl"`
+	// Languages maps each target language to the result of converting the example to it.
+	Languages map[string]*exampleLanguageReport `json:"languages"`
+}
+
+// exampleLanguageReport is the result of converting an example to a single language.
+type exampleLanguageReport struct {
+	Converted   bool                `json:"converted"`
+	Error       string              `json:"error,omitempty"`
+	Panic       string              `json:"panic,omitempty"`
+	Diagnostics []exampleDiagnostic `json:"diagnostics,omitempty"`
+}
+
+type exampleReportKey struct {
+	path, hcl string
+}
+
+func newDocsReport(pkg, version string) *docsReport {
+	return &docsReport{
+		Package:     pkg,
+		Version:     version,
+		Resources:   map[string]*entityReport{},
+		DataSources: map[string]*entityReport{},
+		examples:    map[exampleReportKey]*exampleReport{},
+	}
+}
+
+// entity returns the report for the given entity, creating it if necessary. The caller must hold r.m.
+func (r *docsReport) entity(kind DocKind, rawname string) *entityReport {
+	entities := r.Resources
+	if kind == DataSourceDocs {
+		entities = r.DataSources
+	}
+	e, ok := entities[rawname]
+	if !ok {
+		e = &entityReport{}
+		entities[rawname] = e
+	}
+	return e
+}
+
+// recordDocs records the outcome of looking up and parsing the upstream docs of an entity.
+func (r *docsReport) recordDocs(kind DocKind, rawname, fileName string, found bool, docs entityDocs) {
+	if r == nil {
+		return
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	e := r.entity(kind, rawname)
+	e.DocsFound, e.DocsFile, e.Elided = found, fileName, docs.Elided
+	if kind == ResourceDocs {
+		hasImport := docs.Import != ""
+		e.HasImport = &hasImport
+	}
+}
+
+// recordEntity records the Pulumi token of an entity and the names of its properties that have no description.
+func (r *docsReport) recordEntity(kind DocKind, rawname, token string, argumentsMissingDescriptions,
+	attributesMissingDescriptions []string) {
+	if r == nil {
+		return
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	e := r.entity(kind, rawname)
+	e.Token = token
+	e.ArgumentsMissingDescriptions = argumentsMissingDescriptions
+	e.AttributesMissingDescriptions = attributesMissingDescriptions
+	if kind == ResourceDocs && e.HasImport == nil {
+		hasImport := false
+		e.HasImport = &hasImport
+	}
+}
+
+// recordExample records the result of converting the given example to the given language.
+func (r *docsReport) recordExample(path, hcl, languageName string, result exampleConversion) {
+	if r == nil {
+		return
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	key := exampleReportKey{path: path, hcl: hcl}
+	ex, ok := r.examples[key]
+	if !ok {
+		ex = &exampleReport{Path: path, HCL: hcl, Languages: map[string]*exampleLanguageReport{}}
+		r.examples[key] = ex
+	}
+	ex.Languages[languageName] = &exampleLanguageReport{
+		Converted:   result.Error == "",
+		Error:       result.Error,
+		Panic:       result.Panic,
+		Diagnostics: result.Diagnostics,
+	}
+}
+
+// finish attributes each example to the entity whose docs contain it and sorts the report's lists. Entities that
+// were never gathered, e.g. those whose docs were only read in order to be merged into another entity's, are
+// dropped.
+func (r *docsReport) finish() {
+	entitiesByPath := map[string]*entityReport{}
+	for prefix, entities := range map[string]map[string]*entityReport{
+		"#/resources/": r.Resources,
+		"#/functions/": r.DataSources,
+	} {
+		for rawname, e := range entities {
+			if e.Token == "" {
+				delete(entities, rawname)
+				continue
+			}
+			e.Examples = nil
+			sort.Strings(e.Elided)
+			entitiesByPath[prefix+e.Token] = e
+		}
+	}
+
+	examples := make([]*exampleReport, 0, len(r.examples))
+	for _, ex := range r.examples {
+		examples = append(examples, ex)
+	}
+	sort.Slice(examples, func(i, j int) bool {
+		if examples[i].Path != examples[j].Path {
+			return examples[i].Path < examples[j].Path
+		}
+		return examples[i].HCL < examples[j].HCL
+	})
+
+	r.OtherExamples = nil
+	for _, ex := range examples {
+		if e := entityForPath(entitiesByPath, ex.Path); e != nil {
+			e.Examples = append(e.Examples, ex)
+		} else {
+			r.OtherExamples = append(r.OtherExamples, ex)
+		}
+	}
+}
+
+// entityForPath returns the entity whose schema path is or contains the given path, if any.
+func entityForPath(entitiesByPath map[string]*entityReport, path string) *entityReport {
+	for {
+		if e, ok := entitiesByPath[path]; ok {
+			return e
+		}
+		slash := strings.LastIndex(path, "/")
+		if slash == -1 {
+			return nil
+		}
+		path = path[:slash]
+	}
+}
+
+// write writes the report to the file at the given path as indented JSON.
+func (r *docsReport) write(path string) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.finish()
+	bytes, err := json.MarshalIndent(r, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(bytes, '\n'), 0600)
+}
@@ -0,0 +1,144 @@
+package tfgen
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tf2pulumi/convert"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimschema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+const testReportWidgetMarkdown = "# test_widget\n" +
+	"\n" +
+	"Provides a widget.\n" +
+	"\n" +
+	"## Example Usage\n" +
+	"\n" +
+	"```hcl\n" +
+	"resource \"test_widget\" \"w\" {}\n" +
+	"```\n" +
+	"\n" +
+	"## Argument Reference\n" +
+	"\n" +
+	"* `name` - (Optional) The name of the widget.\n" +
+	"* `size` - (Optional) The size of the widget, as in Terraform.\n" +
+	"\n" +
+	"## Import\n" +
+	"\n" +
+	"Widgets can be imported using their name, e.g.\n" +
+	"\n" +
+	"```\n" +
+	"$ terraform import test_widget.w w\n" +
+	"```\n"
+
+func TestReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	str := (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim()
+	computed := (&shimschema.Schema{Type: shim.TypeString, Computed: true}).Shim()
+	info := tfbridge.ProviderInfo{
+		Name: "test",
+		P: (&shimschema.Provider{
+			Schema: shimschema.SchemaMap{},
+			ResourcesMap: shimschema.ResourceMap{
+				"test_widget": (&shimschema.Resource{Schema: shimschema.SchemaMap{
+					"name":  str,
+					"size":  str,
+					"color": str,
+					"state": computed,
+				}}).Shim(),
+			},
+			DataSourcesMap: shimschema.ResourceMap{
+				"test_gadget": (&shimschema.Resource{Schema: shimschema.SchemaMap{
+					"label": str,
+					"value": computed,
+				}}).Shim(),
+			},
+		}).Shim(),
+		Resources:   map[string]*tfbridge.ResourceInfo{"test_widget": {Tok: "test:index:Widget"}},
+		DataSources: map[string]*tfbridge.DataSourceInfo{"test_gadget": {Tok: "test:index:getGadget"}},
+	}
+
+	repo := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(repo, "docs/resources/widget.html.markdown",
+		[]byte(testReportWidgetMarkdown), 0600))
+
+	reportPath := filepath.Join(dir, "report.json")
+	g, err := NewGenerator(GeneratorOptions{
+		Package:      "test",
+		Version:      "0.0.1",
+		Language:     Schema,
+		ProviderInfo: info,
+		Root:         afero.NewMemMapFs(),
+		Sink:         diag.DefaultSink(ioutil.Discard, ioutil.Discard, diag.FormatOptions{Color: "never"}),
+		DocsSource:   memDocsSource{repo: repo},
+		CacheDir:     filepath.Join(dir, "cache"),
+		ReportPath:   reportPath,
+	})
+	require.NoError(t, err)
+
+	// Seed the example cache so that the example converts without a plugin host.
+	const hcl = "resource \"test_widget\" \"w\" {}"
+	for _, lang := range genLanguageToSlice(Schema) {
+		result := exampleConversion{Code: "// widget in " + lang}
+		if lang == convert.LanguageGo {
+			result = exampleConversion{
+				Error:       "unsupported",
+				Diagnostics: []exampleDiagnostic{{Summary: "unsupported", Detail: "widgets are not supported"}},
+			}
+		}
+		require.NoError(t, g.exampleCache.put(hcl, lang, result))
+	}
+
+	require.NoError(t, g.Generate())
+
+	bytes, err := ioutil.ReadFile(reportPath)
+	require.NoError(t, err)
+	var report docsReport
+	require.NoError(t, json.Unmarshal(bytes, &report))
+
+	assert.Equal(t, "test", report.Package)
+	assert.Empty(t, report.OtherExamples)
+
+	widget := report.Resources["test_widget"]
+	require.NotNil(t, widget)
+	assert.Equal(t, "test:index:Widget", widget.Token)
+	assert.True(t, widget.DocsFound)
+	assert.Equal(t, "widget.html.markdown", widget.DocsFile)
+	assert.Equal(t, []string{"color", "size"}, widget.ArgumentsMissingDescriptions)
+	assert.Equal(t, []string{"state"}, widget.AttributesMissingDescriptions)
+	assert.Equal(t, []string{"argument size"}, widget.Elided)
+	require.NotNil(t, widget.HasImport)
+	assert.True(t, *widget.HasImport)
+
+	require.Len(t, widget.Examples, 1)
+	example := widget.Examples[0]
+	assert.Equal(t, "#/resources/test:index:Widget", example.Path)
+	assert.Equal(t, hcl, example.HCL)
+	assert.Equal(t, &exampleLanguageReport{Converted: true}, example.Languages[convert.LanguageTypescript])
+	assert.Equal(t, &exampleLanguageReport{
+		Error:       "unsupported",
+		Diagnostics: []exampleDiagnostic{{Summary: "unsupported", Detail: "widgets are not supported"}},
+	}, example.Languages[convert.LanguageGo])
+
+	gadget := report.DataSources["test_gadget"]
+	require.NotNil(t, gadget)
+	assert.Equal(t, "test:index:getGadget", gadget.Token)
+	assert.False(t, gadget.DocsFound)
+	assert.Equal(t, []string{"label"}, gadget.ArgumentsMissingDescriptions)
+	assert.Equal(t, []string{"value"}, gadget.AttributesMissingDescriptions)
+	assert.Nil(t, gadget.HasImport)
+	assert.Empty(t, gadget.Examples)
+}
@@ -0,0 +1,492 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/spf13/cobra"
+)
+
+// SchemaChangeKind identifies the part of a Pulumi package schema that a SchemaChange applies to.
+type SchemaChangeKind string
+
+// The kinds of schema changes.
+const (
+	ConfigChange   SchemaChangeKind = "config"
+	ResourceChange SchemaChangeKind = "resource"
+	FunctionChange SchemaChangeKind = "function"
+	TypeChange     SchemaChangeKind = "type"
+)
+
+// SchemaChange is a single difference between two versions of a Pulumi package schema.
+type SchemaChange struct {
+	// Kind is the part of the schema that changed.
+	Kind SchemaChangeKind `json:"kind"`
+	// Token is the token of the resource, function or type that changed. It is empty for config changes.
+	Token string `json:"token,omitempty"`
+	// Property is the name of the property that changed, if any.
+	Property string `json:"property,omitempty"`
+	// Breaking is true if programs written against the old schema may fail to compile or run against the new one.
+	Breaking bool `json:"breaking"`
+	// Message describes the change.
+	Message string `json:"message"`
+}
+
+// SchemaDiff is the result of comparing two versions of a Pulumi package schema.
+type SchemaDiff struct {
+	// Breaking is the number of breaking changes.
+	Breaking int `json:"breaking"`
+	// NonBreaking is the number of non-breaking changes.
+	NonBreaking int `json:"nonBreaking"`
+	// Changes lists every change, ordered by kind, token and property.
+	Changes []SchemaChange `json:"changes"`
+}
+
+// HasBreakingChanges returns true if any of the changes is breaking.
+func (d *SchemaDiff) HasBreakingChanges() bool {
+	return d.Breaking > 0
+}
+
+// WriteSummary writes a human-readable summary of the changes to w, grouped by the resource, function, type or config
+// that they apply to.
+func (d *SchemaDiff) WriteSummary(w io.Writer) error {
+	var sb strings.Builder
+	var group string
+	for _, c := range d.Changes {
+		if g := schemaChangeGroup(c); g != group {
+			if group != "" {
+				sb.WriteString("\n")
+			}
+			fmt.Fprintf(&sb, "%s:\n", g)
+			group = g
+		}
+
+		severity := "non-breaking"
+		if c.Breaking {
+			severity = "BREAKING"
+		}
+		if c.Property != "" {
+			fmt.Fprintf(&sb, "  [%s] %s: %s\n", severity, c.Property, c.Message)
+		} else {
+			fmt.Fprintf(&sb, "  [%s] %s\n", severity, c.Message)
+		}
+	}
+	if len(d.Changes) != 0 {
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, "%d breaking change(s), %d non-breaking change(s)\n", d.Breaking, d.NonBreaking)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func schemaChangeGroup(c SchemaChange) string {
+	if c.Kind == ConfigChange {
+		return "config"
+	}
+	return fmt.Sprintf("%s %s", c.Kind, c.Token)
+}
+
+// CompareSchemas compares two versions of a Pulumi package schema and classifies every difference between them as
+// breaking or non-breaking. Changes are reported for config variables, resources, functions and types. A change is
+// breaking if programs written against the old schema may fail to compile or run against the new one, e.g. a removed
+// resource or property, a new required input, an optional input that became required, or a changed property type
+// (which includes a property that flipped between a list and a single value because of MaxItemsOne).
+func CompareSchemas(old, new pschema.PackageSpec) *SchemaDiff {
+	d := &schemaDiffer{}
+	d.diffConfig(old.Config, new.Config)
+	d.diffResources(old.Resources, new.Resources)
+	d.diffFunctions(old.Functions, new.Functions)
+	d.diffTypes(old.Types, new.Types)
+
+	kindOrder := map[SchemaChangeKind]int{ConfigChange: 0, ResourceChange: 1, FunctionChange: 2, TypeChange: 3}
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		ci, cj := d.changes[i], d.changes[j]
+		if ci.Kind != cj.Kind {
+			return kindOrder[ci.Kind] < kindOrder[cj.Kind]
+		}
+		return ci.Token < cj.Token
+	})
+
+	diff := &SchemaDiff{Changes: d.changes}
+	if diff.Changes == nil {
+		diff.Changes = []SchemaChange{}
+	}
+	for _, c := range diff.Changes {
+		if c.Breaking {
+			diff.Breaking++
+		} else {
+			diff.NonBreaking++
+		}
+	}
+	return diff
+}
+
+// schemaDiffer accumulates the changes between two schemas.
+type schemaDiffer struct {
+	changes []SchemaChange
+}
+
+func (d *schemaDiffer) add(kind SchemaChangeKind, token, property string, breaking bool, format string,
+	args ...interface{}) {
+
+	d.changes = append(d.changes, SchemaChange{
+		Kind:     kind,
+		Token:    token,
+		Property: property,
+		Breaking: breaking,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *schemaDiffer) diffConfig(old, new pschema.ConfigSpec) {
+	d.diffProperties(ConfigChange, "", "config variable", old.Variables, new.Variables, old.Required, new.Required,
+		true)
+}
+
+func (d *schemaDiffer) diffResources(old, new map[string]pschema.ResourceSpec) {
+	// renamed records the new tokens that have been matched to old ones through their aliases.
+	renamed := map[string]bool{}
+	for _, tok := range sortedSpecKeys(old) {
+		oldRes := old[tok]
+		newRes, ok := new[tok]
+		if !ok {
+			if newTok := findResourceAlias(new, tok); newTok != "" {
+				renamed[newTok] = true
+				d.add(ResourceChange, tok, "", true, "resource was renamed to %q", newTok)
+			} else {
+				d.add(ResourceChange, tok, "", true, "resource was removed")
+			}
+			continue
+		}
+
+		d.diffProperties(ResourceChange, tok, "input", oldRes.InputProperties, newRes.InputProperties,
+			oldRes.RequiredInputs, newRes.RequiredInputs, true)
+		d.diffProperties(ResourceChange, tok, "output", oldRes.Properties, newRes.Properties,
+			oldRes.Required, newRes.Required, false)
+	}
+	for _, tok := range sortedSpecKeys(new) {
+		if _, ok := old[tok]; !ok && !renamed[tok] {
+			d.add(ResourceChange, tok, "", false, "resource was added")
+		}
+	}
+}
+
+// findResourceAlias returns the token of the resource in resources that has an alias to the given token, if any.
+func findResourceAlias(resources map[string]pschema.ResourceSpec, tok string) string {
+	for _, newTok := range sortedSpecKeys(resources) {
+		for _, alias := range resources[newTok].Aliases {
+			if alias.Type != nil && *alias.Type == tok {
+				return newTok
+			}
+		}
+	}
+	return ""
+}
+
+func (d *schemaDiffer) diffFunctions(old, new map[string]pschema.FunctionSpec) {
+	for _, tok := range sortedSpecKeys(old) {
+		oldFn := old[tok]
+		newFn, ok := new[tok]
+		if !ok {
+			d.add(FunctionChange, tok, "", true, "function was removed")
+			continue
+		}
+
+		oldInputs, newInputs := objectTypeOrEmpty(oldFn.Inputs), objectTypeOrEmpty(newFn.Inputs)
+		d.diffProperties(FunctionChange, tok, "input", oldInputs.Properties, newInputs.Properties,
+			oldInputs.Required, newInputs.Required, true)
+
+		oldOutputs, newOutputs := objectTypeOrEmpty(oldFn.Outputs), objectTypeOrEmpty(newFn.Outputs)
+		d.diffProperties(FunctionChange, tok, "output", oldOutputs.Properties, newOutputs.Properties,
+			oldOutputs.Required, newOutputs.Required, false)
+	}
+	for _, tok := range sortedSpecKeys(new) {
+		if _, ok := old[tok]; !ok {
+			d.add(FunctionChange, tok, "", false, "function was added")
+		}
+	}
+}
+
+func (d *schemaDiffer) diffTypes(old, new map[string]pschema.ComplexTypeSpec) {
+	for _, tok := range sortedSpecKeys(old) {
+		oldType := old[tok]
+		newType, ok := new[tok]
+		if !ok {
+			d.add(TypeChange, tok, "", true, "type was removed")
+			continue
+		}
+
+		oldIsEnum, newIsEnum := len(oldType.Enum) != 0, len(newType.Enum) != 0
+		switch {
+		case oldIsEnum && newIsEnum:
+			d.diffEnum(tok, oldType, newType)
+		case oldIsEnum != newIsEnum:
+			d.add(TypeChange, tok, "", true, "type changed from %s to %s", complexTypeKind(oldType),
+				complexTypeKind(newType))
+		default:
+			// Object types are used as both inputs and outputs, so they are held to the stricter rules for inputs.
+			d.diffProperties(TypeChange, tok, "property", oldType.Properties, newType.Properties,
+				oldType.Required, newType.Required, true)
+		}
+	}
+	for _, tok := range sortedSpecKeys(new) {
+		if _, ok := old[tok]; !ok {
+			d.add(TypeChange, tok, "", false, "type was added")
+		}
+	}
+}
+
+func (d *schemaDiffer) diffEnum(tok string, old, new pschema.ComplexTypeSpec) {
+	if old.Type != new.Type {
+		d.add(TypeChange, tok, "", true, "enum type changed from %s to %s", old.Type, new.Type)
+	}
+
+	enumValues := func(values []pschema.EnumValueSpec) map[string]bool {
+		m := map[string]bool{}
+		for _, v := range values {
+			m[fmt.Sprintf("%v", v.Value)] = true
+		}
+		return m
+	}
+	oldValues, newValues := enumValues(old.Enum), enumValues(new.Enum)
+	for _, v := range old.Enum {
+		if key := fmt.Sprintf("%v", v.Value); !newValues[key] {
+			d.add(TypeChange, tok, "", true, "enum value %q was removed", key)
+		}
+	}
+	for _, v := range new.Enum {
+		if key := fmt.Sprintf("%v", v.Value); !oldValues[key] {
+			d.add(TypeChange, tok, "", false, "enum value %q was added", key)
+		}
+	}
+}
+
+// diffProperties compares two sets of properties. noun describes the properties in messages. If isInput is true, the
+// properties are supplied by the user, so new or newly-required properties are breaking; otherwise they are returned
+// to the user, so properties that are no longer guaranteed to be set are breaking.
+func (d *schemaDiffer) diffProperties(kind SchemaChangeKind, tok, noun string, old, new map[string]pschema.PropertySpec,
+	oldRequired, newRequired []string, isInput bool) {
+
+	oldReq, newReq := stringSet(oldRequired), stringSet(newRequired)
+
+	names := map[string]bool{}
+	for name := range old {
+		names[name] = true
+	}
+	for name := range new {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		oldProp, inOld := old[name]
+		newProp, inNew := new[name]
+		switch {
+		case !inNew:
+			d.add(kind, tok, name, true, "%s was removed", noun)
+		case !inOld:
+			switch {
+			case !isInput:
+				d.add(kind, tok, name, false, "%s was added", noun)
+			case newReq[name]:
+				d.add(kind, tok, name, true, "required %s was added", noun)
+			default:
+				d.add(kind, tok, name, false, "optional %s was added", noun)
+			}
+		default:
+			if msg, changed, breaking := diffTypeSpecs(oldProp.TypeSpec, newProp.TypeSpec, isInput); changed {
+				d.add(kind, tok, name, breaking, "%s %s", noun, msg)
+			}
+			switch {
+			case !oldReq[name] && newReq[name]:
+				if isInput {
+					d.add(kind, tok, name, true, "%s changed from optional to required", noun)
+				} else {
+					d.add(kind, tok, name, false, "%s is now always set", noun)
+				}
+			case oldReq[name] && !newReq[name]:
+				if isInput {
+					d.add(kind, tok, name, false, "%s changed from required to optional", noun)
+				} else {
+					d.add(kind, tok, name, true, "%s is no longer always set", noun)
+				}
+			}
+		}
+	}
+}
+
+// diffTypeSpecs compares two property types and describes the change between them, if any, and whether it is breaking.
+// If isInput is true, the property is supplied by the user, so widening its type to a union that still contains the
+// old type, as is done when an enum is introduced, is not breaking.
+func diffTypeSpecs(old, new pschema.TypeSpec, isInput bool) (string, bool, bool) {
+	oldType, newType := typeSpecString(old), typeSpecString(new)
+	if oldType == newType {
+		return "", false, false
+	}
+
+	// Flipping MaxItemsOne on a Terraform block turns a list into its element or vice versa.
+	switch {
+	case old.Type == "array" && old.Items != nil && typeSpecString(*old.Items) == newType:
+		return fmt.Sprintf("changed from a list of %s to a single value (MaxItemsOne was set)", newType), true, true
+	case new.Type == "array" && new.Items != nil && typeSpecString(*new.Items) == oldType:
+		return fmt.Sprintf("changed from a single value to a list of %s (MaxItemsOne was unset)", oldType), true, true
+	}
+
+	if isInput {
+		for _, t := range new.OneOf {
+			if typeSpecString(t) == oldType {
+				return fmt.Sprintf("was widened from %s to %s", oldType, newType), true, false
+			}
+		}
+	}
+	return fmt.Sprintf("changed type from %s to %s", oldType, newType), true, true
+}
+
+// typeSpecString renders a property type in a compact, comparable form, e.g. `array<string>` or
+// `map<aws:s3/BucketGrant:BucketGrant>`.
+func typeSpecString(t pschema.TypeSpec) string {
+	switch {
+	case t.Ref != "":
+		return strings.TrimPrefix(t.Ref, "#/types/")
+	case len(t.OneOf) != 0:
+		elements := make([]string, len(t.OneOf))
+		for i, e := range t.OneOf {
+			elements[i] = typeSpecString(e)
+		}
+		return fmt.Sprintf("union<%s>", strings.Join(elements, ", "))
+	case t.Type == "array" && t.Items != nil:
+		return fmt.Sprintf("array<%s>", typeSpecString(*t.Items))
+	case t.Type == "object" && t.AdditionalProperties != nil:
+		return fmt.Sprintf("map<%s>", typeSpecString(*t.AdditionalProperties))
+	default:
+		return t.Type
+	}
+}
+
+func complexTypeKind(t pschema.ComplexTypeSpec) string {
+	if len(t.Enum) != 0 {
+		return "an enum"
+	}
+	return "an object"
+}
+
+func objectTypeOrEmpty(t *pschema.ObjectTypeSpec) pschema.ObjectTypeSpec {
+	if t == nil {
+		return pschema.ObjectTypeSpec{}
+	}
+	return *t
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+func sortedSpecKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]pschema.ResourceSpec:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]pschema.FunctionSpec:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]pschema.ComplexTypeSpec:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	default:
+		contract.Failf("unexpected map type %T", m)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func readPackageSpec(path string) (pschema.PackageSpec, error) {
+	var spec pschema.PackageSpec
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return spec, err
+	}
+	if err = json.Unmarshal(bytes, &spec); err != nil {
+		return spec, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+func newCompareSchemasCmd() *cobra.Command {
+	var jsonReport string
+	cmd := &cobra.Command{
+		Use:   "compare-schemas <old-schema> <new-schema>",
+		Args:  cmdutil.ExactArgs(2),
+		Short: "Report the breaking changes between two versions of the provider's Pulumi schema",
+		Long: "Report the breaking changes between two versions of the provider's Pulumi schema.\n" +
+			"\n" +
+			"Every difference between the two schema.json files is classified as breaking or non-breaking\n" +
+			"and printed, grouped by resource, function, type and config. Removed resources and properties,\n" +
+			"new required inputs, inputs that became required and changed property types (including\n" +
+			"MaxItemsOne flips) are breaking. The command exits with a non-zero status if any breaking\n" +
+			"changes are found.\n",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			old, err := readPackageSpec(args[0])
+			if err != nil {
+				return err
+			}
+			new, err := readPackageSpec(args[1])
+			if err != nil {
+				return err
+			}
+
+			diff := CompareSchemas(old, new)
+			if jsonReport != "" {
+				bytes, err := json.MarshalIndent(diff, "", "    ")
+				if err != nil {
+					return err
+				}
+				if err = ioutil.WriteFile(jsonReport, append(bytes, '\n'), 0600); err != nil {
+					return err
+				}
+			}
+			if err = diff.WriteSummary(cmd.OutOrStdout()); err != nil {
+				return err
+			}
+			if diff.HasBreakingChanges() {
+				return fmt.Errorf("found %d breaking change(s)", diff.Breaking)
+			}
+			return nil
+		}),
+	}
+	cmd.Flags().StringVar(&jsonReport, "json", "", "write a machine-readable report of the changes to the given file")
+	return cmd
+}
@@ -0,0 +1,164 @@
+package tfgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareSchemas(t *testing.T) {
+	str := pschema.PropertySpec{TypeSpec: pschema.TypeSpec{Type: "string"}}
+	num := pschema.PropertySpec{TypeSpec: pschema.TypeSpec{Type: "integer"}}
+	ref := pschema.PropertySpec{TypeSpec: pschema.TypeSpec{Ref: "#/types/test:index/Block:Block"}}
+	refs := pschema.PropertySpec{TypeSpec: pschema.TypeSpec{
+		Type:  "array",
+		Items: &pschema.TypeSpec{Ref: "#/types/test:index/Block:Block"},
+	}}
+	color := pschema.PropertySpec{TypeSpec: pschema.TypeSpec{
+		Type:  "string",
+		OneOf: []pschema.TypeSpec{{Type: "string"}, {Type: "string", Ref: "#/types/test:index/Color:Color"}},
+	}}
+	oldTok := "test:index/old:Old"
+
+	old := pschema.PackageSpec{
+		Config: pschema.ConfigSpec{Variables: map[string]pschema.PropertySpec{"region": str, "zone": str}},
+		Resources: map[string]pschema.ResourceSpec{
+			"test:index/widget:Widget": {
+				ObjectTypeSpec: pschema.ObjectTypeSpec{
+					Properties: map[string]pschema.PropertySpec{
+						"name": str, "count": num, "block": refs, "color": str,
+					},
+					Required: []string{"name", "count"},
+				},
+				InputProperties: map[string]pschema.PropertySpec{
+					"name": str, "count": num, "block": refs, "color": str,
+				},
+				RequiredInputs: []string{"name"},
+			},
+			oldTok:                     {},
+			"test:index/gone:Gone":     {},
+			"test:index/gadget:Gadget": {},
+		},
+		Functions: map[string]pschema.FunctionSpec{
+			"test:index/getWidget:getWidget": {
+				Inputs: &pschema.ObjectTypeSpec{Properties: map[string]pschema.PropertySpec{"name": str}},
+			},
+		},
+		Types: map[string]pschema.ComplexTypeSpec{
+			"test:index/Block:Block": {ObjectTypeSpec: pschema.ObjectTypeSpec{
+				Type:       "object",
+				Properties: map[string]pschema.PropertySpec{"inner": str},
+			}},
+			"test:index/Color:Color": {
+				ObjectTypeSpec: pschema.ObjectTypeSpec{Type: "string"},
+				Enum:           []pschema.EnumValueSpec{{Value: "red"}, {Value: "blue"}},
+			},
+		},
+	}
+	new := pschema.PackageSpec{
+		Config: pschema.ConfigSpec{
+			Variables: map[string]pschema.PropertySpec{"region": str, "profile": str},
+			Required:  []string{"region"},
+		},
+		Resources: map[string]pschema.ResourceSpec{
+			"test:index/widget:Widget": {
+				ObjectTypeSpec: pschema.ObjectTypeSpec{
+					Properties: map[string]pschema.PropertySpec{
+						"name": str, "count": str, "block": ref, "color": color,
+					},
+					Required: []string{"name"},
+				},
+				InputProperties: map[string]pschema.PropertySpec{
+					"name": str, "count": str, "block": ref, "color": color, "size": num, "tags": str,
+				},
+				RequiredInputs: []string{"size"},
+			},
+			"test:index/new:New":       {Aliases: []pschema.AliasSpec{{Type: &oldTok}}},
+			"test:index/gadget:Gadget": {},
+		},
+		Functions: map[string]pschema.FunctionSpec{
+			"test:index/getGadget:getGadget": {},
+		},
+		Types: map[string]pschema.ComplexTypeSpec{
+			"test:index/Block:Block": {ObjectTypeSpec: pschema.ObjectTypeSpec{
+				Type:       "object",
+				Properties: map[string]pschema.PropertySpec{"inner": str, "outer": str},
+			}},
+			"test:index/Color:Color": {
+				ObjectTypeSpec: pschema.ObjectTypeSpec{Type: "string"},
+				Enum:           []pschema.EnumValueSpec{{Value: "red"}, {Value: "green"}},
+			},
+		},
+	}
+
+	diff := CompareSchemas(old, new)
+	assert.True(t, diff.HasBreakingChanges())
+	assert.Equal(t, 13, diff.Breaking)
+	assert.Equal(t, 7, diff.NonBreaking)
+
+	var buf bytes.Buffer
+	assert.NoError(t, diff.WriteSummary(&buf))
+	assert.Equal(t, `config:
+  [non-breaking] profile: optional config variable was added
+  [BREAKING] region: config variable changed from optional to required
+  [BREAKING] zone: config variable was removed
+
+resource test:index/gone:Gone:
+  [BREAKING] resource was removed
+
+resource test:index/old:Old:
+  [BREAKING] resource was renamed to "test:index/new:New"
+
+resource test:index/widget:Widget:
+  [BREAKING] block: input changed from a list of test:index/Block:Block to a single value (MaxItemsOne was set)
+  [non-breaking] color: input was widened from string to union<string, test:index/Color:Color>
+  [BREAKING] count: input changed type from integer to string
+  [non-breaking] name: input changed from required to optional
+  [BREAKING] size: required input was added
+  [non-breaking] tags: optional input was added
+  [BREAKING] block: output changed from a list of test:index/Block:Block to a single value (MaxItemsOne was set)
+  [BREAKING] color: output changed type from string to union<string, test:index/Color:Color>
+  [BREAKING] count: output changed type from integer to string
+  [BREAKING] count: output is no longer always set
+
+function test:index/getGadget:getGadget:
+  [non-breaking] function was added
+
+function test:index/getWidget:getWidget:
+  [BREAKING] function was removed
+
+type test:index/Block:Block:
+  [non-breaking] outer: optional property was added
+
+type test:index/Color:Color:
+  [BREAKING] enum value "blue" was removed
+  [non-breaking] enum value "green" was added
+
+13 breaking change(s), 7 non-breaking change(s)
+`, buf.String())
+
+	data, err := json.Marshal(diff.Changes[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"kind": "config",
+		"property": "profile",
+		"breaking": false,
+		"message": "optional config variable was added"
+	}`, string(data))
+}
+
+func TestCompareSchemasUnchanged(t *testing.T) {
+	spec := pschema.PackageSpec{
+		Resources: map[string]pschema.ResourceSpec{"test:index/widget:Widget": {}},
+	}
+	diff := CompareSchemas(spec, spec)
+	assert.False(t, diff.HasBreakingChanges())
+	assert.Empty(t, diff.Changes)
+
+	var buf bytes.Buffer
+	assert.NoError(t, diff.WriteSummary(&buf))
+	assert.Equal(t, "0 breaking change(s), 0 non-breaking change(s)\n", buf.String())
+}
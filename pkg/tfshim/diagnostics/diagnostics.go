@@ -0,0 +1,89 @@
+package diagnostics
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-multierror"
+)
+
+// Severity is the severity of a diagnostic.
+type Severity int
+
+const (
+	// Error diagnostics cause the operation that reported them to fail.
+	Error Severity = iota
+	// Warning diagnostics are reported to the user, but do not cause the operation that reported them to fail.
+	Warning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Diagnostic is a single diagnostic reported by a shim. AttributePath, if non-empty, identifies the attribute that the
+// diagnostic pertains to. Error diagnostics are returned to callers as errors of type *Diagnostic.
+type Diagnostic struct {
+	Severity      Severity
+	Summary       string
+	Detail        string
+	AttributePath cty.Path
+}
+
+func (d Diagnostic) Error() string {
+	if d.Detail != "" {
+		return fmt.Sprintf("%s: %s", d.Summary, d.Detail)
+	}
+	return d.Summary
+}
+
+// Diagnostics is a list of diagnostics.
+type Diagnostics []Diagnostic
+
+// HasErrors returns true if any of the diagnostics is an error.
+func (ds Diagnostics) HasErrors() bool {
+	for _, d := range ds {
+		if d.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// Split separates the diagnostics into warnings and errors. Diagnostics with an unknown severity are dropped.
+func (ds Diagnostics) Split() (Diagnostics, []error) {
+	var warnings Diagnostics
+	var errors []error
+	for _, d := range ds {
+		switch d.Severity {
+		case Error:
+			d := d
+			errors = append(errors, &d)
+		case Warning:
+			warnings = append(warnings, d)
+		}
+	}
+	return warnings, errors
+}
+
+// Err returns the error diagnostics as a (possibly multi-) error, or nil if there are none.
+func (ds Diagnostics) Err() error {
+	_, errors := ds.Split()
+	if len(errors) == 0 {
+		return nil
+	}
+	return multierror.Append(nil, errors...)
+}
+
+// WarningsAndErr separates the diagnostics into warnings and a (possibly multi-) error.
+func (ds Diagnostics) WarningsAndErr() (Diagnostics, error) {
+	warnings, _ := ds.Split()
+	return warnings, ds.Err()
+}
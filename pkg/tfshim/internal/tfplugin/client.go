@@ -0,0 +1,141 @@
+package tfplugin
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+)
+
+// Client is the protocol-independent view of a Terraform provider plugin. Each protocol version adapts its generated
+// gRPC client to this interface. Dynamic values are msgpack-encoded and private state is passed through as-is.
+type Client interface {
+	GetProviderSchema(ctx context.Context) (*ProviderSchema, error)
+	ValidateProviderConfig(ctx context.Context, config []byte) (diagnostics.Diagnostics, error)
+	ValidateResourceConfig(ctx context.Context, typeName string, config []byte) (diagnostics.Diagnostics, error)
+	ValidateDataResourceConfig(ctx context.Context, typeName string, config []byte) (diagnostics.Diagnostics, error)
+	ConfigureProvider(ctx context.Context, terraformVersion string, config []byte) (diagnostics.Diagnostics, error)
+	UpgradeResourceState(ctx context.Context, typeName string, version int64,
+		rawStateJSON []byte) ([]byte, diagnostics.Diagnostics, error)
+	ImportResourceState(ctx context.Context, typeName, id string) ([]ImportedResource, error)
+	PlanResourceChange(ctx context.Context, req *PlanResourceChangeRequest) (*PlanResourceChangeResponse, error)
+	ApplyResourceChange(ctx context.Context, req *ApplyResourceChangeRequest) (*ApplyResourceChangeResponse, error)
+	ReadResource(ctx context.Context, req *ReadResourceRequest) (*ReadResourceResponse, error)
+	ReadDataSource(ctx context.Context, req *ReadDataSourceRequest) (*ReadDataSourceResponse, error)
+	StopProvider(ctx context.Context) error
+}
+
+// ProviderSchema is the schema of a provider, its resources and its data sources.
+type ProviderSchema struct {
+	Provider          *Schema
+	ProviderMeta      *Schema
+	ResourceSchemas   map[string]*Schema
+	DataSourceSchemas map[string]*Schema
+}
+
+// Schema is a versioned block schema.
+type Schema struct {
+	Version int64
+	Block   *Block
+}
+
+// Block is the schema of a configuration block.
+type Block struct {
+	Attributes  []*Attribute
+	BlockTypes  []*NestedBlock
+	Description string
+	Deprecated  bool
+}
+
+// Attribute is the schema of an attribute. An attribute either has a JSON-encoded cty type or a nested object type.
+type Attribute struct {
+	Name        string
+	Type        []byte
+	NestedType  *Object
+	Description string
+	Required    bool
+	Optional    bool
+	Computed    bool
+	Sensitive   bool
+	Deprecated  bool
+}
+
+// Object is the type of a nested attribute.
+type Object struct {
+	Attributes []*Attribute
+	Nesting    NestingMode
+	MinItems   int64
+	MaxItems   int64
+}
+
+// NestedBlock is the schema of a nested block.
+type NestedBlock struct {
+	TypeName string
+	Block    *Block
+	Nesting  NestingMode
+	MinItems int64
+	MaxItems int64
+}
+
+// ImportedResource is a resource returned by ImportResourceState.
+type ImportedResource struct {
+	TypeName string
+	State    []byte
+	Private  []byte
+}
+
+type PlanResourceChangeRequest struct {
+	TypeName         string
+	PriorState       []byte
+	ProposedNewState []byte
+	Config           []byte
+	PriorPrivate     []byte
+	ProviderMeta     []byte
+}
+
+type PlanResourceChangeResponse struct {
+	PlannedState    []byte
+	RequiresReplace []cty.Path
+	PlannedPrivate  []byte
+	Diagnostics     diagnostics.Diagnostics
+}
+
+type ApplyResourceChangeRequest struct {
+	TypeName       string
+	PriorState     []byte
+	PlannedState   []byte
+	Config         []byte
+	PlannedPrivate []byte
+	ProviderMeta   []byte
+}
+
+type ApplyResourceChangeResponse struct {
+	NewState    []byte
+	Private     []byte
+	Diagnostics diagnostics.Diagnostics
+}
+
+type ReadResourceRequest struct {
+	TypeName     string
+	CurrentState []byte
+	Private      []byte
+	ProviderMeta []byte
+}
+
+type ReadResourceResponse struct {
+	NewState    []byte
+	Private     []byte
+	Diagnostics diagnostics.Diagnostics
+}
+
+type ReadDataSourceRequest struct {
+	TypeName     string
+	Config       []byte
+	ProviderMeta []byte
+}
+
+type ReadDataSourceResponse struct {
+	State       []byte
+	Diagnostics diagnostics.Diagnostics
+}
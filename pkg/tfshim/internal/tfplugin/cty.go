@@ -1,7 +1,8 @@
-package tfplugin5
+package tfplugin
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 
 	"github.com/hashicorp/go-cty/cty"
@@ -9,7 +10,9 @@ import (
 )
 
 // ctyToGo converts a cty.Value to a plain Go value with the notable exception of sets, which are left as-is. Sets can
-// be converted to plain values by calling provider.IsSet ala tfbridge. Capsule types are not supported.
+// be converted to plain values by calling provider.IsSet ala tfbridge. Capsule types are not supported. Integral
+// numbers are converted to int64 values, or to *big.Int values if they do not fit in an int64, so that they do not
+// lose precision; all other numbers are converted to float64 values.
 func ctyToGo(val cty.Value) (interface{}, error) {
 	switch {
 	case val.IsNull():
@@ -23,9 +26,7 @@ func ctyToGo(val cty.Value) (interface{}, error) {
 		case cty.Bool:
 			return val.True(), nil
 		case cty.Number:
-			// Convert number values to floats.
-			float, _ := val.AsBigFloat().Float64()
-			return float, nil
+			return numberToGo(val.AsBigFloat()), nil
 		case cty.String:
 			return val.AsString(), nil
 		}
@@ -71,18 +72,46 @@ func ctyToGo(val cty.Value) (interface{}, error) {
 	return nil, fmt.Errorf("unsupported cty type %v", val.Type().FriendlyName())
 }
 
-// goToCty converts a reflect.Value to a cty.Value of the given type. Capsule types are not supported.
-// Only a limited set of Go values are supported: bools, ints/uints/floats, strings, arrays/slices, and maps with
-// string-typed keys. Structs are not supported.
+// numberToGo converts a number to an int64 if it is an integer that fits in 64 bits, to a *big.Int if it is a larger
+// integer, and to a float64 otherwise.
+func numberToGo(n *big.Float) interface{} {
+	if n.IsInt() {
+		if i, accuracy := n.Int64(); accuracy == big.Exact {
+			return i
+		}
+		i, _ := n.Int(nil)
+		return i
+	}
+	f, _ := n.Float64()
+	return f
+}
+
+// goToCty converts a Go value to a cty.Value of the given type. Capsule types are not supported. See reflectToCty for
+// the set of supported Go values.
 func goToCty(v interface{}, ty cty.Type) (cty.Value, error) {
 	return reflectToCty(reflect.ValueOf(v), ty)
 }
 
-var ctyValueType = reflect.TypeOf((*cty.Value)(nil)).Elem()
+// CtyToGo converts a cty.Value to a plain Go value. See ctyToGo.
+func CtyToGo(val cty.Value) (interface{}, error) {
+	return ctyToGo(val)
+}
+
+// GoToCty converts a Go value to a cty.Value of the given type. See goToCty.
+func GoToCty(v interface{}, ty cty.Type) (cty.Value, error) {
+	return goToCty(v, ty)
+}
+
+var (
+	ctyValueType = reflect.TypeOf((*cty.Value)(nil)).Elem()
+	bigIntType   = reflect.TypeOf((*big.Int)(nil))
+	bigFloatType = reflect.TypeOf((*big.Float)(nil))
+)
 
 // reflectToCty converts a reflect.Value to a cty.Value of the given type. Capsule types are not supported.
-// Only a limited set of Go values are supported: bools, ints/uints/floats, strings, arrays/slices, and maps with
-// string-typed keys. Structs are not supported.
+// Only a limited set of Go values are supported: bools, ints/uints/floats, *big.Ints/*big.Floats, strings,
+// arrays/slices, and maps with string-typed keys. Structs are not supported. Strings are accepted for numbers so
+// that values that cannot be represented exactly as a float64 can be passed in their decimal form.
 func reflectToCty(v reflect.Value, ty cty.Type) (cty.Value, error) {
 	if v.Type() == ctyValueType {
 		if !v.CanInterface() {
@@ -91,10 +120,28 @@ func reflectToCty(v reflect.Value, ty cty.Type) (cty.Value, error) {
 		return v.Interface().(cty.Value), nil
 	}
 
+	if ty == cty.DynamicPseudoType {
+		return reflectToDynamicCty(v)
+	}
+
 	if !v.IsValid() {
 		return cty.NullVal(ty), nil
 	}
 
+	switch v.Type() {
+	case bigIntType, bigFloatType:
+		if v.IsNil() {
+			return cty.NullVal(ty), nil
+		}
+		if ty != cty.Number {
+			return cty.NilVal, fmt.Errorf("can't convert Go %v to %v", v.Type(), ty.FriendlyName())
+		}
+		if i, ok := v.Interface().(*big.Int); ok {
+			return cty.NumberVal(new(big.Float).SetInt(i)), nil
+		}
+		return cty.NumberVal(v.Interface().(*big.Float)), nil
+	}
+
 	switch v.Type().Kind() {
 	case reflect.Ptr, reflect.Interface:
 		if v.IsNil() {
@@ -126,6 +173,13 @@ func reflectToCty(v reflect.Value, ty cty.Type) (cty.Value, error) {
 		if s == UnknownVariableValue {
 			return cty.UnknownVal(ty), nil
 		}
+		if ty == cty.Number {
+			n, err := cty.ParseNumberVal(s)
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("can't convert Go string %q to number: %w", s, err)
+			}
+			return n, nil
+		}
 		if ty != cty.String {
 			return cty.NilVal, fmt.Errorf("can't convert Go string to %v", ty.FriendlyName())
 		}
@@ -145,6 +199,9 @@ func reflectToCty(v reflect.Value, ty cty.Type) (cty.Value, error) {
 				}
 				values[i] = val
 			}
+			if err := checkElementTypes(values, ty); err != nil {
+				return cty.NilVal, err
+			}
 			return cty.ListVal(values), nil
 		case ty.IsTupleType():
 			if v.Len() != ty.Length() {
@@ -178,6 +235,9 @@ func reflectToCty(v reflect.Value, ty cty.Type) (cty.Value, error) {
 				}
 				values[i] = val
 			}
+			if err := checkElementTypes(values, ty); err != nil {
+				return cty.NilVal, err
+			}
 			return cty.SetVal(values), nil
 		default:
 			return cty.NilVal, fmt.Errorf("can't convert Go slice to %v", ty.FriendlyName())
@@ -207,6 +267,13 @@ func reflectToCty(v reflect.Value, ty cty.Type) (cty.Value, error) {
 				}
 				values[k] = val
 			}
+			elements := make([]cty.Value, 0, len(values))
+			for _, v := range values {
+				elements = append(elements, v)
+			}
+			if err := checkElementTypes(elements, ty); err != nil {
+				return cty.NilVal, err
+			}
 			return cty.MapVal(values), nil
 		case ty.IsObjectType():
 			values := map[string]cty.Value{}
@@ -241,3 +308,86 @@ func reflectToCty(v reflect.Value, ty cty.Type) (cty.Value, error) {
 		return cty.NilVal, fmt.Errorf("unsupported Go value of type %v", v.Type())
 	}
 }
+
+// checkElementTypes returns an error if the given elements of a collection of the given type do not all have the same
+// type. This can only happen if the collection's element type is cty.DynamicPseudoType.
+func checkElementTypes(elements []cty.Value, ty cty.Type) error {
+	for _, e := range elements[1:] {
+		if !e.Type().Equals(elements[0].Type()) {
+			return fmt.Errorf("can't convert Go values of differing types to %v", ty.FriendlyName())
+		}
+	}
+	return nil
+}
+
+// reflectToDynamicCty converts a reflect.Value to a cty.Value whose type is implied by the Go value. This is used for
+// attributes of cty.DynamicPseudoType: slices are converted to tuples and maps are converted to objects so that their
+// elements may have differing types.
+func reflectToDynamicCty(v reflect.Value) (cty.Value, error) {
+	if !v.IsValid() {
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	}
+
+	if v.Type() == ctyValueType {
+		if !v.CanInterface() {
+			return cty.NullVal(cty.DynamicPseudoType), nil
+		}
+		return v.Interface().(cty.Value), nil
+	}
+
+	switch v.Type() {
+	case bigIntType, bigFloatType:
+		return reflectToCty(v, cty.Number)
+	}
+
+	switch v.Type().Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return cty.NullVal(cty.DynamicPseudoType), nil
+		}
+		return reflectToDynamicCty(v.Elem())
+	case reflect.Bool:
+		return reflectToCty(v, cty.Bool)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return reflectToCty(v, cty.Number)
+	case reflect.String:
+		if v.String() == UnknownVariableValue {
+			return cty.DynamicVal, nil
+		}
+		return cty.StringVal(v.String()), nil
+	case reflect.Slice, reflect.Array:
+		values := make([]cty.Value, v.Len())
+		for i := range values {
+			val, err := reflectToDynamicCty(v.Index(i))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			values[i] = val
+		}
+		return cty.TupleVal(values), nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return cty.NilVal, fmt.Errorf("can't convert Go map with keys that are not strings")
+		}
+
+		values := map[string]cty.Value{}
+		iter := v.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			if k == UnknownVariableValue {
+				return cty.NilVal, fmt.Errorf("can't convert Go map with unknown keys")
+			}
+
+			val, err := reflectToDynamicCty(iter.Value())
+			if err != nil {
+				return cty.NilVal, err
+			}
+			values[k] = val
+		}
+		return cty.ObjectVal(values), nil
+	default:
+		return cty.NilVal, fmt.Errorf("unsupported Go value of type %v", v.Type())
+	}
+}
@@ -1,10 +1,14 @@
-package tfplugin5
+package tfplugin
 
 import (
+	"math"
+	"math/big"
 	"testing"
+	"testing/quick"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func testCtyToGo(t *testing.T, expected interface{}, val cty.Value) {
@@ -26,9 +30,79 @@ func TestNumber(t *testing.T) {
 	testCtyToGo(t, nil, cty.NullVal(cty.Number))
 	testCtyToGo(t, UnknownVariableValue, cty.UnknownVal(cty.Number))
 
-	testCtyToGo(t, 0.0, cty.NumberIntVal(0))
+	testCtyToGo(t, int64(0), cty.NumberIntVal(0))
 	testCtyToGo(t, 3.14, cty.NumberFloatVal(3.14))
-	testCtyToGo(t, -1e10, cty.NumberFloatVal(-1e10))
+	testCtyToGo(t, int64(-1e10), cty.NumberFloatVal(-1e10))
+	testCtyToGo(t, int64(math.MaxInt64), cty.NumberIntVal(math.MaxInt64))
+	testCtyToGo(t, int64(math.MinInt64), cty.NumberIntVal(math.MinInt64))
+
+	maxUint64 := new(big.Int).SetUint64(math.MaxUint64)
+	testCtyToGo(t, maxUint64, cty.NumberUIntVal(math.MaxUint64))
+}
+
+// numberBoundaries are values around the limits of exact integer representation in float64 and int64.
+var numberBoundaries = []string{
+	"0",
+	"-1",
+	"9007199254740991",  // 2^53 - 1
+	"9007199254740992",  // 2^53
+	"9007199254740993",  // 2^53 + 1
+	"-9007199254740993", // -(2^53 + 1)
+	"9223372036854775807",
+	"-9223372036854775808",
+	"9223372036854775808",
+	"-9223372036854775809",
+	"18446744073709551615",
+	"18446744073709551616",
+	"123456789012345678901234567890",
+	"0.5",
+	"-1.25",
+}
+
+func testNumberRoundTrip(t *testing.T, val cty.Value) bool {
+	gv, err := ctyToGo(val)
+	if !assert.NoError(t, err) {
+		return false
+	}
+	actual, err := goToCty(gv, cty.Number)
+	if !assert.NoError(t, err) {
+		return false
+	}
+	return assert.True(t, val.Equals(actual).True(), "expected %v, got %v (via %T)",
+		val.AsBigFloat(), actual.AsBigFloat(), gv)
+}
+
+func TestNumberRoundTrip(t *testing.T) {
+	for _, s := range numberBoundaries {
+		val, err := cty.ParseNumberVal(s)
+		require.NoError(t, err)
+		testNumberRoundTrip(t, val)
+
+		// Numbers may also be supplied in their decimal form.
+		actual, err := goToCty(s, cty.Number)
+		if assert.NoError(t, err) {
+			assert.True(t, val.Equals(actual).True(), s)
+		}
+	}
+
+	config := &quick.Config{MaxCount: 1000}
+	assert.NoError(t, quick.Check(func(i int64) bool {
+		return testNumberRoundTrip(t, cty.NumberIntVal(i))
+	}, config))
+	assert.NoError(t, quick.Check(func(u uint64) bool {
+		return testNumberRoundTrip(t, cty.NumberUIntVal(u))
+	}, config))
+	assert.NoError(t, quick.Check(func(hi, lo uint64, neg bool) bool {
+		i := new(big.Int).Lsh(new(big.Int).SetUint64(hi), 64)
+		i.Add(i, new(big.Int).SetUint64(lo))
+		if neg {
+			i.Neg(i)
+		}
+		return testNumberRoundTrip(t, cty.NumberVal(new(big.Float).SetInt(i)))
+	}, config))
+	assert.NoError(t, quick.Check(func(f float64) bool {
+		return testNumberRoundTrip(t, cty.NumberFloatVal(f))
+	}, config))
 }
 
 func TestString(t *testing.T) {
@@ -140,3 +214,38 @@ func TestObject(t *testing.T) {
 		"baz": cty.ListVal([]cty.Value{cty.StringVal("qux"), cty.StringVal("zed")}),
 	}))
 }
+
+func TestDynamic(t *testing.T) {
+	value := map[string]interface{}{
+		"aString": "foo",
+		"aNumber": int64(42),
+		"aFloat":  1.5,
+		"aBool":   true,
+		"aNull":   nil,
+		"aList":   []interface{}{"bar", int64(1), map[string]interface{}{"nested": false}},
+	}
+
+	val, err := goToCty(value, cty.DynamicPseudoType)
+	require.NoError(t, err)
+	assert.Equal(t, cty.ObjectVal(map[string]cty.Value{
+		"aString": cty.StringVal("foo"),
+		"aNumber": cty.NumberIntVal(42),
+		"aFloat":  cty.NumberFloatVal(1.5),
+		"aBool":   cty.True,
+		"aNull":   cty.NullVal(cty.DynamicPseudoType),
+		"aList": cty.TupleVal([]cty.Value{
+			cty.StringVal("bar"),
+			cty.NumberIntVal(1),
+			cty.ObjectVal(map[string]cty.Value{"nested": cty.False}),
+		}),
+	}), val)
+	testCtyToGo(t, value, val)
+
+	unknown, err := goToCty(UnknownVariableValue, cty.DynamicPseudoType)
+	require.NoError(t, err)
+	assert.Equal(t, cty.DynamicVal, unknown)
+
+	// Collections of dynamic values must still have elements of a single type.
+	_, err = goToCty([]interface{}{"foo", 42}, cty.List(cty.DynamicPseudoType))
+	assert.Error(t, err)
+}
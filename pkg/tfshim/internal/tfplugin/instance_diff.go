@@ -1,4 +1,4 @@
-package tfplugin5
+package tfplugin
 
 import (
 	"fmt"
@@ -10,7 +10,6 @@ import (
 	"github.com/hashicorp/go-cty/cty/convert"
 
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
-	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 )
 
@@ -29,7 +28,7 @@ type instanceDiff struct {
 }
 
 func newInstanceDiff(config, prior, planned cty.Value, meta map[string]interface{},
-	requiresReplace []*proto.AttributePath) *instanceDiff {
+	requiresReplace []cty.Path) *instanceDiff {
 
 	attributes, requiresNew := computeDiff(prior, planned, requiresReplace)
 	return &instanceDiff{
@@ -65,7 +64,7 @@ func (d *instanceDiff) ProposedState(res shim.Resource, priorState shim.Instance
 	}
 
 	return &instanceState{
-		resourceType: res.(*resource).resourceType,
+		resourceType: res.(ResourceShim).V.ResourceType,
 		id:           id,
 		object:       plannedObject.(map[string]interface{}),
 		meta:         d.meta,
@@ -167,25 +166,23 @@ type differ struct {
 	isRequiresNew bool
 }
 
-func pathString(path *proto.AttributePath) string {
+func pathString(path cty.Path) string {
 	var builder strings.Builder
-	for _, s := range path.Steps {
-		switch s := s.Selector.(type) {
-		case *proto.AttributePath_Step_AttributeName:
-			if builder.Len() != 0 {
-				builder.WriteString(".")
-			}
-			builder.WriteString(s.AttributeName)
-		case *proto.AttributePath_Step_ElementKeyString:
-			if builder.Len() != 0 {
-				builder.WriteString(".")
-			}
-			builder.WriteString(s.ElementKeyString)
-		case *proto.AttributePath_Step_ElementKeyInt:
-			if builder.Len() != 0 {
-				builder.WriteString(".")
+	for _, s := range path {
+		if builder.Len() != 0 {
+			builder.WriteString(".")
+		}
+		switch s := s.(type) {
+		case cty.GetAttrStep:
+			builder.WriteString(s.Name)
+		case cty.IndexStep:
+			switch {
+			case s.Key.Type() == cty.String:
+				builder.WriteString(s.Key.AsString())
+			case s.Key.Type() == cty.Number:
+				index, _ := s.Key.AsBigFloat().Int64()
+				builder.WriteString(strconv.FormatInt(index, 10))
 			}
-			builder.WriteString(strconv.FormatInt(s.ElementKeyInt, 10))
 		}
 	}
 	return builder.String()
@@ -216,7 +213,7 @@ func rangeValue(val cty.Value, each func(k, v cty.Value)) {
 }
 
 func computeDiff(prior, planned cty.Value,
-	requiresReplace []*proto.AttributePath) (map[string]shim.ResourceAttrDiff, bool) {
+	requiresReplace []cty.Path) (map[string]shim.ResourceAttrDiff, bool) {
 
 	requiresNew := stringSet{}
 	for _, path := range requiresReplace {
@@ -1,4 +1,4 @@
-package tfplugin5
+package tfplugin
 
 import (
 	"testing"
@@ -7,7 +7,6 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
-	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
 )
 
 func add(new string, replace bool) shim.ResourceAttrDiff {
@@ -33,53 +32,53 @@ func update(old, new string, replace bool) shim.ResourceAttrDiff {
 	}
 }
 
-func path(elements ...interface{}) *proto.AttributePath {
-	steps := make([]*proto.AttributePath_Step, len(elements))
-	for i, e := range elements {
+func path(elements ...interface{}) cty.Path {
+	var p cty.Path
+	for _, e := range elements {
 		switch e := e.(type) {
 		case string:
-			steps[i] = &proto.AttributePath_Step{
-				Selector: &proto.AttributePath_Step_AttributeName{AttributeName: e},
-			}
+			p = p.GetAttr(e)
 		case int:
-			steps[i] = &proto.AttributePath_Step{
-				Selector: &proto.AttributePath_Step_ElementKeyInt{ElementKeyInt: int64(e)},
-			}
+			p = p.Index(cty.NumberIntVal(int64(e)))
 		}
 	}
-	return &proto.AttributePath{Steps: steps}
+	return p
 }
 
-func resolvePath(path *proto.AttributePath, ty cty.Type) {
-	for _, s := range path.Steps {
-		switch sel := s.Selector.(type) {
-		case *proto.AttributePath_Step_AttributeName:
+func resolvePath(path cty.Path, ty cty.Type) cty.Path {
+	resolved := make(cty.Path, len(path))
+	for i, s := range path {
+		resolved[i] = s
+		switch s := s.(type) {
+		case cty.GetAttrStep:
 			if ty.IsMapType() {
-				s.Selector = &proto.AttributePath_Step_ElementKeyString{ElementKeyString: sel.AttributeName}
+				resolved[i] = cty.IndexStep{Key: cty.StringVal(s.Name)}
 			} else if ty.IsObjectType() {
 				// This case only exists to handle the fact that set element keys passed to path() are strings. This
 				// should be changed once set element key info is validated.
-				ty = ty.AttributeType(sel.AttributeName)
+				ty = ty.AttributeType(s.Name)
 				continue
 			}
-		case *proto.AttributePath_Step_ElementKeyInt:
+		case cty.IndexStep:
 			if ty.IsTupleType() {
-				ty = ty.TupleElementType(int(sel.ElementKeyInt))
+				index, _ := s.Key.AsBigFloat().Int64()
+				ty = ty.TupleElementType(int(index))
 				continue
 			}
 		}
 
 		ty = ty.ElementType()
 	}
+	return resolved
 }
 
-func diffTest(t *testing.T, attributes map[string]cty.Type, requiresReplace []*proto.AttributePath,
+func diffTest(t *testing.T, attributes map[string]cty.Type, requiresReplace []cty.Path,
 	planned, prior interface{}, expected map[string]shim.ResourceAttrDiff) {
 
 	objectType := cty.Object(attributes)
 
-	for _, p := range requiresReplace {
-		resolvePath(p, objectType)
+	for i, p := range requiresReplace {
+		requiresReplace[i] = resolvePath(p, objectType)
 	}
 
 	priorVal, err := goToCty(prior, objectType)
@@ -113,7 +112,7 @@ func TestEmptyDiff(t *testing.T) {
 			"prop": cty.String,
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": "foo",
 			"outp": "bar",
@@ -131,7 +130,7 @@ func TestSimpleAdd(t *testing.T) {
 			"prop": cty.String,
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": "foo",
 			"outp": "bar",
@@ -150,7 +149,7 @@ func TestSimpleAddReplace(t *testing.T) {
 			"prop": cty.String,
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"prop": "foo",
 			"outp": "bar",
@@ -169,7 +168,7 @@ func TestSimpleDelete(t *testing.T) {
 			"prop": cty.String,
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"outp": "bar",
 		},
@@ -188,7 +187,7 @@ func TestSimpleDeleteReplace(t *testing.T) {
 			"prop": cty.String,
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"outp": "bar",
 		},
@@ -207,7 +206,7 @@ func TestSimpleUpdate(t *testing.T) {
 			"prop": cty.String,
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": "baz",
 			"outp": "bar",
@@ -227,7 +226,7 @@ func TestSimpleUpdateReplace(t *testing.T) {
 			"prop": cty.String,
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"prop": "baz",
 			"outp": "bar",
@@ -247,7 +246,7 @@ func TestNestedAdd(t *testing.T) {
 			"prop": cty.Map(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": map[string]interface{}{"nest": "foo"},
 			"outp": "bar",
@@ -267,7 +266,7 @@ func TestNestedAddReplace(t *testing.T) {
 			"prop": cty.Map(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"prop": map[string]interface{}{"nest": "foo"},
 			"outp": "bar",
@@ -285,7 +284,7 @@ func TestNestedAddReplace(t *testing.T) {
 			"prop": cty.Map(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop", "nest")},
+		[]cty.Path{path("prop", "nest")},
 		map[string]interface{}{
 			"prop": map[string]interface{}{"nest": "foo"},
 			"outp": "bar",
@@ -305,7 +304,7 @@ func TestNestedDelete(t *testing.T) {
 			"prop": cty.Map(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"outp": "bar",
 		},
@@ -325,7 +324,7 @@ func TestNestedDeleteReplace(t *testing.T) {
 			"prop": cty.Map(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"outp": "bar",
 		},
@@ -343,7 +342,7 @@ func TestNestedDeleteReplace(t *testing.T) {
 			"prop": cty.Map(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop", "nest")},
+		[]cty.Path{path("prop", "nest")},
 		map[string]interface{}{
 			"outp": "bar",
 		},
@@ -363,7 +362,7 @@ func TestNestedUpdate(t *testing.T) {
 			"prop": cty.Map(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": map[string]interface{}{"nest": "baz"},
 			"outp": "bar",
@@ -383,7 +382,7 @@ func TestNestedUpdateReplace(t *testing.T) {
 			"prop": cty.Map(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"prop": map[string]interface{}{"nest": "baz"},
 			"outp": "bar",
@@ -401,7 +400,7 @@ func TestNestedUpdateReplace(t *testing.T) {
 			"prop": cty.Map(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop", "nest")},
+		[]cty.Path{path("prop", "nest")},
 		map[string]interface{}{
 			"prop": map[string]interface{}{"nest": "baz"},
 			"outp": "bar",
@@ -421,7 +420,7 @@ func TestListAdd(t *testing.T) {
 			"prop": cty.List(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": []interface{}{"foo"},
 			"outp": "bar",
@@ -441,7 +440,7 @@ func TestListAddReplace(t *testing.T) {
 			"prop": cty.List(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"prop": []interface{}{"foo"},
 			"outp": "bar",
@@ -459,7 +458,7 @@ func TestListAddReplace(t *testing.T) {
 			"prop": cty.List(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop", 0)},
+		[]cty.Path{path("prop", 0)},
 		map[string]interface{}{
 			"prop": []interface{}{"foo"},
 			"outp": "bar",
@@ -479,7 +478,7 @@ func TestListDelete(t *testing.T) {
 			"prop": cty.List(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"outp": "bar",
 		},
@@ -499,7 +498,7 @@ func TestListDeleteReplace(t *testing.T) {
 			"prop": cty.List(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"outp": "bar",
 		},
@@ -517,7 +516,7 @@ func TestListDeleteReplace(t *testing.T) {
 			"prop": cty.List(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop", 0)},
+		[]cty.Path{path("prop", 0)},
 		map[string]interface{}{
 			"outp": "bar",
 		},
@@ -537,7 +536,7 @@ func TestListUpdate(t *testing.T) {
 			"prop": cty.List(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": []interface{}{"baz"},
 			"outp": "bar",
@@ -557,7 +556,7 @@ func TestListUpdateReplace(t *testing.T) {
 			"prop": cty.List(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"prop": []interface{}{"baz"},
 			"outp": "bar",
@@ -575,7 +574,7 @@ func TestListUpdateReplace(t *testing.T) {
 			"prop": cty.List(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop", 0)},
+		[]cty.Path{path("prop", 0)},
 		map[string]interface{}{
 			"prop": []interface{}{"baz"},
 			"outp": "bar",
@@ -595,7 +594,7 @@ func TestSetAdd(t *testing.T) {
 			"prop": cty.Set(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": []interface{}{"foo"},
 			"outp": "bar",
@@ -615,7 +614,7 @@ func TestSetAddReplace(t *testing.T) {
 			"prop": cty.Set(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"prop": []interface{}{"foo"},
 			"outp": "bar",
@@ -635,7 +634,7 @@ func TestSetDelete(t *testing.T) {
 			"prop": cty.Set(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"outp": "bar",
 		},
@@ -655,7 +654,7 @@ func TestSetDeleteReplace(t *testing.T) {
 			"prop": cty.Set(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"outp": "bar",
 		},
@@ -675,7 +674,7 @@ func TestUnknownSimpleUpdate(t *testing.T) {
 			"prop": cty.String,
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": UnknownVariableValue,
 			"outp": "bar",
@@ -695,7 +694,7 @@ func TestUnknownSimpleUpdateReplace(t *testing.T) {
 			"prop": cty.String,
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"prop": UnknownVariableValue,
 			"outp": "bar",
@@ -715,7 +714,7 @@ func TestUnknownMapUpdate(t *testing.T) {
 			"prop": cty.Map(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": UnknownVariableValue,
 			"outp": "bar",
@@ -735,7 +734,7 @@ func TestUnknownNestedUpdate(t *testing.T) {
 			"prop": cty.Map(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": map[string]interface{}{"nest": UnknownVariableValue},
 			"outp": "bar",
@@ -755,7 +754,7 @@ func TestUnknownNestedUpdateReplace(t *testing.T) {
 			"prop": cty.Map(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"prop": map[string]interface{}{"nest": UnknownVariableValue},
 			"outp": "bar",
@@ -773,7 +772,7 @@ func TestUnknownNestedUpdateReplace(t *testing.T) {
 			"prop": cty.Map(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop", "nest")},
+		[]cty.Path{path("prop", "nest")},
 		map[string]interface{}{
 			"prop": map[string]interface{}{"nest": UnknownVariableValue},
 			"outp": "bar",
@@ -793,7 +792,7 @@ func TestUnknownListUpdate(t *testing.T) {
 			"prop": cty.List(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": UnknownVariableValue,
 			"outp": "bar",
@@ -813,7 +812,7 @@ func TestUnknownListElementUpdate(t *testing.T) {
 			"prop": cty.List(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": []interface{}{UnknownVariableValue},
 			"outp": "bar",
@@ -833,7 +832,7 @@ func TestUnknownListElementUpdateReplace(t *testing.T) {
 			"prop": cty.List(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"prop": []interface{}{UnknownVariableValue},
 			"outp": "bar",
@@ -851,7 +850,7 @@ func TestUnknownListElementUpdateReplace(t *testing.T) {
 			"prop": cty.List(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop", 0)},
+		[]cty.Path{path("prop", 0)},
 		map[string]interface{}{
 			"prop": []interface{}{UnknownVariableValue},
 			"outp": "bar",
@@ -871,7 +870,7 @@ func TestUnknownSetUpdate(t *testing.T) {
 			"prop": cty.Set(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{},
+		[]cty.Path{},
 		map[string]interface{}{
 			"prop": UnknownVariableValue,
 			"outp": "bar",
@@ -891,7 +890,7 @@ func TestUnknownSetUpdateReplace(t *testing.T) {
 			"prop": cty.Set(cty.String),
 			"outp": cty.String,
 		},
-		[]*proto.AttributePath{path("prop")},
+		[]cty.Path{path("prop")},
 		map[string]interface{}{
 			"prop": UnknownVariableValue,
 			"outp": "bar",
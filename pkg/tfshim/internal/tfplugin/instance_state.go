@@ -1,4 +1,4 @@
-package tfplugin5
+package tfplugin
 
 import (
 	"github.com/hashicorp/go-cty/cty"
@@ -0,0 +1,302 @@
+package tfplugin
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func deprecationMessage(propertyName string, isDeprecated bool) string {
+	if isDeprecated {
+		return fmt.Sprintf("%v is deprecated", propertyName)
+	}
+	return ""
+}
+
+// mapOfObjects returns the element schema of a map whose elements are objects of the given type. The element is a
+// TypeMap schema whose elem is the object type, which distinguishes a map of objects from a single object.
+func mapOfObjects(r *Resource) shim.Schema {
+	return (&AttributeSchema{
+		CtyType:   r.CtyType,
+		ValueType: shim.TypeMap,
+		Elem:      r.Shim(),
+	}).Shim()
+}
+
+func unmarshalNestedType(elementType cty.Type) (interface{}, error) {
+	valueType, elem, err := unmarshalType(elementType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch valueType {
+	case shim.TypeBool, shim.TypeInt, shim.TypeFloat, shim.TypeString, shim.TypeDynamic:
+		return (&AttributeSchema{
+			CtyType:   elementType,
+			ValueType: valueType,
+		}).Shim(), nil
+	case shim.TypeList, shim.TypeSet:
+		return (&AttributeSchema{
+			CtyType:   elementType,
+			ValueType: valueType,
+			Elem:      elem,
+		}).Shim(), nil
+	case shim.TypeMap:
+		if r, ok := elem.(ResourceShim); ok {
+			return r, nil
+		}
+		return (&AttributeSchema{
+			CtyType:   elementType,
+			ValueType: valueType,
+			Elem:      elem,
+		}).Shim(), nil
+	default:
+		return nil, fmt.Errorf("unexpected value type %v", valueType)
+	}
+}
+
+func unmarshalCompositeType(ty cty.Type) (shim.ValueType, interface{}, error) {
+	switch {
+	case ty.IsListType():
+		elementType, err := unmarshalNestedType(ty.ElementType())
+		if err != nil {
+			return shim.TypeInvalid, nil, err
+		}
+		return shim.TypeList, elementType, nil
+	case ty.IsMapType():
+		elementType, err := unmarshalNestedType(ty.ElementType())
+		if err != nil {
+			return shim.TypeInvalid, nil, err
+		}
+		if r, ok := elementType.(ResourceShim); ok {
+			elementType = mapOfObjects(r.V)
+		}
+		return shim.TypeMap, elementType, nil
+	case ty.IsSetType():
+		elementType, err := unmarshalNestedType(ty.ElementType())
+		if err != nil {
+			return shim.TypeInvalid, nil, err
+		}
+		return shim.TypeSet, elementType, nil
+	case ty.IsObjectType():
+		properties := schema.SchemaMap{}
+		for name, ty := range ty.AttributeTypes() {
+			property, err := unmarshalNestedType(ty)
+			if err != nil {
+				return shim.TypeInvalid, nil, err
+			}
+			switch property := property.(type) {
+			case shim.Schema:
+				properties[name] = property
+			case ResourceShim:
+				properties[name] = (&AttributeSchema{
+					CtyType:   property.V.CtyType,
+					ValueType: shim.TypeMap,
+					Elem:      property,
+				}).Shim()
+			}
+		}
+		return shim.TypeMap, (&Resource{CtyType: ty, Schema: properties}).Shim(), nil
+	default:
+		return shim.TypeInvalid, nil, fmt.Errorf("unexpected composite type %v", ty)
+	}
+}
+
+func unmarshalType(ty cty.Type) (shim.ValueType, interface{}, error) {
+	switch ty {
+	case cty.String:
+		return shim.TypeString, nil, nil
+	case cty.Bool:
+		return shim.TypeBool, nil, nil
+	case cty.Number:
+		return shim.TypeFloat, nil, nil
+	case cty.DynamicPseudoType:
+		return shim.TypeDynamic, nil, nil
+	default:
+		return unmarshalCompositeType(ty)
+	}
+}
+
+func unmarshalObject(object *Object) (cty.Type, schema.SchemaMap, error) {
+	attributes, properties := map[string]cty.Type{}, schema.SchemaMap{}
+	for _, attribute := range object.Attributes {
+		property, err := unmarshalAttribute(attribute)
+		if err != nil {
+			return cty.Type{}, nil, err
+		}
+		attributes[attribute.Name], properties[attribute.Name] = property.CtyType, property.Shim()
+	}
+	return cty.Object(attributes), properties, nil
+}
+
+func unmarshalNestedAttributeType(object *Object) (cty.Type, shim.ValueType, interface{}, error) {
+	objectType, properties, err := unmarshalObject(object)
+	if err != nil {
+		return cty.Type{}, shim.TypeInvalid, nil, err
+	}
+
+	elem := &Resource{CtyType: objectType, Schema: properties}
+	switch object.Nesting {
+	case NestingSingle:
+		return objectType, shim.TypeMap, elem.Shim(), nil
+	case NestingList:
+		return cty.List(objectType), shim.TypeList, elem.Shim(), nil
+	case NestingSet:
+		return cty.Set(objectType), shim.TypeSet, elem.Shim(), nil
+	case NestingMap:
+		return cty.Map(objectType), shim.TypeMap, mapOfObjects(elem), nil
+	default:
+		return cty.Type{}, shim.TypeInvalid, nil, fmt.Errorf("unexpected nesting mode %v", object.Nesting)
+	}
+}
+
+func unmarshalAttribute(attribute *Attribute) (*AttributeSchema, error) {
+	var ty cty.Type
+	var valueType shim.ValueType
+	var elem interface{}
+	var minItems, maxItems int
+	var objectNesting NestingMode
+	if attribute.NestedType != nil {
+		t, vt, e, err := unmarshalNestedAttributeType(attribute.NestedType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal nested type: %w", err)
+		}
+		ty, valueType, elem = t, vt, e
+		minItems, maxItems = int(attribute.NestedType.MinItems), int(attribute.NestedType.MaxItems)
+		objectNesting = attribute.NestedType.Nesting
+	} else {
+		if err := json.Unmarshal(attribute.Type, &ty); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal type: %w", err)
+		}
+
+		vt, e, err := unmarshalType(ty)
+		if err != nil {
+			return nil, err
+		}
+		valueType, elem = vt, e
+	}
+
+	optional := attribute.Optional
+	if !attribute.Computed && !attribute.Required {
+		optional = true
+	}
+
+	return &AttributeSchema{
+		CtyType:       ty,
+		ValueType:     valueType,
+		Elem:          elem,
+		Description:   attribute.Description,
+		Required:      attribute.Required,
+		Optional:      optional,
+		Computed:      attribute.Computed,
+		Sensitive:     attribute.Sensitive,
+		Deprecated:    deprecationMessage(attribute.Name, attribute.Deprecated),
+		MinItems:      minItems,
+		MaxItems:      maxItems,
+		ObjectNesting: objectNesting,
+	}, nil
+}
+
+func unmarshalBlock(block *Block) (cty.Type, schema.SchemaMap, bool, error) {
+	attributes, properties, allComputed := map[string]cty.Type{}, schema.SchemaMap{}, true
+	for _, attribute := range block.Attributes {
+		property, err := unmarshalAttribute(attribute)
+		if err != nil {
+			return cty.Type{}, nil, false, err
+		}
+		attributes[attribute.Name], properties[attribute.Name] = property.CtyType, property.Shim()
+		if !property.Computed {
+			allComputed = false
+		}
+	}
+	for _, nestedBlock := range block.BlockTypes {
+		property, err := unmarshalNestedBlock(nestedBlock)
+		if err != nil {
+			return cty.Type{}, nil, false, err
+		}
+		attributes[nestedBlock.TypeName], properties[nestedBlock.TypeName] = property.CtyType, property.Shim()
+		if !property.Computed {
+			allComputed = false
+		}
+	}
+	return cty.Object(attributes), properties, allComputed, nil
+}
+
+func unmarshalNestedBlock(nestedBlock *NestedBlock) (*AttributeSchema, error) {
+	objectType, properties, computed, err := unmarshalBlock(nestedBlock.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	object := &Resource{CtyType: objectType, Schema: properties}
+	ctyType, valueType, elem := objectType, shim.TypeMap, interface{}(object.Shim())
+	switch nestedBlock.Nesting {
+	case NestingList:
+		ctyType, valueType = cty.List(objectType), shim.TypeList
+	case NestingSet:
+		ctyType, valueType = cty.Set(objectType), shim.TypeSet
+	case NestingMap:
+		ctyType, elem = cty.Map(objectType), mapOfObjects(object)
+	}
+
+	required, optional := false, false
+	if !computed {
+		if nestedBlock.Nesting == NestingSingle {
+			required, optional = true, false
+		} else {
+			required, optional = nestedBlock.MinItems > 0, nestedBlock.MinItems == 0
+		}
+	}
+	return &AttributeSchema{
+		CtyType:     ctyType,
+		ValueType:   valueType,
+		Elem:        elem,
+		Description: nestedBlock.Block.Description,
+		Required:    required,
+		Optional:    optional,
+		Computed:    computed,
+		Deprecated:  deprecationMessage(nestedBlock.TypeName, nestedBlock.Block.Deprecated),
+		MinItems:    int(nestedBlock.MinItems),
+		MaxItems:    int(nestedBlock.MaxItems),
+		Nesting:     nestedBlock.Nesting,
+	}, nil
+}
+
+func unmarshalResource(p *provider, typeName string, resourceSchema *Schema) (*Resource, error) {
+	ctyType, properties, _, err := unmarshalBlock(resourceSchema.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure that `id` is treated as a pure output property.
+	if id, ok := properties["id"]; ok {
+		schema := attributeSchema(id)
+		schema.Optional = false
+		schema.Required = false
+		schema.Computed = true
+	}
+
+	return &Resource{
+		provider:      p,
+		ResourceType:  typeName,
+		CtyType:       ctyType,
+		Schema:        properties,
+		SchemaVersion: int(resourceSchema.Version),
+	}, nil
+}
+
+func unmarshalResourceMap(p *provider, resources map[string]*Schema) (resourceMap, error) {
+	resourceMap := resourceMap{}
+	for name, schema := range resources {
+		r, err := unmarshalResource(p, name, schema)
+		if err != nil {
+			return nil, err
+		}
+		resourceMap[name] = r
+	}
+	return resourceMap, nil
+}
@@ -0,0 +1,156 @@
+package tfplugin
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+func nestedAttribute(name string, nesting NestingMode, optional bool) *Attribute {
+	return &Attribute{
+		Name:     name,
+		Optional: optional,
+		Computed: !optional,
+		NestedType: &Object{
+			Nesting: nesting,
+			Attributes: []*Attribute{
+				{Name: "name", Type: []byte(`"string"`), Required: true},
+				{Name: "port", Type: []byte(`"number"`), Optional: true},
+			},
+		},
+	}
+}
+
+var nestedSchema = &Schema{
+	Block: &Block{
+		Attributes: []*Attribute{
+			{Name: "id", Type: []byte(`"string"`), Computed: true},
+			nestedAttribute("single", NestingSingle, true),
+			nestedAttribute("list", NestingList, true),
+			nestedAttribute("set", NestingSet, false),
+			nestedAttribute("map", NestingMap, true),
+		},
+	},
+}
+
+// assertObjectSchema asserts that the given resource is the object type of the attributes in nestedSchema.
+func assertObjectSchema(t *testing.T, elem shim.Resource, name string) {
+	assert.True(t, elem.Schema().Get("name").Required(), name)
+	assert.Equal(t, shim.TypeString, elem.Schema().Get("name").Type(), name)
+	assert.True(t, elem.Schema().Get("port").Optional(), name)
+	assert.Equal(t, shim.TypeFloat, elem.Schema().Get("port").Type(), name)
+}
+
+func TestUnmarshalNestedAttributes(t *testing.T) {
+	r, err := unmarshalResource(nil, "nested", nestedSchema)
+	require.NoError(t, err)
+
+	objectType := cty.Object(map[string]cty.Type{"name": cty.String, "port": cty.Number})
+	assert.Equal(t, cty.Object(map[string]cty.Type{
+		"id":     cty.String,
+		"single": objectType,
+		"list":   cty.List(objectType),
+		"set":    cty.Set(objectType),
+		"map":    cty.Map(objectType),
+	}), r.CtyType)
+
+	expected := map[string]shim.ValueType{
+		"single": shim.TypeMap,
+		"list":   shim.TypeList,
+		"set":    shim.TypeSet,
+	}
+	for name, valueType := range expected {
+		s := r.Schema.Get(name)
+		assert.Equal(t, valueType, s.Type(), name)
+		assert.Equal(t, name != "set", s.Optional(), name)
+		assert.Equal(t, name == "set", s.Computed(), name)
+
+		elem, ok := s.Elem().(shim.Resource)
+		if assert.True(t, ok, name) {
+			assertObjectSchema(t, elem, name)
+		}
+	}
+
+	// A map of objects is a map whose element is an object schema, as opposed to a single object, whose elem is the
+	// object type itself.
+	m := r.Schema.Get("map")
+	assert.Equal(t, shim.TypeMap, m.Type())
+	assert.True(t, m.Optional())
+	elem, ok := m.Elem().(shim.Schema)
+	if assert.True(t, ok) {
+		assert.Equal(t, shim.TypeMap, elem.Type())
+		object, ok := elem.Elem().(shim.Resource)
+		if assert.True(t, ok) {
+			assertObjectSchema(t, object, "map")
+		}
+	}
+}
+
+func TestUnmarshalNestedBlockMap(t *testing.T) {
+	s, err := unmarshalNestedBlock(&NestedBlock{
+		TypeName: "block",
+		Nesting:  NestingMap,
+		Block: &Block{
+			Attributes: []*Attribute{
+				{Name: "name", Type: []byte(`"string"`), Required: true},
+				{Name: "port", Type: []byte(`"number"`), Optional: true},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	objectType := cty.Object(map[string]cty.Type{"name": cty.String, "port": cty.Number})
+	assert.Equal(t, cty.Map(objectType), s.CtyType)
+	assert.Equal(t, shim.TypeMap, s.ValueType)
+	assert.Equal(t, objectType, objectResource(s).CtyType)
+	elem, ok := s.Elem.(shim.Schema)
+	if assert.True(t, ok) {
+		assert.Equal(t, shim.TypeMap, elem.Type())
+		object, ok := elem.Elem().(shim.Resource)
+		if assert.True(t, ok) {
+			assertObjectSchema(t, object, "block")
+		}
+	}
+}
+
+func TestUnmarshalObjectTypes(t *testing.T) {
+	objectType := cty.Object(map[string]cty.Type{"name": cty.String, "port": cty.Number})
+
+	// A plain object type is a TypeMap whose elem is the object type.
+	valueType, elem, err := unmarshalType(objectType)
+	require.NoError(t, err)
+	assert.Equal(t, shim.TypeMap, valueType)
+	object, ok := elem.(shim.Resource)
+	if assert.True(t, ok) {
+		assert.Equal(t, shim.TypeString, object.Schema().Get("name").Type())
+		assert.Equal(t, shim.TypeFloat, object.Schema().Get("port").Type())
+	}
+
+	// A map of objects is a TypeMap whose elem is an object schema.
+	valueType, elem, err = unmarshalType(cty.Map(objectType))
+	require.NoError(t, err)
+	assert.Equal(t, shim.TypeMap, valueType)
+	s, ok := elem.(shim.Schema)
+	if assert.True(t, ok) {
+		assert.Equal(t, shim.TypeMap, s.Type())
+		object, ok := s.Elem().(shim.Resource)
+		if assert.True(t, ok) {
+			assert.Equal(t, shim.TypeString, object.Schema().Get("name").Type())
+		}
+	}
+
+	// An object-typed attribute of an object is an object schema.
+	valueType, elem, err = unmarshalType(cty.Object(map[string]cty.Type{"inner": objectType}))
+	require.NoError(t, err)
+	assert.Equal(t, shim.TypeMap, valueType)
+	inner := elem.(shim.Resource).Schema().Get("inner")
+	assert.Equal(t, shim.TypeMap, inner.Type())
+	object, ok = inner.Elem().(shim.Resource)
+	if assert.True(t, ok) {
+		assert.Equal(t, shim.TypeFloat, object.Schema().Get("port").Type())
+	}
+}
@@ -0,0 +1,222 @@
+package tfplugin
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+// proposedNew constructs a proposed new object value by combining the computed attribute values from "prior" with
+// the configured attribute values from "config". This mirrors the algorithm Terraform core uses to compute the
+// ProposedNewState it sends to PlanResourceChange (see objchange.ProposedNew in hashicorp/terraform).
+//
+// Both values must be of the object type implied by the given schema.
+func proposedNew(sch schema.SchemaMap, ty cty.Type, prior, config cty.Value) cty.Value {
+	// If the config and prior are both null, return early here before populating the prior block. This prevents
+	// non-null blocks from appearing in the proposed state value.
+	if config.IsNull() && prior.IsNull() {
+		return prior
+	}
+
+	if prior.IsNull() {
+		// Construct a synthetic prior value that is similar to the result of decoding an empty configuration block,
+		// which gives us one non-null level of object to pull values from below.
+		prior = allAttributesNull(sch, ty)
+	}
+	return proposedNewObject(sch, prior, config)
+}
+
+func proposedNewObject(sch schema.SchemaMap, prior, config cty.Value) cty.Value {
+	if config.IsNull() || !config.IsKnown() {
+		return prior
+	}
+
+	// From this point onwards both values are non-null objects and the config value itself is known, though it may
+	// contain nested values that are unknown.
+	newAttrs := map[string]cty.Value{}
+	for name, s := range sch {
+		attr := attributeSchema(s)
+		priorV, configV := prior.GetAttr(name), config.GetAttr(name)
+		if attr.Nesting == NestingInvalid {
+			newAttrs[name] = proposedNewAttribute(attr, priorV, configV)
+		} else {
+			newAttrs[name] = proposedNewNested(attr.Nesting, objectResource(attr), priorV, configV)
+		}
+	}
+	return cty.ObjectVal(newAttrs)
+}
+
+func proposedNewAttribute(attr *AttributeSchema, priorV, configV cty.Value) cty.Value {
+	switch {
+	case attr.Computed && attr.Optional:
+		// Keep the prior value if the config isn't overriding it. As in Terraform, this makes a configured value
+		// "sticky" if it is later removed from the config, unless the provider overrides it during planning.
+		if configV.IsNull() {
+			return priorV
+		}
+		return proposedNewAttributeValue(attr, priorV, configV)
+	case attr.Computed:
+		// The config value is always null for a computed attribute, so the prior value carries over.
+		return priorV
+	default:
+		// Non-computed attributes always take the config value, even if it is null.
+		return proposedNewAttributeValue(attr, priorV, configV)
+	}
+}
+
+// proposedNewAttributeValue returns the proposed value for a configured attribute. Nested attributes are correlated
+// with their prior values in the same way as nested blocks so that computed values within them are preserved.
+func proposedNewAttributeValue(attr *AttributeSchema, priorV, configV cty.Value) cty.Value {
+	if attr.ObjectNesting == NestingInvalid || configV.IsNull() {
+		return configV
+	}
+	return proposedNewNested(attr.ObjectNesting, objectResource(attr), priorV, configV)
+}
+
+func proposedNewNested(nesting NestingMode, block *Resource,
+	priorV, configV cty.Value) cty.Value {
+
+	switch nesting {
+	case NestingList:
+		// Nested blocks are correlated by index.
+		if configV.IsNull() || !configV.IsKnown() || configV.LengthInt() == 0 {
+			return cty.ListValEmpty(block.CtyType)
+		}
+		newVals := make([]cty.Value, 0, configV.LengthInt())
+		for it := configV.ElementIterator(); it.Next(); {
+			idx, configEV := it.Element()
+			if priorV.IsKnown() && (priorV.IsNull() || !priorV.HasIndex(idx).True()) {
+				// There is no corresponding prior element, so take the config value as-is.
+				newVals = append(newVals, configEV)
+				continue
+			}
+			newVals = append(newVals, proposedNewObject(block.Schema, priorV.Index(idx), configEV))
+		}
+		return cty.ListVal(newVals)
+	case NestingMap:
+		// Nested blocks are correlated by key.
+		if configV.IsNull() || !configV.IsKnown() || configV.LengthInt() == 0 {
+			return cty.MapValEmpty(block.CtyType)
+		}
+		newVals := make(map[string]cty.Value, configV.LengthInt())
+		for it := configV.ElementIterator(); it.Next(); {
+			idx, configEV := it.Element()
+			k := idx.AsString()
+			if priorV.IsNull() || !priorV.IsKnown() || !priorV.HasIndex(idx).True() {
+				newVals[k] = configEV
+				continue
+			}
+			newVals[k] = proposedNewObject(block.Schema, priorV.Index(idx), configEV)
+		}
+		return cty.MapVal(newVals)
+	case NestingSet:
+		// Nested blocks are correlated by comparing the element values after eliminating all of the computed
+		// attributes. In practice, this means that any config change produces an entirely new nested object, and
+		// prior computed values are only propagated if the non-computed attribute values are identical.
+		var cmpVals [][2]cty.Value
+		if priorV.IsKnown() && !priorV.IsNull() {
+			cmpVals = setElementCompareValues(block.Schema, priorV)
+		}
+		if configV.IsNull() || !configV.IsKnown() || configV.LengthInt() == 0 {
+			return cty.SetValEmpty(block.CtyType)
+		}
+		// Track used elements in case multiple prior elements have the same compare value.
+		used := make([]bool, len(cmpVals))
+		newVals := make([]cty.Value, 0, configV.LengthInt())
+		for it := configV.ElementIterator(); it.Next(); {
+			_, configEV := it.Element()
+			priorEV := cty.NullVal(block.CtyType)
+			for i, cmp := range cmpVals {
+				if !used[i] && cmp[1].RawEquals(configEV) {
+					priorEV, used[i] = cmp[0], true
+					break
+				}
+			}
+			newVals = append(newVals, proposedNew(block.Schema, block.CtyType, priorEV, configEV))
+		}
+		return cty.SetVal(newVals)
+	default:
+		// SINGLE and GROUP blocks are a single nested object.
+		return proposedNew(block.Schema, block.CtyType, priorV, configV)
+	}
+}
+
+// allAttributesNull returns an object of the given type with all attributes set to null.
+func allAttributesNull(sch schema.SchemaMap, ty cty.Type) cty.Value {
+	vals := map[string]cty.Value{}
+	for name := range sch {
+		vals[name] = cty.NullVal(ty.AttributeType(name))
+	}
+	return cty.ObjectVal(vals)
+}
+
+func setElementCompareValues(sch schema.SchemaMap, set cty.Value) [][2]cty.Value {
+	ret := make([][2]cty.Value, 0, set.LengthInt())
+	for it := set.ElementIterator(); it.Next(); {
+		_, ev := it.Element()
+		ret = append(ret, [2]cty.Value{ev, setElementCompareValue(sch, ev)})
+	}
+	return ret
+}
+
+// setElementCompareValue creates a new value that has all of the same non-computed attribute values as the one
+// given but has all computed attribute values, including optional+computed ones, forced to null.
+func setElementCompareValue(sch schema.SchemaMap, v cty.Value) cty.Value {
+	if v.IsNull() || !v.IsKnown() {
+		return v
+	}
+
+	attrs := map[string]cty.Value{}
+	for name, s := range sch {
+		attr := attributeSchema(s)
+		cv := v.GetAttr(name)
+		if attr.Nesting == NestingInvalid {
+			if attr.Computed {
+				cv = cty.NullVal(attr.CtyType)
+			}
+			attrs[name] = cv
+			continue
+		}
+
+		block := objectResource(attr)
+		switch attr.Nesting {
+		case NestingList, NestingSet:
+			if cv.IsNull() || !cv.IsKnown() {
+				attrs[name] = cv
+				continue
+			}
+			if cv.LengthInt() == 0 {
+				if attr.Nesting == NestingSet {
+					attrs[name] = cty.SetValEmpty(block.CtyType)
+				} else {
+					attrs[name] = cty.ListValEmpty(block.CtyType)
+				}
+				continue
+			}
+			elems := make([]cty.Value, 0, cv.LengthInt())
+			for it := cv.ElementIterator(); it.Next(); {
+				_, ev := it.Element()
+				elems = append(elems, setElementCompareValue(block.Schema, ev))
+			}
+			if attr.Nesting == NestingSet {
+				attrs[name] = cty.SetVal(elems)
+			} else {
+				attrs[name] = cty.ListVal(elems)
+			}
+		case NestingMap:
+			if cv.IsNull() || !cv.IsKnown() || cv.LengthInt() == 0 {
+				attrs[name] = cv
+				continue
+			}
+			elems := make(map[string]cty.Value)
+			for it := cv.ElementIterator(); it.Next(); {
+				kv, ev := it.Element()
+				elems[kv.AsString()] = setElementCompareValue(block.Schema, ev)
+			}
+			attrs[name] = cty.MapVal(elems)
+		default:
+			attrs[name] = setElementCompareValue(block.Schema, cv)
+		}
+	}
+	return cty.ObjectVal(attrs)
+}
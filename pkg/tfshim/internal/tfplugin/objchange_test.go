@@ -0,0 +1,261 @@
+package tfplugin
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nestedBlock(name string, nesting NestingMode) *NestedBlock {
+	return &NestedBlock{
+		TypeName: name,
+		Nesting:  nesting,
+		Block: &Block{
+			Attributes: []*Attribute{
+				{Name: "name", Type: []byte(`"string"`), Optional: true},
+				{Name: "size", Type: []byte(`"number"`), Optional: true, Computed: true},
+				{Name: "id", Type: []byte(`"string"`), Computed: true},
+			},
+		},
+	}
+}
+
+func TestProposedNew(t *testing.T) {
+	r, err := unmarshalResource(nil, "test", &Schema{
+		Block: &Block{
+			Attributes: []*Attribute{
+				{Name: "foo", Type: []byte(`"string"`), Optional: true},
+				{Name: "bar", Type: []byte(`"string"`), Optional: true, Computed: true},
+				{Name: "baz", Type: []byte(`"string"`), Computed: true},
+			},
+			BlockTypes: []*NestedBlock{
+				nestedBlock("single", NestingSingle),
+				nestedBlock("list", NestingList),
+				nestedBlock("set", NestingSet),
+				nestedBlock("map", NestingMap),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	blockType := objectResource(attributeSchema(r.Schema["single"])).CtyType
+	block := func(name, size, id cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"name": name, "size": size, "id": id})
+	}
+	object := func(foo, bar, baz, single, list, set, m cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"foo":    foo,
+			"bar":    bar,
+			"baz":    baz,
+			"single": single,
+			"list":   list,
+			"set":    set,
+			"map":    m,
+		})
+	}
+	nullString, nullNumber, nullBlock := cty.NullVal(cty.String), cty.NullVal(cty.Number), cty.NullVal(blockType)
+	emptyList, emptySet, emptyMap := cty.ListValEmpty(blockType), cty.SetValEmpty(blockType), cty.MapValEmpty(blockType)
+
+	tests := []struct {
+		name     string
+		prior    cty.Value
+		config   cty.Value
+		expected cty.Value
+	}{
+		{
+			name:     "both null",
+			prior:    cty.NullVal(r.CtyType),
+			config:   cty.NullVal(r.CtyType),
+			expected: cty.NullVal(r.CtyType),
+		},
+		{
+			name:   "no prior",
+			prior:  cty.NullVal(r.CtyType),
+			config: object(cty.StringVal("hello"), nullString, nullString, nullBlock, emptyList, emptySet, emptyMap),
+			expected: object(cty.StringVal("hello"), nullString, nullString, nullBlock, emptyList, emptySet,
+				emptyMap),
+		},
+		{
+			name: "prior computed values are preserved",
+			prior: object(cty.StringVal("hello"), cty.StringVal("computed"), cty.StringVal("also computed"),
+				nullBlock, emptyList, emptySet, emptyMap),
+			config: object(cty.StringVal("world"), nullString, nullString, nullBlock, emptyList, emptySet, emptyMap),
+			expected: object(cty.StringVal("world"), cty.StringVal("computed"), cty.StringVal("also computed"),
+				nullBlock, emptyList, emptySet, emptyMap),
+		},
+		{
+			name: "optional+computed values are overridden by config",
+			prior: object(nullString, cty.StringVal("computed"), cty.StringVal("also computed"), nullBlock,
+				emptyList, emptySet, emptyMap),
+			config: object(nullString, cty.StringVal("configured"), nullString, nullBlock, emptyList, emptySet,
+				emptyMap),
+			expected: object(nullString, cty.StringVal("configured"), cty.StringVal("also computed"), nullBlock,
+				emptyList, emptySet, emptyMap),
+		},
+		{
+			name: "single block",
+			prior: object(nullString, nullString, nullString,
+				block(cty.StringVal("a"), cty.NumberIntVal(1), cty.StringVal("a1")), emptyList, emptySet, emptyMap),
+			config: object(nullString, nullString, nullString,
+				block(cty.StringVal("b"), nullNumber, nullString), emptyList, emptySet, emptyMap),
+			expected: object(nullString, nullString, nullString,
+				block(cty.StringVal("b"), cty.NumberIntVal(1), cty.StringVal("a1")), emptyList, emptySet, emptyMap),
+		},
+		{
+			name: "list blocks are correlated by index",
+			prior: object(nullString, nullString, nullString, nullBlock, cty.ListVal([]cty.Value{
+				block(cty.StringVal("a"), cty.NumberIntVal(1), cty.StringVal("a1")),
+			}), emptySet, emptyMap),
+			config: object(nullString, nullString, nullString, nullBlock, cty.ListVal([]cty.Value{
+				block(cty.StringVal("b"), nullNumber, nullString),
+				block(cty.StringVal("c"), cty.NumberIntVal(3), nullString),
+			}), emptySet, emptyMap),
+			expected: object(nullString, nullString, nullString, nullBlock, cty.ListVal([]cty.Value{
+				block(cty.StringVal("b"), cty.NumberIntVal(1), cty.StringVal("a1")),
+				block(cty.StringVal("c"), cty.NumberIntVal(3), nullString),
+			}), emptySet, emptyMap),
+		},
+		{
+			name: "removed list blocks are dropped",
+			prior: object(nullString, nullString, nullString, nullBlock, cty.ListVal([]cty.Value{
+				block(cty.StringVal("a"), cty.NumberIntVal(1), cty.StringVal("a1")),
+			}), emptySet, emptyMap),
+			config:   object(nullString, nullString, nullString, nullBlock, emptyList, emptySet, emptyMap),
+			expected: object(nullString, nullString, nullString, nullBlock, emptyList, emptySet, emptyMap),
+		},
+		{
+			name: "set blocks are correlated by their non-computed values",
+			prior: object(nullString, nullString, nullString, nullBlock, emptyList, cty.SetVal([]cty.Value{
+				block(cty.StringVal("a"), cty.NumberIntVal(1), cty.StringVal("a1")),
+				block(cty.StringVal("b"), cty.NumberIntVal(2), cty.StringVal("b2")),
+			}), emptyMap),
+			config: object(nullString, nullString, nullString, nullBlock, emptyList, cty.SetVal([]cty.Value{
+				block(cty.StringVal("a"), nullNumber, nullString),
+				block(cty.StringVal("c"), nullNumber, nullString),
+			}), emptyMap),
+			expected: object(nullString, nullString, nullString, nullBlock, emptyList, cty.SetVal([]cty.Value{
+				block(cty.StringVal("a"), cty.NumberIntVal(1), cty.StringVal("a1")),
+				block(cty.StringVal("c"), nullNumber, nullString),
+			}), emptyMap),
+		},
+		{
+			name: "map blocks are correlated by key",
+			prior: object(nullString, nullString, nullString, nullBlock, emptyList, emptySet, cty.MapVal(
+				map[string]cty.Value{
+					"x": block(cty.StringVal("a"), cty.NumberIntVal(1), cty.StringVal("a1")),
+					"y": block(cty.StringVal("b"), cty.NumberIntVal(2), cty.StringVal("b2")),
+				})),
+			config: object(nullString, nullString, nullString, nullBlock, emptyList, emptySet, cty.MapVal(
+				map[string]cty.Value{
+					"x": block(cty.StringVal("c"), nullNumber, nullString),
+					"z": block(cty.StringVal("d"), nullNumber, nullString),
+				})),
+			expected: object(nullString, nullString, nullString, nullBlock, emptyList, emptySet, cty.MapVal(
+				map[string]cty.Value{
+					"x": block(cty.StringVal("c"), cty.NumberIntVal(1), cty.StringVal("a1")),
+					"z": block(cty.StringVal("d"), nullNumber, nullString),
+				})),
+		},
+		{
+			name: "unknown config values are passed through",
+			prior: object(cty.StringVal("hello"), cty.StringVal("computed"), nullString, nullBlock, emptyList,
+				emptySet, emptyMap),
+			config: object(cty.UnknownVal(cty.String), cty.UnknownVal(cty.String), nullString, nullBlock,
+				emptyList, emptySet, emptyMap),
+			expected: object(cty.UnknownVal(cty.String), cty.UnknownVal(cty.String), nullString, nullBlock,
+				emptyList, emptySet, emptyMap),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := proposedNew(r.Schema, r.CtyType, tt.prior, tt.config)
+			assert.True(t, tt.expected.RawEquals(actual), "expected %#v, got %#v", tt.expected, actual)
+		})
+	}
+}
+
+func TestProposedNewNestedAttributes(t *testing.T) {
+	r, err := unmarshalResource(nil, "test", &Schema{
+		Block: &Block{
+			Attributes: []*Attribute{
+				{
+					Name:     "list",
+					Optional: true,
+					NestedType: &Object{
+						Nesting: NestingList,
+						Attributes: []*Attribute{
+							{Name: "name", Type: []byte(`"string"`), Required: true},
+							{Name: "id", Type: []byte(`"string"`), Computed: true},
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	element := func(name, id cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"name": name, "id": id})
+	}
+	object := func(elements ...cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"list": cty.ListVal(elements)})
+	}
+
+	prior := object(element(cty.StringVal("a"), cty.StringVal("a1")))
+	config := object(element(cty.StringVal("b"), cty.NullVal(cty.String)),
+		element(cty.StringVal("c"), cty.NullVal(cty.String)))
+	expected := object(element(cty.StringVal("b"), cty.StringVal("a1")),
+		element(cty.StringVal("c"), cty.NullVal(cty.String)))
+
+	actual := proposedNew(r.Schema, r.CtyType, prior, config)
+	assert.True(t, expected.RawEquals(actual), "expected %#v, got %#v", expected, actual)
+
+	// A null nested attribute is taken from config as-is.
+	config = cty.ObjectVal(map[string]cty.Value{"list": cty.NullVal(r.CtyType.AttributeType("list"))})
+	actual = proposedNew(r.Schema, r.CtyType, prior, config)
+	assert.True(t, config.RawEquals(actual), "expected %#v, got %#v", config, actual)
+}
+
+func TestProposedNewMapNestedAttribute(t *testing.T) {
+	r, err := unmarshalResource(nil, "test", &Schema{
+		Block: &Block{
+			Attributes: []*Attribute{
+				{
+					Name:     "map",
+					Optional: true,
+					NestedType: &Object{
+						Nesting: NestingMap,
+						Attributes: []*Attribute{
+							{Name: "name", Type: []byte(`"string"`), Required: true},
+							{Name: "id", Type: []byte(`"string"`), Computed: true},
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	element := func(name, id cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"name": name, "id": id})
+	}
+	object := func(elements map[string]cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"map": cty.MapVal(elements)})
+	}
+
+	// Elements are correlated by key.
+	prior := object(map[string]cty.Value{"a": element(cty.StringVal("a"), cty.StringVal("a1"))})
+	config := object(map[string]cty.Value{
+		"a": element(cty.StringVal("b"), cty.NullVal(cty.String)),
+		"c": element(cty.StringVal("c"), cty.NullVal(cty.String)),
+	})
+	expected := object(map[string]cty.Value{
+		"a": element(cty.StringVal("b"), cty.StringVal("a1")),
+		"c": element(cty.StringVal("c"), cty.NullVal(cty.String)),
+	})
+
+	actual := proposedNew(r.Schema, r.CtyType, prior, config)
+	assert.True(t, expected.RawEquals(actual), "expected %#v, got %#v", expected, actual)
+}
@@ -0,0 +1,73 @@
+package tfplugin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// privateMetaKey is the meta key under which private state that cannot be faithfully represented as a JSON object is
+// recorded, base64-encoded. Like the SDK's timeouts key, it is a UUID so that it cannot collide with the keys of a
+// provider's own private state.
+const privateMetaKey = "a6b1c2d8-3e4f-4a5b-8c6d-7e8f9a0b1c2d"
+
+// decodePrivate converts the private state returned by the provider into an instance state's meta. Private state is
+// opaque to Terraform. In practice it is usually a JSON object, which is decoded as-is so that its contents (e.g. the
+// schema version and timeouts) are visible to the bridge. Any other private state is recorded verbatim.
+func decodePrivate(private []byte) map[string]interface{} {
+	if len(private) == 0 {
+		return nil
+	}
+
+	var meta map[string]interface{}
+	if err := json.Unmarshal(private, &meta); err == nil && meta != nil && roundTrips(private, meta) {
+		if _, ok := meta[privateMetaKey]; !ok {
+			return meta
+		}
+	}
+	return map[string]interface{}{privateMetaKey: base64.StdEncoding.EncodeToString(private)}
+}
+
+// roundTrips returns true if re-encoding the given decoded private state produces a value that is equivalent to the
+// original, i.e. if decoding did not lose the precision of any numbers.
+func roundTrips(private []byte, meta map[string]interface{}) bool {
+	encoded, err := json.Marshal(meta)
+	if err != nil {
+		return false
+	}
+
+	var expected, actual interface{}
+	if err := unmarshalJSONNumbers(private, &expected); err != nil {
+		return false
+	}
+	if err := unmarshalJSONNumbers(encoded, &actual); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+// unmarshalJSONNumbers decodes JSON, representing numbers as json.Number to preserve their text.
+func unmarshalJSONNumbers(b []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// encodePrivate converts an instance state's meta into the private state to send to the provider. Private state that
+// was recorded verbatim is sent back as-is: any other meta, such as timeouts set by the bridge, was not part of it.
+func encodePrivate(meta map[string]interface{}) ([]byte, error) {
+	if meta == nil {
+		return nil, nil
+	}
+
+	if v, ok := meta[privateMetaKey]; ok {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected type %T for private state", v)
+		}
+		return base64.StdEncoding.DecodeString(s)
+	}
+	return json.Marshal(meta)
+}
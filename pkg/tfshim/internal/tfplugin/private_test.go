@@ -0,0 +1,115 @@
+package tfplugin
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+func TestPrivateRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		private string
+		meta    map[string]interface{}
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:    "json object",
+			private: `{"e2bfb730-ecaa-11e6-8f88-34363bc7c4c0":{"create":120000000000},"schema_version":"1"}`,
+			meta: map[string]interface{}{
+				timeoutsKey:      map[string]interface{}{"create": float64(1.2e11)},
+				"schema_version": "1",
+			},
+		},
+		{
+			name:    "json object resembling recorded private state",
+			private: `{"private":"AAFiaW5hcnk="}`,
+			meta:    map[string]interface{}{"private": "AAFiaW5hcnk="},
+		},
+		{
+			name:    "json object containing the private meta key",
+			private: `{"a6b1c2d8-3e4f-4a5b-8c6d-7e8f9a0b1c2d":"x"}`,
+			meta:    map[string]interface{}{privateMetaKey: "eyJhNmIxYzJkOC0zZTRmLTRhNWItOGM2ZC03ZThmOWEwYjFjMmQiOiJ4In0="},
+		},
+		{
+			name:    "imprecise number",
+			private: `{"n":9007199254740993}`,
+			meta:    map[string]interface{}{privateMetaKey: "eyJuIjo5MDA3MTk5MjU0NzQwOTkzfQ=="},
+		},
+		{
+			name:    "json array",
+			private: `[1,2]`,
+			meta:    map[string]interface{}{privateMetaKey: "WzEsMl0="},
+		},
+		{
+			name:    "not json",
+			private: "\x00\x01binary",
+			meta:    map[string]interface{}{privateMetaKey: "AAFiaW5hcnk="},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := decodePrivate([]byte(tt.private))
+			assert.Equal(t, tt.meta, meta)
+
+			// The meta survives being recorded in Pulumi state as JSON.
+			metaJSON, err := json.Marshal(meta)
+			require.NoError(t, err)
+			var stateMeta map[string]interface{}
+			require.NoError(t, json.Unmarshal(metaJSON, &stateMeta))
+
+			private, err := encodePrivate(stateMeta)
+			require.NoError(t, err)
+			switch {
+			case tt.private == "":
+				assert.Empty(t, private)
+			case tt.meta[privateMetaKey] != nil:
+				assert.Equal(t, tt.private, string(private))
+			default:
+				assert.JSONEq(t, tt.private, string(private))
+			}
+		})
+	}
+}
+
+func TestEncodePrivate(t *testing.T) {
+	// Meta created by the bridge rather than the provider is sent as JSON.
+	private, err := encodePrivate(map[string]interface{}{"schema_version": "2"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"schema_version":"2"}`, string(private))
+
+	_, err = encodePrivate(map[string]interface{}{privateMetaKey: 42})
+	assert.EqualError(t, err, "unexpected type int for private state")
+}
+
+func TestTimeoutsWithOpaquePrivate(t *testing.T) {
+	private := "\x00\x01binary"
+
+	// Timeouts set by the bridge do not disturb private state that is not a JSON object.
+	minute := time.Minute
+	diff := &instanceDiff{meta: decodePrivate([]byte(private))}
+	diff.SetTimeout(60, shim.TimeoutCreate)
+	require.NoError(t, diff.EncodeTimeouts(&shim.ResourceTimeout{Delete: &minute}))
+	assert.Contains(t, diff.meta, timeoutsKey)
+
+	encoded, err := encodePrivate(diff.meta)
+	require.NoError(t, err)
+	assert.Equal(t, private, string(encoded))
+
+	// The same holds for a JSON object whose only key resembles the recorded form of opaque private state.
+	private = `{"private":"AAFiaW5hcnk="}`
+	diff = &instanceDiff{meta: decodePrivate([]byte(private))}
+	diff.SetTimeout(60, shim.TimeoutCreate)
+
+	encoded, err = encodePrivate(diff.meta)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"private":"AAFiaW5hcnk=","e2bfb730-ecaa-11e6-8f88-34363bc7c4c0":{"create":60000000000}}`,
+		string(encoded))
+}
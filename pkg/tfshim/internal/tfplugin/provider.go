@@ -0,0 +1,601 @@
+package tfplugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+)
+
+type provider struct {
+	client           Client
+	terraformVersion string
+
+	resources   resourceMap
+	dataSources resourceMap
+	config      *Resource
+
+	// providerMeta is the schema of the provider's provider_meta block, if it has one.
+	providerMeta *Resource
+	// providerMetaObject and providerMetaValue hold the provider_meta value sent with each resource operation.
+	providerMetaObject map[string]interface{}
+	providerMetaValue  []byte
+}
+
+var _ = shim.ProviderWithMeta((*provider)(nil))
+
+// NewProvider returns a shim.Provider that drives the given plugin client.
+func NewProvider(ctx context.Context, client Client, terraformVersion string) (shim.Provider, error) {
+	schemaResponse, err := client.GetProviderSchema(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving schema: %w", err)
+	}
+
+	// Default to reporting 0.13.2.
+	if terraformVersion == "" {
+		terraformVersion = "0.13.2"
+	}
+
+	p := &provider{
+		client:           client,
+		terraformVersion: terraformVersion,
+	}
+
+	p.resources, err = unmarshalResourceMap(p, schemaResponse.ResourceSchemas)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling resources: %w", err)
+	}
+
+	p.dataSources, err = unmarshalResourceMap(p, schemaResponse.DataSourceSchemas)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling data sources: %w", err)
+	}
+
+	p.config, err = unmarshalResource(p, "", schemaResponse.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling provider config: %w", err)
+	}
+
+	if schemaResponse.ProviderMeta != nil {
+		p.providerMeta, err = unmarshalResource(p, "", schemaResponse.ProviderMeta)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshaling provider meta: %w", err)
+		}
+	}
+
+	return p, nil
+}
+
+// SetProviderMeta sets the provider_meta value that is sent to the provider with each resource and data source
+// operation.
+func (p *provider) SetProviderMeta(meta map[string]interface{}) error {
+	if meta == nil {
+		p.providerMetaObject, p.providerMetaValue = nil, nil
+		return nil
+	}
+	if p.providerMeta == nil {
+		return fmt.Errorf("provider does not accept provider_meta")
+	}
+
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !p.providerMeta.CtyType.HasAttribute(k) {
+			return fmt.Errorf("provider_meta has no attribute %q", k)
+		}
+	}
+
+	val, err := goToCty(meta, p.providerMeta.CtyType)
+	if err != nil {
+		return err
+	}
+	bytes, err := msgpack.Marshal(val, p.providerMeta.CtyType)
+	if err != nil {
+		return err
+	}
+	p.providerMetaObject, p.providerMetaValue = meta, bytes
+	return nil
+}
+
+func (p *provider) decodeState(resource *Resource, s *instanceState,
+	val cty.Value, meta map[string]interface{}) (shim.InstanceState, error) {
+
+	if !val.Type().IsObjectType() || !val.IsKnown() {
+		return nil, fmt.Errorf("internal error: state is not an object or is unknown")
+	}
+
+	if val.IsNull() && s == nil {
+		return nil, nil
+	}
+
+	if s == nil {
+		s = &instanceState{resourceType: resource.ResourceType}
+	}
+
+	if val.IsNull() {
+		s.id = ""
+		s.object = nil
+		return s, nil
+	}
+
+	valueMap := val.AsValueMap()
+	if idVal := valueMap["id"]; idVal.Type() == cty.String && !idVal.IsNull() && idVal.IsKnown() {
+		s.id = idVal.AsString()
+	}
+
+	object, err := ctyToGo(val)
+	if err != nil {
+		return nil, err
+	}
+	s.object, s.meta = object.(map[string]interface{}), meta
+	return s, nil
+}
+
+func (p *provider) upgradeResourceState(resource *Resource, s *instanceState) (*instanceState, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	schemaVersion := int64(0)
+	if schemaVersionValue, ok := s.meta["schema_version"]; ok {
+		if schemaVersionString, ok := schemaVersionValue.(string); ok {
+			sv, err := strconv.ParseInt(schemaVersionString, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse schema version: %v", err)
+			}
+			schemaVersion = sv
+		}
+	}
+
+	stateBytes, err := json.Marshal(s.object)
+	if err != nil {
+		return nil, err
+	}
+
+	upgradedState, diags, err := p.client.UpgradeResourceState(context.TODO(), resource.ResourceType, schemaVersion,
+		stateBytes)
+	if err != nil {
+		return nil, err
+	}
+	if err = diags.Err(); err != nil {
+		return nil, err
+	}
+
+	upgradedVal, err := msgpack.Unmarshal(upgradedState, resource.CtyType)
+	if err != nil {
+		return nil, err
+	}
+
+	upgradedShim, err := p.decodeState(resource, s, upgradedVal, s.meta)
+	upgraded, _ := upgradedShim.(*instanceState)
+	return upgraded, err
+}
+
+func (p *provider) importResourceState(t, id string, _ interface{}) ([]shim.InstanceState, error) {
+	importedResources, err := p.client.ImportResourceState(context.TODO(), t, id)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]shim.InstanceState, len(importedResources))
+	for i, importedResource := range importedResources {
+		resource, ok := p.resources[importedResource.TypeName]
+		if !ok {
+			return nil, fmt.Errorf("unknown resource type %v", importedResource.TypeName)
+		}
+
+		stateVal, err := msgpack.Unmarshal(importedResource.State, resource.CtyType)
+		if err != nil {
+			return nil, err
+		}
+
+		states[i], err = p.decodeState(resource, nil, stateVal, decodePrivate(importedResource.Private))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return states, nil
+}
+
+func (p *provider) Schema() shim.SchemaMap {
+	return p.config.Schema
+}
+
+func (p *provider) ResourcesMap() shim.ResourceMap {
+	return p.resources
+}
+
+func (p *provider) DataSourcesMap() shim.ResourceMap {
+	return p.dataSources
+}
+
+func (p *provider) Validate(c shim.ResourceConfig) (diagnostics.Diagnostics, []error) {
+	config, ok := c.(resourceConfig)
+	if !ok {
+		return nil, []error{fmt.Errorf("internal error: foreign resource config")}
+	}
+
+	val, err := config.marshal(p.config.CtyType)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	diags, err := p.client.ValidateProviderConfig(context.TODO(), val)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return diags.Split()
+}
+
+func (p *provider) ValidateResource(t string, c shim.ResourceConfig) (diagnostics.Diagnostics, []error) {
+	config, ok := c.(resourceConfig)
+	if !ok {
+		return nil, []error{fmt.Errorf("internal error: foreign resource config")}
+	}
+
+	resource, ok := p.resources[t]
+	if !ok {
+		return nil, []error{fmt.Errorf("unknown resource type %v", t)}
+	}
+
+	val, err := config.marshal(resource.CtyType)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	diags, err := p.client.ValidateResourceConfig(context.TODO(), t, val)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return diags.Split()
+}
+
+func (p *provider) ValidateDataSource(t string, c shim.ResourceConfig) (diagnostics.Diagnostics, []error) {
+	config, ok := c.(resourceConfig)
+	if !ok {
+		return nil, []error{fmt.Errorf("internal error: foreign resource config")}
+	}
+
+	dataSource, ok := p.dataSources[t]
+	if !ok {
+		return nil, []error{fmt.Errorf("unknown data source %v", t)}
+	}
+
+	val, err := config.marshal(dataSource.CtyType)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	diags, err := p.client.ValidateDataResourceConfig(context.TODO(), t, val)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return diags.Split()
+}
+
+func (p *provider) Configure(c shim.ResourceConfig) (diagnostics.Diagnostics, error) {
+	config, ok := c.(resourceConfig)
+	if !ok {
+		return nil, fmt.Errorf("internal error: foreign resource config")
+	}
+
+	val, err := config.marshal(p.config.CtyType)
+	if err != nil {
+		return nil, err
+	}
+
+	diags, err := p.client.ConfigureProvider(context.TODO(), p.terraformVersion, val)
+	if err != nil {
+		return nil, err
+	}
+
+	return diags.WarningsAndErr()
+}
+
+func (p *provider) Diff(t string, s shim.InstanceState,
+	c shim.ResourceConfig) (shim.InstanceDiff, diagnostics.Diagnostics, error) {
+
+	state, ok := s.(*instanceState)
+	if s != nil && !ok {
+		return nil, nil, fmt.Errorf("internal error: foreign resource state")
+	}
+	config, ok := c.(resourceConfig)
+	if !ok {
+		return nil, nil, fmt.Errorf("internal error: foreign resource config")
+	}
+
+	resource, ok := p.resources[t]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown resource type %v", t)
+	}
+
+	state, err := p.upgradeResourceState(resource, state)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stateVal, err := goToCty(state.getObject(), resource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+	configVal, err := goToCty(config, resource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stateBytes, err := msgpack.Marshal(stateVal, resource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+	var metaBytes []byte
+	if state != nil {
+		if metaBytes, err = encodePrivate(state.meta); err != nil {
+			return nil, nil, err
+		}
+	}
+	configBytes, err := msgpack.Marshal(configVal, resource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+	proposedVal := proposedNew(resource.Schema, resource.CtyType, stateVal, configVal)
+	proposedBytes, err := msgpack.Marshal(proposedVal, resource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := p.client.PlanResourceChange(context.TODO(), &PlanResourceChangeRequest{
+		TypeName:         resource.ResourceType,
+		PriorState:       stateBytes,
+		ProposedNewState: proposedBytes,
+		Config:           configBytes,
+		PriorPrivate:     metaBytes,
+		ProviderMeta:     p.providerMetaValue,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	warnings, err := resp.Diagnostics.WarningsAndErr()
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	plannedVal, err := msgpack.Unmarshal(resp.PlannedState, resource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plannedMeta := decodePrivate(resp.PlannedPrivate)
+	return newInstanceDiff(configVal, stateVal, plannedVal, plannedMeta, resp.RequiresReplace), warnings, nil
+}
+
+func (p *provider) Apply(t string, s shim.InstanceState,
+	d shim.InstanceDiff) (shim.InstanceState, diagnostics.Diagnostics, error) {
+
+	state, ok := s.(*instanceState)
+	if s != nil && !ok {
+		return nil, nil, fmt.Errorf("internal error: foreign resource state")
+	}
+	diff, ok := d.(*instanceDiff)
+	if !ok {
+		return nil, nil, fmt.Errorf("internal error: foreign instance diff")
+	}
+
+	resource, ok := p.resources[t]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown resource type %v", t)
+	}
+
+	state, err := p.upgradeResourceState(resource, state)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stateBytes, err := state.marshal(resource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if diff.planned == (cty.Value{}) {
+		diff.planned = cty.NullVal(resource.CtyType)
+	}
+	plannedStateBytes, err := msgpack.Marshal(diff.planned, resource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+	plannedMetaBytes, err := encodePrivate(diff.meta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if diff.config == (cty.Value{}) {
+		diff.config = cty.NullVal(resource.CtyType)
+	}
+	configBytes, err := msgpack.Marshal(diff.config, resource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := p.client.ApplyResourceChange(context.TODO(), &ApplyResourceChangeRequest{
+		TypeName:       resource.ResourceType,
+		PriorState:     stateBytes,
+		PlannedState:   plannedStateBytes,
+		Config:         configBytes,
+		PlannedPrivate: plannedMetaBytes,
+		ProviderMeta:   p.providerMetaValue,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newStateVal, err := msgpack.Unmarshal(resp.NewState, resource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newState, err := p.decodeState(resource, state, newStateVal, decodePrivate(resp.Private))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	warnings, err := resp.Diagnostics.WarningsAndErr()
+	return newState, warnings, err
+}
+
+func (p *provider) Refresh(t string, s shim.InstanceState) (shim.InstanceState, diagnostics.Diagnostics, error) {
+	state, ok := s.(*instanceState)
+	if s != nil && !ok {
+		return nil, nil, fmt.Errorf("internal error: foreign resource state")
+	}
+
+	resource, ok := p.resources[t]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown resource type %v", t)
+	}
+
+	state, err := p.upgradeResourceState(resource, state)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stateBytes, err := state.marshal(resource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+	metaBytes, err := encodePrivate(state.meta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := p.client.ReadResource(context.TODO(), &ReadResourceRequest{
+		TypeName:     resource.ResourceType,
+		CurrentState: stateBytes,
+		Private:      metaBytes,
+		ProviderMeta: p.providerMetaValue,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newStateVal, err := msgpack.Unmarshal(resp.NewState, resource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newState, err := p.decodeState(resource, state, newStateVal, decodePrivate(resp.Private))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	warnings, err := resp.Diagnostics.WarningsAndErr()
+	return newState, warnings, err
+}
+
+func (p *provider) ReadDataDiff(t string, c shim.ResourceConfig) (shim.InstanceDiff, diagnostics.Diagnostics, error) {
+	dataSource, ok := p.dataSources[t]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown data source %v", t)
+	}
+
+	planned, err := goToCty(c, dataSource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &instanceDiff{planned: planned}, nil, nil
+}
+
+func (p *provider) ReadDataApply(t string, d shim.InstanceDiff) (shim.InstanceState, diagnostics.Diagnostics, error) {
+	diff, ok := d.(*instanceDiff)
+	if d != nil && !ok {
+		return nil, nil, fmt.Errorf("internal error: foreign instance diff")
+	}
+
+	dataSource, ok := p.dataSources[t]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown data source %v", t)
+	}
+
+	configBytes, err := msgpack.Marshal(diff.planned, dataSource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := p.client.ReadDataSource(context.TODO(), &ReadDataSourceRequest{
+		TypeName:     t,
+		Config:       configBytes,
+		ProviderMeta: p.providerMetaValue,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	warnings, err := resp.Diagnostics.WarningsAndErr()
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	stateVal, err := msgpack.Unmarshal(resp.State, dataSource.CtyType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	state, err := p.decodeState(dataSource, nil, stateVal, nil)
+	return state, warnings, err
+}
+
+func (p *provider) Meta() interface{} {
+	if p.providerMetaObject == nil {
+		return nil
+	}
+	return p.providerMetaObject
+}
+
+func (p *provider) Stop() error {
+	return p.client.StopProvider(context.TODO())
+}
+
+func (p *provider) InitLogging() {
+	// Nothing to do.
+}
+
+func (p *provider) NewDestroyDiff() shim.InstanceDiff {
+	return &instanceDiff{destroy: true}
+}
+
+func (p *provider) NewResourceConfig(object map[string]interface{}) shim.ResourceConfig {
+	return resourceConfig(object)
+}
+
+func (p *provider) IsSet(v interface{}) ([]interface{}, bool) {
+	val, ok := v.(cty.Value)
+	if !ok {
+		return nil, false
+	}
+	if !val.Type().IsSetType() {
+		return nil, false
+	}
+
+	result := make([]interface{}, 0, val.LengthInt())
+	iter := val.ElementIterator()
+	for iter.Next() {
+		v, _ := iter.Element()
+		gv, err := ctyToGo(v)
+		if err != nil {
+			// NOTE: this might be worthy of a panic.
+			return nil, false
+		}
+		result = append(result, gv)
+	}
+	return result, true
+}
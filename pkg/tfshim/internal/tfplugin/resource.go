@@ -1,57 +1,69 @@
-package tfplugin5
+package tfplugin
 
 import (
 	"fmt"
 	"time"
 
 	"github.com/hashicorp/go-cty/cty"
+
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 )
 
-var _ = shim.Resource((*resource)(nil))
+var _ = shim.Resource(ResourceShim{})
 var _ = shim.ResourceMap(resourceMap{})
 
-type resource struct {
+// Resource is the schema of a resource, a data source, the provider's configuration or an object nested within one of
+// these.
+type Resource struct {
+	ResourceType  string
+	CtyType       cty.Type
+	Schema        schema.SchemaMap
+	SchemaVersion int
+
 	provider *provider
+}
+
+func (r *Resource) Shim() shim.Resource {
+	return ResourceShim{r}
+}
 
-	resourceType  string
-	ctyType       cty.Type
-	schema        schema.SchemaMap
-	schemaVersion int
+//nolint: golint
+type ResourceShim struct {
+	V *Resource
 }
 
-func (r *resource) Schema() shim.SchemaMap {
-	return r.schema
+func (r ResourceShim) Schema() shim.SchemaMap {
+	return r.V.Schema
 }
 
-func (r *resource) SchemaVersion() int {
-	return r.schemaVersion
+func (r ResourceShim) SchemaVersion() int {
+	return r.V.SchemaVersion
 }
 
 //nolint: staticcheck
-func (r *resource) Importer() shim.ImportFunc {
-	if r.provider == nil {
+func (r ResourceShim) Importer() shim.ImportFunc {
+	if r.V.provider == nil {
 		return nil
 	}
-	return r.provider.importResourceState
+	return r.V.provider.importResourceState
 }
 
-func (r *resource) DeprecationMessage() string {
+func (r ResourceShim) DeprecationMessage() string {
 	return ""
 }
 
-func (r *resource) Timeouts() *shim.ResourceTimeout {
+func (r ResourceShim) Timeouts() *shim.ResourceTimeout {
 	return &shim.ResourceTimeout{}
 }
 
-func (r *resource) InstanceState(id string, object, meta map[string]interface{}) (shim.InstanceState, error) {
+func (r ResourceShim) InstanceState(id string, object, meta map[string]interface{}) (shim.InstanceState, error) {
 	// Stamp the ID into the object.
 	object["id"] = id
 
 	// Return an instance state.
 	return &instanceState{
-		resourceType: r.resourceType,
+		resourceType: r.V.ResourceType,
 		id:           id,
 		object:       object,
 		meta:         meta,
@@ -88,7 +100,7 @@ func parseTimeout(timeouts *shim.ResourceTimeout, timeoutsMap map[string]interfa
 	return nil
 }
 
-func (r *resource) DecodeTimeouts(c shim.ResourceConfig) (*shim.ResourceTimeout, error) {
+func (r ResourceShim) DecodeTimeouts(c shim.ResourceConfig) (*shim.ResourceTimeout, error) {
 	config, ok := c.(resourceConfig)
 	if !ok {
 		return nil, fmt.Errorf("internal error: foreign resource config")
@@ -112,7 +124,7 @@ func (r *resource) DecodeTimeouts(c shim.ResourceConfig) (*shim.ResourceTimeout,
 	return timeouts, nil
 }
 
-type resourceMap map[string]*resource
+type resourceMap map[string]*Resource
 
 func (m resourceMap) Len() int {
 	return len(m)
@@ -125,19 +137,19 @@ func (m resourceMap) Get(key string) shim.Resource {
 
 func (m resourceMap) GetOk(key string) (shim.Resource, bool) {
 	if r, ok := m[key]; ok {
-		return r, true
+		return r.Shim(), true
 	}
 	return nil, false
 }
 
 func (m resourceMap) Range(each func(key string, value shim.Resource) bool) {
 	for key, value := range m {
-		if !each(key, value) {
+		if !each(key, value.Shim()) {
 			return
 		}
 	}
 }
 
 func (m resourceMap) Set(key string, value shim.Resource) {
-	m[key] = value.(*resource)
+	m[key] = value.(ResourceShim).V
 }
@@ -0,0 +1,172 @@
+package tfplugin
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// UnknownVariableValue is the sentinal defined in github.com/hashicorp/terraform/configs/hcl2shim,
+// representing a variable whose value is not known at some particular time. The value is duplicated here in
+// order to prevent an additional dependency - it is unlikely to ever change upstream since that would break
+// rather a lot of things.
+const UnknownVariableValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+// NestingMode is the nesting mode of a nested block or of a nested attribute.
+type NestingMode int
+
+const (
+	NestingInvalid NestingMode = iota
+	NestingSingle
+	NestingGroup
+	NestingList
+	NestingSet
+	NestingMap
+)
+
+// AttributeSchema is the schema of an attribute or nested block of a resource.
+type AttributeSchema struct {
+	CtyType     cty.Type
+	ValueType   shim.ValueType
+	Optional    bool
+	Required    bool
+	Description string
+	Computed    bool
+	ForceNew    bool
+	Elem        interface{}
+	MaxItems    int
+	MinItems    int
+	Deprecated  string
+	Sensitive   bool
+
+	// Nesting is the nesting mode of a nested block, or NestingInvalid if the schema describes an attribute.
+	Nesting NestingMode
+	// ObjectNesting is the nesting mode of a nested attribute, or NestingInvalid if the attribute has a plain type.
+	ObjectNesting NestingMode
+}
+
+func (s *AttributeSchema) Shim() shim.Schema {
+	return AttributeSchemaShim{s}
+}
+
+//nolint: golint
+type AttributeSchemaShim struct {
+	V *AttributeSchema
+}
+
+func (s AttributeSchemaShim) Type() shim.ValueType {
+	return s.V.ValueType
+}
+
+func (s AttributeSchemaShim) Optional() bool {
+	return s.V.Optional
+}
+
+func (s AttributeSchemaShim) Required() bool {
+	return s.V.Required
+}
+
+func (s AttributeSchemaShim) Default() interface{} {
+	return nil
+}
+
+func (s AttributeSchemaShim) DefaultFunc() shim.SchemaDefaultFunc {
+	return nil
+}
+
+func (s AttributeSchemaShim) DefaultValue() (interface{}, error) {
+	return nil, nil
+}
+
+func (s AttributeSchemaShim) Description() string {
+	return s.V.Description
+}
+
+func (s AttributeSchemaShim) Computed() bool {
+	return s.V.Computed
+}
+
+func (s AttributeSchemaShim) ForceNew() bool {
+	return s.V.ForceNew
+}
+
+func (s AttributeSchemaShim) StateFunc() shim.SchemaStateFunc {
+	return nil
+}
+
+func (s AttributeSchemaShim) Elem() interface{} {
+	return s.V.Elem
+}
+
+func (s AttributeSchemaShim) MaxItems() int {
+	return s.V.MaxItems
+}
+
+func (s AttributeSchemaShim) MinItems() int {
+	return s.V.MinItems
+}
+
+func (s AttributeSchemaShim) ConflictsWith() []string {
+	return nil
+}
+
+func (s AttributeSchemaShim) ExactlyOneOf() []string {
+	return nil
+}
+
+func (s AttributeSchemaShim) AtLeastOneOf() []string {
+	return nil
+}
+
+func (s AttributeSchemaShim) RequiredWith() []string {
+	return nil
+}
+
+func (s AttributeSchemaShim) Validation() *shim.SchemaValidation {
+	return nil
+}
+
+func (s AttributeSchemaShim) Removed() string {
+	return ""
+}
+
+func (s AttributeSchemaShim) Deprecated() string {
+	return s.V.Deprecated
+}
+
+func (s AttributeSchemaShim) Sensitive() bool {
+	return s.V.Sensitive
+}
+
+func (s AttributeSchemaShim) UnknownValue() interface{} {
+	return UnknownVariableValue
+}
+
+func (s AttributeSchemaShim) SetElement(v interface{}) (interface{}, error) {
+	val, err := goToCty(v, s.V.CtyType)
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+func (s AttributeSchemaShim) SetHash(v interface{}) int {
+	val, ok := v.(cty.Value)
+	contract.Assertf(ok, "internal error: SetHash must be a cty.Value")
+	return val.Hash()
+}
+
+// attributeSchema returns the attribute schema underlying the given schema, which must be one of this package's.
+func attributeSchema(s shim.Schema) *AttributeSchema {
+	return s.(AttributeSchemaShim).V
+}
+
+// objectResource returns the object type of a nested block or nested attribute.
+func objectResource(s *AttributeSchema) *Resource {
+	if elem, ok := s.Elem.(AttributeSchemaShim); ok {
+		// The elements of a map nested attribute are objects.
+		return elem.V.Elem.(ResourceShim).V
+	}
+	return s.Elem.(ResourceShim).V
+}
@@ -2,6 +2,7 @@ package schema
 
 import (
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
 )
 
 type Provider struct {
@@ -30,39 +31,43 @@ func (s ProviderShim) DataSourcesMap() shim.ResourceMap {
 	return s.V.DataSourcesMap
 }
 
-func (ProviderShim) Validate(c shim.ResourceConfig) ([]string, []error) {
+func (ProviderShim) Validate(c shim.ResourceConfig) (diagnostics.Diagnostics, []error) {
 	panic("this provider is schema-only and does not support runtime operations")
 }
 
-func (ProviderShim) ValidateResource(t string, c shim.ResourceConfig) ([]string, []error) {
+func (ProviderShim) ValidateResource(t string, c shim.ResourceConfig) (diagnostics.Diagnostics, []error) {
 	panic("this provider is schema-only and does not support runtime operations")
 }
 
-func (ProviderShim) ValidateDataSource(t string, c shim.ResourceConfig) ([]string, []error) {
+func (ProviderShim) ValidateDataSource(t string, c shim.ResourceConfig) (diagnostics.Diagnostics, []error) {
 	panic("this provider is schema-only and does not support runtime operations")
 }
 
-func (ProviderShim) Configure(c shim.ResourceConfig) error {
+func (ProviderShim) Configure(c shim.ResourceConfig) (diagnostics.Diagnostics, error) {
 	panic("this provider is schema-only and does not support runtime operations")
 }
 
-func (ProviderShim) Diff(t string, s shim.InstanceState, c shim.ResourceConfig) (shim.InstanceDiff, error) {
+func (ProviderShim) Diff(t string, s shim.InstanceState,
+	c shim.ResourceConfig) (shim.InstanceDiff, diagnostics.Diagnostics, error) {
+
 	panic("this provider is schema-only and does not support runtime operations")
 }
 
-func (ProviderShim) Apply(t string, s shim.InstanceState, d shim.InstanceDiff) (shim.InstanceState, error) {
+func (ProviderShim) Apply(t string, s shim.InstanceState,
+	d shim.InstanceDiff) (shim.InstanceState, diagnostics.Diagnostics, error) {
+
 	panic("this provider is schema-only and does not support runtime operations")
 }
 
-func (ProviderShim) Refresh(t string, s shim.InstanceState) (shim.InstanceState, error) {
+func (ProviderShim) Refresh(t string, s shim.InstanceState) (shim.InstanceState, diagnostics.Diagnostics, error) {
 	panic("this provider is schema-only and does not support runtime operations")
 }
 
-func (ProviderShim) ReadDataDiff(t string, c shim.ResourceConfig) (shim.InstanceDiff, error) {
+func (ProviderShim) ReadDataDiff(t string, c shim.ResourceConfig) (shim.InstanceDiff, diagnostics.Diagnostics, error) {
 	panic("this provider is schema-only and does not support runtime operations")
 }
 
-func (ProviderShim) ReadDataApply(t string, d shim.InstanceDiff) (shim.InstanceState, error) {
+func (ProviderShim) ReadDataApply(t string, d shim.InstanceDiff) (shim.InstanceState, diagnostics.Diagnostics, error) {
 	panic("this provider is schema-only and does not support runtime operations")
 }
 
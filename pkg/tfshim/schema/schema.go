@@ -26,6 +26,10 @@ type Schema struct {
 	MaxItems      int
 	MinItems      int
 	ConflictsWith []string
+	ExactlyOneOf  []string
+	AtLeastOneOf  []string
+	RequiredWith  []string
+	Validation    *shim.SchemaValidation
 	Removed       string
 	Deprecated    string
 	Sensitive     bool
@@ -108,6 +112,22 @@ func (s SchemaShim) ConflictsWith() []string {
 	return s.V.ConflictsWith
 }
 
+func (s SchemaShim) ExactlyOneOf() []string {
+	return s.V.ExactlyOneOf
+}
+
+func (s SchemaShim) AtLeastOneOf() []string {
+	return s.V.AtLeastOneOf
+}
+
+func (s SchemaShim) RequiredWith() []string {
+	return s.V.RequiredWith
+}
+
+func (s SchemaShim) Validation() *shim.SchemaValidation {
+	return s.V.Validation
+}
+
 func (s SchemaShim) Removed() string {
 	return s.V.Removed
 }
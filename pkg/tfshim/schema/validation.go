@@ -0,0 +1,234 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// ValidateFunc validates a single value, returning any errors it encounters.
+type ValidateFunc func(v interface{}) []error
+
+// probeKey is the attribute name passed to validators while probing them. It contains no spaces so that it cannot
+// confuse the parsing of the validators' error messages.
+const probeKey = "value"
+
+var (
+	oneOfPattern   = regexp.MustCompile(`^expected ` + probeKey + ` to be one of \[(.*)\], got `)
+	rangePattern   = regexp.MustCompile(`^expected ` + probeKey + ` to be in the range \((\S+) - (\S+)\), got `)
+	atLeastPattern = regexp.MustCompile(`^expected ` + probeKey + ` to be at least \((\S+)\), got `)
+	atMostPattern  = regexp.MustCompile(`^expected ` + probeKey + ` to be at most \((\S+)\), got `)
+)
+
+// recognizedValidators matches the names of the closures returned by the SDK's helper/validation functions whose
+// error messages ProbeValidation knows how to parse.
+var recognizedValidators = regexp.MustCompile(`/helper/validation\.` +
+	`(StringInSlice|IntBetween|IntAtLeast|IntAtMost|FloatBetween|FloatAtLeast|FloatAtMost)\.func\d+$`)
+
+// isRecognizedValidator returns true if the given function is one of the validators matched by recognizedValidators.
+func isRecognizedValidator(validator interface{}) bool {
+	v := reflect.ValueOf(validator)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return false
+	}
+	f := runtime.FuncForPC(v.Pointer())
+	return f != nil && recognizedValidators.MatchString(f.Name())
+}
+
+// ProbeValidation attempts to recover the constraints imposed by a Terraform validation function on values of the
+// given type. Validation functions are opaque, so this works by invoking the validator with values that the SDK's
+// helper/validation functions are known to reject and parsing the resulting error messages. Any candidate constraints
+// are then checked against the validator itself, so a constraint is only reported if the validator agrees with it.
+//
+// The validator is the function as it appears in the schema, and validate calls it. Only validators that are
+// recognizably one of the SDK's helper/validation functions are probed: the error messages of other validators,
+// including those that wrap or combine the SDK's, say nothing reliable about the values they accept.
+//
+// ProbeValidation returns nil if no constraints can be determined.
+func ProbeValidation(validator interface{}, validate ValidateFunc, typ shim.ValueType) *shim.SchemaValidation {
+	if validate == nil || !isRecognizedValidator(validator) {
+		return nil
+	}
+
+	switch typ {
+	case shim.TypeString:
+		if values, ignoreCase := probeAllowedValues(validate); len(values) != 0 {
+			return &shim.SchemaValidation{AllowedValues: values, IgnoreCase: ignoreCase}
+		}
+	case shim.TypeInt:
+		maxInt := int(^uint(0) >> 1)
+		return probeRange(validate, []interface{}{-maxInt - 1, maxInt},
+			func(f float64) interface{} { return int(f) })
+	case shim.TypeFloat:
+		return probeRange(validate, []interface{}{-math.MaxFloat64, math.MaxFloat64},
+			func(f float64) interface{} { return f })
+	}
+	return nil
+}
+
+// ProbedValidation holds the result of ProbeValidation, which invokes the validator several times, for a single shim
+// schema. The zero value is ready to use.
+type ProbedValidation struct {
+	once   sync.Once
+	result *shim.SchemaValidation
+}
+
+// Get returns the result of ProbeValidation for the given validator, probing it on first use.
+func (p *ProbedValidation) Get(validator interface{}, validate ValidateFunc,
+	typ shim.ValueType) *shim.SchemaValidation {
+
+	p.once.Do(func() {
+		p.result = ProbeValidation(validator, validate, typ)
+	})
+	return p.result
+}
+
+// callValidator invokes the validator, treating a panic as a validation failure. Arbitrary validators may make
+// assumptions about the values they are passed that do not hold for probe values.
+func callValidator(validate ValidateFunc, v interface{}) (errs []error, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			errs, ok = []error{fmt.Errorf("validator panicked: %v", r)}, false
+		}
+	}()
+	errs = validate(v)
+	return errs, len(errs) == 0
+}
+
+// matchError returns the submatches of the first error that matches the given pattern, if any.
+func matchError(errs []error, pattern *regexp.Regexp) []string {
+	for _, err := range errs {
+		if m := pattern.FindStringSubmatch(err.Error()); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// otherStringProbes are values that validators which list their allowed values are not expected to accept. A validator
+// that accepts one of them, such as the union of a list of values and a pattern, does not list all of its values.
+var otherStringProbes = []string{"0", "probe", "PROBE", "probe-value", "probe_value", "probe.value"}
+
+func probeAllowedValues(validate ValidateFunc) ([]string, bool) {
+	// Validators that combine several others, such as validation.Any, report an error for each of them, so only a
+	// single error describes the complete set of allowed values.
+	errs, ok := callValidator(validate, "\x00")
+	if ok || len(errs) != 1 {
+		return nil, false
+	}
+	m := matchError(errs, oneOfPattern)
+	if m == nil {
+		return nil, false
+	}
+
+	// The values are formatted with %v, so they are separated by spaces. Lists that contain empty values or values
+	// with spaces cannot be recovered unambiguously.
+	values := strings.Fields(m[1])
+	if len(values) == 0 || strings.Join(values, " ") != m[1] {
+		return nil, false
+	}
+	allowed := make(map[string]bool)
+	for _, v := range values {
+		if _, ok := callValidator(validate, v); !ok {
+			return nil, false
+		}
+		allowed[strings.ToLower(v)] = true
+	}
+
+	// Check whether the validator ignores case, as validation.StringInSlice may.
+	ignoreCase := false
+	for _, v := range values {
+		if swapped := swapCase(v); swapped != v {
+			if _, ok := callValidator(validate, swapped); ok {
+				ignoreCase = true
+				break
+			}
+		}
+	}
+
+	for _, probe := range otherStringProbes {
+		if allowed[strings.ToLower(probe)] {
+			continue
+		}
+		if _, ok := callValidator(validate, probe); ok {
+			return nil, false
+		}
+	}
+	return values, ignoreCase
+}
+
+// swapCase returns the string with the case of each of its letters inverted.
+func swapCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsUpper(r) {
+			return unicode.ToLower(r)
+		}
+		return unicode.ToUpper(r)
+	}, s)
+}
+
+func probeRange(validate ValidateFunc, probes []interface{},
+	makeValue func(f float64) interface{}) *shim.SchemaValidation {
+
+	var min, max *float64
+	parse := func(s string) (*float64, bool) {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, false
+		}
+		return &f, true
+	}
+
+	for _, probe := range probes {
+		errs, ok := callValidator(validate, probe)
+		if ok {
+			continue
+		}
+		if m := matchError(errs, rangePattern); m != nil {
+			lo, lok := parse(m[1])
+			hi, hok := parse(m[2])
+			if !lok || !hok {
+				return nil
+			}
+			min, max = lo, hi
+		} else if m := matchError(errs, atLeastPattern); m != nil {
+			lo, ok := parse(m[1])
+			if !ok {
+				return nil
+			}
+			min = lo
+		} else if m := matchError(errs, atMostPattern); m != nil {
+			hi, ok := parse(m[1])
+			if !ok {
+				return nil
+			}
+			max = hi
+		}
+	}
+
+	if min == nil && max == nil {
+		return nil
+	}
+	if min != nil && max != nil && *min > *max {
+		return nil
+	}
+
+	// Make sure that the validator accepts the bounds we have found.
+	for _, bound := range []*float64{min, max} {
+		if bound == nil {
+			continue
+		}
+		if _, ok := callValidator(validate, makeValue(*bound)); !ok {
+			return nil
+		}
+	}
+	return &shim.SchemaValidation{Min: min, Max: max}
+}
@@ -0,0 +1,233 @@
+package schemajson
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func deprecationMessage(name string, isDeprecated bool) string {
+	if isDeprecated {
+		return fmt.Sprintf("%v is deprecated", name)
+	}
+	return ""
+}
+
+// mapOfObjects returns the element schema of a map whose elements are objects with the given schema. The element is a
+// TypeMap schema whose elem is the object, which distinguishes a map of objects from a single object.
+func mapOfObjects(object shim.Resource) shim.Schema {
+	return (&schema.Schema{Type: shim.TypeMap, Elem: object}).Shim()
+}
+
+// unmarshalElem returns the element schema for a collection whose elements have the given type. Object-typed elements
+// are represented as resources, all other elements as schemas.
+func unmarshalElem(ty cty.Type) (interface{}, error) {
+	valueType, elem, err := unmarshalType(ty)
+	if err != nil {
+		return nil, err
+	}
+	if ty.IsObjectType() {
+		return elem, nil
+	}
+	return (&schema.Schema{Type: valueType, Elem: elem}).Shim(), nil
+}
+
+// unmarshalType returns the value type and element of the given type. Tuple types have no equivalent in the shim
+// schema, so attributes of tuple type are rejected.
+func unmarshalType(ty cty.Type) (shim.ValueType, interface{}, error) {
+	switch {
+	case ty == cty.String:
+		return shim.TypeString, nil, nil
+	case ty == cty.Bool:
+		return shim.TypeBool, nil, nil
+	case ty == cty.Number:
+		return shim.TypeFloat, nil, nil
+	case ty == cty.DynamicPseudoType:
+		return shim.TypeDynamic, nil, nil
+	case ty.IsListType():
+		elem, err := unmarshalElem(ty.ElementType())
+		return shim.TypeList, elem, err
+	case ty.IsSetType():
+		elem, err := unmarshalElem(ty.ElementType())
+		return shim.TypeSet, elem, err
+	case ty.IsMapType():
+		elem, err := unmarshalElem(ty.ElementType())
+		if object, ok := elem.(shim.Resource); ok {
+			elem = mapOfObjects(object)
+		}
+		return shim.TypeMap, elem, err
+	case ty.IsObjectType():
+		properties := schema.SchemaMap{}
+		for name, ty := range ty.AttributeTypes() {
+			valueType, elem, err := unmarshalType(ty)
+			if err != nil {
+				return shim.TypeInvalid, nil, err
+			}
+			properties[name] = (&schema.Schema{Type: valueType, Elem: elem}).Shim()
+		}
+		return shim.TypeMap, (&schema.Resource{Schema: properties}).Shim(), nil
+	case ty.IsTupleType():
+		return shim.TypeInvalid, nil, fmt.Errorf("tuple types are not supported")
+	default:
+		return shim.TypeInvalid, nil, fmt.Errorf("unexpected type %v", ty.FriendlyName())
+	}
+}
+
+func unmarshalNestedType(nestedType *NestedType) (shim.ValueType, interface{}, error) {
+	properties := schema.SchemaMap{}
+	for name, attribute := range nestedType.Attributes {
+		property, err := unmarshalAttribute(name, attribute)
+		if err != nil {
+			return shim.TypeInvalid, nil, err
+		}
+		properties[name] = property
+	}
+
+	elem := (&schema.Resource{Schema: properties}).Shim()
+	switch nestedType.NestingMode {
+	case "single":
+		return shim.TypeMap, elem, nil
+	case "map":
+		return shim.TypeMap, mapOfObjects(elem), nil
+	case "list":
+		return shim.TypeList, elem, nil
+	case "set":
+		return shim.TypeSet, elem, nil
+	default:
+		return shim.TypeInvalid, nil, fmt.Errorf("unexpected nesting mode %q", nestedType.NestingMode)
+	}
+}
+
+func unmarshalAttribute(name string, attribute *Attribute) (shim.Schema, error) {
+	s := &schema.Schema{
+		Description: attribute.Description,
+		Required:    attribute.Required,
+		Optional:    attribute.Optional || !attribute.Computed && !attribute.Required,
+		Computed:    attribute.Computed,
+		Sensitive:   attribute.Sensitive,
+		Deprecated:  deprecationMessage(name, attribute.Deprecated),
+	}
+
+	var err error
+	if attribute.NestedType != nil {
+		s.Type, s.Elem, err = unmarshalNestedType(attribute.NestedType)
+		s.MinItems, s.MaxItems = attribute.NestedType.MinItems, attribute.NestedType.MaxItems
+	} else {
+		s.Type, s.Elem, err = unmarshalType(attribute.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("attribute %v: %w", name, err)
+	}
+	return s.Shim(), nil
+}
+
+func unmarshalBlock(block *Block) (schema.SchemaMap, error) {
+	properties := schema.SchemaMap{}
+	for name, attribute := range block.Attributes {
+		property, err := unmarshalAttribute(name, attribute)
+		if err != nil {
+			return nil, err
+		}
+		properties[name] = property
+	}
+	for name, nestedBlock := range block.BlockTypes {
+		property, err := unmarshalNestedBlock(name, nestedBlock)
+		if err != nil {
+			return nil, err
+		}
+		properties[name] = property
+	}
+	return properties, nil
+}
+
+// allComputed returns true if every property in the given schema is computed.
+func allComputed(properties schema.SchemaMap) bool {
+	for _, property := range properties {
+		if !property.Computed() {
+			return false
+		}
+	}
+	return true
+}
+
+func unmarshalNestedBlock(name string, nestedBlock *NestedBlock) (shim.Schema, error) {
+	block := nestedBlock.Block
+	if block == nil {
+		block = &Block{}
+	}
+	properties, err := unmarshalBlock(block)
+	if err != nil {
+		return nil, fmt.Errorf("block %v: %w", name, err)
+	}
+
+	var valueType shim.ValueType
+	object := (&schema.Resource{Schema: properties}).Shim()
+	elem := interface{}(object)
+	switch nestedBlock.NestingMode {
+	case "single", "group":
+		valueType = shim.TypeMap
+	case "map":
+		valueType, elem = shim.TypeMap, mapOfObjects(object)
+	case "list":
+		valueType = shim.TypeList
+	case "set":
+		valueType = shim.TypeSet
+	default:
+		return nil, fmt.Errorf("block %v: unexpected nesting mode %q", name, nestedBlock.NestingMode)
+	}
+
+	s := &schema.Schema{
+		Type:        valueType,
+		Elem:        elem,
+		Description: block.Description,
+		Deprecated:  deprecationMessage(name, block.Deprecated),
+		MinItems:    nestedBlock.MinItems,
+		MaxItems:    nestedBlock.MaxItems,
+	}
+	if allComputed(properties) {
+		s.Computed = true
+	} else if nestedBlock.NestingMode == "single" {
+		s.Required = true
+	} else {
+		s.Required, s.Optional = nestedBlock.MinItems > 0, nestedBlock.MinItems == 0
+	}
+	return s.Shim(), nil
+}
+
+func unmarshalResource(typeName string, resourceSchema *Schema) (shim.Resource, error) {
+	block := resourceSchema.Block
+	if block == nil {
+		block = &Block{}
+	}
+	properties, err := unmarshalBlock(block)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", typeName, err)
+	}
+
+	// Ensure that `id` is treated as a pure output property.
+	if id, ok := properties["id"]; ok {
+		s := id.(schema.SchemaShim).V
+		s.Optional, s.Required, s.Computed = false, false, true
+	}
+
+	return (&schema.Resource{
+		Schema:             properties,
+		SchemaVersion:      resourceSchema.Version,
+		DeprecationMessage: deprecationMessage(typeName, block.Deprecated),
+	}).Shim(), nil
+}
+
+func unmarshalResourceMap(resources map[string]*Schema) (schema.ResourceMap, error) {
+	resourceMap := schema.ResourceMap{}
+	for name, s := range resources {
+		r, err := unmarshalResource(name, s)
+		if err != nil {
+			return nil, err
+		}
+		resourceMap[name] = r
+	}
+	return resourceMap, nil
+}
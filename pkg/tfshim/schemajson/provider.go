@@ -0,0 +1,170 @@
+// Package schemajson implements a schema-only shim.Provider backed by the output of
+// `terraform providers schema -json`. Providers loaded this way describe their resources, data sources and
+// configuration, which is sufficient for tfgen, but do not support any runtime operations.
+package schemajson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+// ProviderSchemas is the document produced by `terraform providers schema -json`.
+type ProviderSchemas struct {
+	FormatVersion string                     `json:"format_version"`
+	Schemas       map[string]*ProviderSchema `json:"provider_schemas"`
+}
+
+// ProviderSchema describes a single provider's configuration, resources and data sources.
+type ProviderSchema struct {
+	ConfigSchema      *Schema            `json:"provider,omitempty"`
+	ResourceSchemas   map[string]*Schema `json:"resource_schemas,omitempty"`
+	DataSourceSchemas map[string]*Schema `json:"data_source_schemas,omitempty"`
+}
+
+// Schema is the schema of a provider's configuration, a resource or a data source.
+type Schema struct {
+	Version int    `json:"version"`
+	Block   *Block `json:"block,omitempty"`
+}
+
+// Block is a configuration block, which contains attributes and nested blocks.
+type Block struct {
+	Attributes  map[string]*Attribute   `json:"attributes,omitempty"`
+	BlockTypes  map[string]*NestedBlock `json:"block_types,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	Deprecated  bool                    `json:"deprecated,omitempty"`
+}
+
+// Attribute is a single attribute of a block or nested attribute type.
+type Attribute struct {
+	// Type is the attribute's type. It is unset if the attribute has a nested type.
+	Type        cty.Type    `json:"type,omitempty"`
+	NestedType  *NestedType `json:"nested_type,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Deprecated  bool        `json:"deprecated,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Optional    bool        `json:"optional,omitempty"`
+	Computed    bool        `json:"computed,omitempty"`
+	Sensitive   bool        `json:"sensitive,omitempty"`
+}
+
+// NestedType is the type of an attribute whose value is one or more objects with attributes of their own.
+type NestedType struct {
+	Attributes  map[string]*Attribute `json:"attributes,omitempty"`
+	NestingMode string                `json:"nesting_mode,omitempty"`
+	MinItems    int                   `json:"min_items,omitempty"`
+	MaxItems    int                   `json:"max_items,omitempty"`
+}
+
+// NestedBlock is a block nested within another block.
+type NestedBlock struct {
+	Block       *Block `json:"block,omitempty"`
+	NestingMode string `json:"nesting_mode,omitempty"`
+	MinItems    int    `json:"min_items,omitempty"`
+	MaxItems    int    `json:"max_items,omitempty"`
+}
+
+// ReadProviderSchemas reads the output of `terraform providers schema -json`.
+func ReadProviderSchemas(r io.Reader) (*ProviderSchemas, error) {
+	var schemas ProviderSchemas
+	if err := json.NewDecoder(r).Decode(&schemas); err != nil {
+		return nil, fmt.Errorf("failed to decode provider schemas: %w", err)
+	}
+	if !strings.HasPrefix(schemas.FormatVersion, "1.") {
+		return nil, fmt.Errorf("unsupported provider schemas format version %q", schemas.FormatVersion)
+	}
+	return &schemas, nil
+}
+
+// Provider returns the schema of the named provider. The name may be either the provider's full source address (e.g.
+// "registry.terraform.io/hashicorp/aws") or its type name (e.g. "aws"). If the name is empty and the document
+// describes a single provider, that provider's schema is returned.
+func (s *ProviderSchemas) Provider(name string) (*ProviderSchema, error) {
+	if p, ok := s.Schemas[name]; ok {
+		return p, nil
+	}
+
+	var matches []string
+	for address := range s.Schemas {
+		if name == "" || address[strings.LastIndex(address, "/")+1:] == name {
+			matches = append(matches, address)
+		}
+	}
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no schema for provider %q", name)
+	case 1:
+		return s.Schemas[matches[0]], nil
+	default:
+		return nil, fmt.Errorf("ambiguous provider name %q: could refer to any of %s", name,
+			strings.Join(matches, ", "))
+	}
+}
+
+// LoadProvider reads the output of `terraform providers schema -json` and returns a schema-only provider for the
+// named provider. See ProviderSchemas.Provider for the interpretation of the name.
+func LoadProvider(r io.Reader, name string) (shim.Provider, error) {
+	schemas, err := ReadProviderSchemas(r)
+	if err != nil {
+		return nil, err
+	}
+	p, err := schemas.Provider(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewProvider(p)
+}
+
+// LoadProviderFile is like LoadProvider, but reads the provider schemas from the file at the given path.
+func LoadProviderFile(path, name string) (shim.Provider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer contract.IgnoreClose(f)
+
+	p, err := LoadProvider(f, name)
+	if err != nil {
+		return nil, fmt.Errorf("loading %v: %w", path, err)
+	}
+	return p, nil
+}
+
+// NewProvider returns a schema-only provider for the given provider schema.
+func NewProvider(p *ProviderSchema) (shim.Provider, error) {
+	config := schema.SchemaMap{}
+	if p.ConfigSchema != nil && p.ConfigSchema.Block != nil {
+		c, err := unmarshalBlock(p.ConfigSchema.Block)
+		if err != nil {
+			return nil, fmt.Errorf("provider configuration: %w", err)
+		}
+		config = c
+	}
+
+	resources, err := unmarshalResourceMap(p.ResourceSchemas)
+	if err != nil {
+		return nil, err
+	}
+	dataSources, err := unmarshalResourceMap(p.DataSourceSchemas)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&schema.Provider{
+		Schema:         config,
+		ResourcesMap:   resources,
+		DataSourcesMap: dataSources,
+	}).Shim(), nil
+}
@@ -0,0 +1,152 @@
+package schemajson
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+func TestLoadProviderFile(t *testing.T) {
+	p, err := LoadProviderFile("testdata/schema.json", "example")
+	require.NoError(t, err)
+
+	// Provider configuration.
+	endpoint := p.Schema().Get("endpoint")
+	assert.Equal(t, shim.TypeString, endpoint.Type())
+	assert.True(t, endpoint.Optional())
+	assert.Equal(t, "The API endpoint.", endpoint.Description())
+	token := p.Schema().Get("token")
+	assert.True(t, token.Required())
+	assert.True(t, token.Sensitive())
+
+	// Resources.
+	assert.Equal(t, 1, p.ResourcesMap().Len())
+	server, ok := p.ResourcesMap().GetOk("example_server")
+	require.True(t, ok)
+	assert.Equal(t, 2, server.SchemaVersion())
+
+	sch := server.Schema()
+	id := sch.Get("id")
+	assert.True(t, id.Computed())
+	assert.False(t, id.Optional())
+
+	assert.True(t, sch.Get("name").Required())
+	assert.Equal(t, "The name of the server.", sch.Get("name").Description())
+
+	tags := sch.Get("tags")
+	assert.Equal(t, shim.TypeMap, tags.Type())
+	assert.Equal(t, shim.TypeString, tags.Elem().(shim.Schema).Type())
+
+	ports := sch.Get("ports")
+	assert.Equal(t, shim.TypeList, ports.Type())
+	assert.Equal(t, shim.TypeFloat, ports.Elem().(shim.Schema).Type())
+
+	assert.Equal(t, shim.TypeDynamic, sch.Get("metadata").Type())
+
+	address := sch.Get("address")
+	assert.Equal(t, shim.TypeMap, address.Type())
+	assert.True(t, address.Computed())
+	addressFields := address.Elem().(shim.Resource).Schema()
+	assert.Equal(t, shim.TypeString, addressFields.Get("ip").Type())
+	assert.Equal(t, shim.TypeFloat, addressFields.Get("port").Type())
+
+	assert.Equal(t, "legacy_setting is deprecated", sch.Get("legacy_setting").Deprecated())
+
+	volumes := sch.Get("volumes")
+	assert.Equal(t, shim.TypeSet, volumes.Type())
+	assert.True(t, volumes.Elem().(shim.Resource).Schema().Get("size").Required())
+
+	network := sch.Get("network")
+	assert.Equal(t, shim.TypeList, network.Type())
+	assert.True(t, network.Optional())
+	assert.Equal(t, 1, network.MaxItems())
+	assert.Equal(t, "A network interface.", network.Description())
+	assert.True(t, network.Elem().(shim.Resource).Schema().Get("subnet").Required())
+
+	// Maps of objects are maps whose elements are schemas of objects, unlike single objects whose elements are the
+	// objects themselves.
+	endpoints := sch.Get("endpoints")
+	assert.Equal(t, shim.TypeMap, endpoints.Type())
+	endpointsElem := endpoints.Elem().(shim.Schema)
+	assert.Equal(t, shim.TypeMap, endpointsElem.Type())
+	assert.Equal(t, shim.TypeString, endpointsElem.Elem().(shim.Resource).Schema().Get("url").Type())
+
+	disks := sch.Get("disks")
+	assert.Equal(t, shim.TypeMap, disks.Type())
+	disksElem := disks.Elem().(shim.Schema)
+	assert.Equal(t, shim.TypeMap, disksElem.Type())
+	assert.True(t, disksElem.Elem().(shim.Resource).Schema().Get("size").Required())
+
+	listener := sch.Get("listener")
+	assert.Equal(t, shim.TypeMap, listener.Type())
+	assert.True(t, listener.Optional())
+	listenerElem := listener.Elem().(shim.Schema)
+	assert.Equal(t, shim.TypeMap, listenerElem.Type())
+	assert.True(t, listenerElem.Elem().(shim.Resource).Schema().Get("port").Required())
+
+	status := sch.Get("status")
+	assert.Equal(t, shim.TypeMap, status.Type())
+	assert.True(t, status.Computed())
+	assert.True(t, status.Elem().(shim.Resource).Schema().Get("state").Computed())
+
+	// Data sources.
+	image, ok := p.DataSourcesMap().GetOk("example_image")
+	require.True(t, ok)
+	assert.True(t, image.Schema().Get("family").Required())
+	assert.Equal(t, "example_image is deprecated", image.DeprecationMessage())
+}
+
+func TestProviderNames(t *testing.T) {
+	schemas, err := ReadProviderSchemas(strings.NewReader(`{
+		"format_version": "1.0",
+		"provider_schemas": {
+			"registry.terraform.io/hashicorp/aws": {},
+			"registry.terraform.io/example/aws": {},
+			"registry.terraform.io/hashicorp/random": {}
+		}
+	}`))
+	require.NoError(t, err)
+
+	_, err = schemas.Provider("registry.terraform.io/hashicorp/aws")
+	assert.NoError(t, err)
+	_, err = schemas.Provider("random")
+	assert.NoError(t, err)
+	_, err = schemas.Provider("aws")
+	assert.EqualError(t, err, `ambiguous provider name "aws": could refer to any of `+
+		`registry.terraform.io/example/aws, registry.terraform.io/hashicorp/aws`)
+	_, err = schemas.Provider("google")
+	assert.EqualError(t, err, `no schema for provider "google"`)
+
+	_, err = ReadProviderSchemas(strings.NewReader(`{"format_version": "2.0"}`))
+	assert.EqualError(t, err, `unsupported provider schemas format version "2.0"`)
+}
+
+func TestTupleTypes(t *testing.T) {
+	schemas, err := ReadProviderSchemas(strings.NewReader(`{
+		"format_version": "1.0",
+		"provider_schemas": {
+			"registry.terraform.io/example/example": {
+				"resource_schemas": {
+					"example_pair": {
+						"version": 0,
+						"block": {
+							"attributes": {
+								"pair": {"type": ["tuple", ["string", "number"]], "optional": true}
+							}
+						}
+					}
+				}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+
+	p, err := schemas.Provider("example")
+	require.NoError(t, err)
+	_, err = NewProvider(p)
+	assert.EqualError(t, err, "example_pair: attribute pair: tuple types are not supported")
+}
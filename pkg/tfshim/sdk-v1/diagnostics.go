@@ -0,0 +1,15 @@
+package sdkv1
+
+import (
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+)
+
+// warningsAndErrors converts the warnings and errors returned by the SDK's validation functions into diagnostics. SDK
+// v1 reports warnings as plain strings, so the resulting warnings have neither detail nor attribute path.
+func warningsAndErrors(warns []string, errs []error) (diagnostics.Diagnostics, []error) {
+	var warnings diagnostics.Diagnostics
+	for _, w := range warns {
+		warnings = append(warnings, diagnostics.Diagnostic{Severity: diagnostics.Warning, Summary: w})
+	}
+	return warnings, errs
+}
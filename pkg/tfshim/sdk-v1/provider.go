@@ -1,10 +1,13 @@
 package sdkv1
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/terraform-plugin-sdk/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
 )
 
 var _ = shim.Provider(v1Provider{})
@@ -68,49 +71,79 @@ func (p v1Provider) DataSourcesMap() shim.ResourceMap {
 	return v1ResourceMap(p.tf.DataSourcesMap)
 }
 
-func (p v1Provider) Validate(c shim.ResourceConfig) ([]string, []error) {
-	return p.tf.Validate(configFromShim(c))
+func (p v1Provider) Validate(c shim.ResourceConfig) (diagnostics.Diagnostics, []error) {
+	return warningsAndErrors(p.tf.Validate(configFromShim(c)))
 }
 
-func (p v1Provider) ValidateResource(t string, c shim.ResourceConfig) ([]string, []error) {
-	return p.tf.ValidateResource(t, configFromShim(c))
+func (p v1Provider) ValidateResource(t string, c shim.ResourceConfig) (diagnostics.Diagnostics, []error) {
+	return warningsAndErrors(p.tf.ValidateResource(t, configFromShim(c)))
 }
 
-func (p v1Provider) ValidateDataSource(t string, c shim.ResourceConfig) ([]string, []error) {
-	return p.tf.ValidateDataSource(t, configFromShim(c))
+func (p v1Provider) ValidateDataSource(t string, c shim.ResourceConfig) (diagnostics.Diagnostics, []error) {
+	return warningsAndErrors(p.tf.ValidateDataSource(t, configFromShim(c)))
 }
 
-func (p v1Provider) Configure(c shim.ResourceConfig) error {
-	return p.tf.Configure(configFromShim(c))
+// SDK v1 does not report warnings from any of the methods below.
+
+func (p v1Provider) Configure(c shim.ResourceConfig) (diagnostics.Diagnostics, error) {
+	return nil, p.tf.Configure(configFromShim(c))
 }
 
-func (p v1Provider) Diff(t string, s shim.InstanceState, c shim.ResourceConfig) (shim.InstanceDiff, error) {
+func (p v1Provider) Diff(t string, s shim.InstanceState,
+	c shim.ResourceConfig) (shim.InstanceDiff, diagnostics.Diagnostics, error) {
+
 	if c == nil {
-		return diffToShim(&terraform.InstanceDiff{Destroy: true}), nil
+		return diffToShim(&terraform.InstanceDiff{Destroy: true}), nil, nil
 	}
 
-	diff, err := p.tf.SimpleDiff(instanceInfo(t), stateFromShim(s), configFromShim(c))
-	return diffToShim(diff), err
+	r, ok := p.tf.ResourcesMap[t]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown resource %v", t)
+	}
+	state, err := upgradeResourceState(p.tf, r, stateFromShim(s))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to upgrade resource state: %w", err)
+	}
+	diff, err := p.tf.SimpleDiff(instanceInfo(t), state, configFromShim(c))
+	return diffToShim(diff), nil, err
 }
 
-func (p v1Provider) Apply(t string, s shim.InstanceState, d shim.InstanceDiff) (shim.InstanceState, error) {
-	state, err := p.tf.Apply(instanceInfo(t), stateFromShim(s), diffFromShim(d))
-	return stateToShim(state), err
+func (p v1Provider) Apply(t string, s shim.InstanceState,
+	d shim.InstanceDiff) (shim.InstanceState, diagnostics.Diagnostics, error) {
+
+	r, ok := p.tf.ResourcesMap[t]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown resource %v", t)
+	}
+	state, err := upgradeResourceState(p.tf, r, stateFromShim(s))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to upgrade resource state: %w", err)
+	}
+	state, err = r.Apply(state, diffFromShim(d), p.tf.Meta())
+	return stateToShim(state), nil, err
 }
 
-func (p v1Provider) Refresh(t string, s shim.InstanceState) (shim.InstanceState, error) {
-	state, err := p.tf.Refresh(instanceInfo(t), stateFromShim(s))
-	return stateToShim(state), err
+func (p v1Provider) Refresh(t string, s shim.InstanceState) (shim.InstanceState, diagnostics.Diagnostics, error) {
+	r, ok := p.tf.ResourcesMap[t]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown resource %v", t)
+	}
+	state, err := upgradeResourceState(p.tf, r, stateFromShim(s))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to upgrade resource state: %w", err)
+	}
+	state, err = r.RefreshWithoutUpgrade(state, p.tf.Meta())
+	return stateToShim(state), nil, err
 }
 
-func (p v1Provider) ReadDataDiff(t string, c shim.ResourceConfig) (shim.InstanceDiff, error) {
+func (p v1Provider) ReadDataDiff(t string, c shim.ResourceConfig) (shim.InstanceDiff, diagnostics.Diagnostics, error) {
 	diff, err := p.tf.ReadDataDiff(instanceInfo(t), configFromShim(c))
-	return diffToShim(diff), err
+	return diffToShim(diff), nil, err
 }
 
-func (p v1Provider) ReadDataApply(t string, d shim.InstanceDiff) (shim.InstanceState, error) {
+func (p v1Provider) ReadDataApply(t string, d shim.InstanceDiff) (shim.InstanceState, diagnostics.Diagnostics, error) {
 	state, err := p.tf.ReadDataApply(instanceInfo(t), diffFromShim(d))
-	return stateToShim(state), err
+	return stateToShim(state), nil, err
 }
 
 func (p v1Provider) Meta() interface{} {
@@ -4,6 +4,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimschema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 )
 
 var _ = shim.Schema(v1Schema{})
@@ -17,10 +18,17 @@ const UnknownVariableValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
 
 type v1Schema struct {
 	tf *schema.Schema
+
+	// validation holds the constraints probed from the schema's validator.
+	validation *shimschema.ProbedValidation
+}
+
+func newSchema(s *schema.Schema) v1Schema {
+	return v1Schema{tf: s, validation: &shimschema.ProbedValidation{}}
 }
 
 func NewSchema(s *schema.Schema) shim.Schema {
-	return v1Schema{s}
+	return newSchema(s)
 }
 
 func (s v1Schema) Type() shim.ValueType {
@@ -85,7 +93,7 @@ func (s v1Schema) Elem() interface{} {
 	case *schema.Resource:
 		return v1Resource{e}
 	case *schema.Schema:
-		return v1Schema{e}
+		return newSchema(e)
 	default:
 		return nil
 	}
@@ -103,6 +111,28 @@ func (s v1Schema) ConflictsWith() []string {
 	return s.tf.ConflictsWith
 }
 
+func (s v1Schema) ExactlyOneOf() []string {
+	return s.tf.ExactlyOneOf
+}
+
+func (s v1Schema) AtLeastOneOf() []string {
+	return s.tf.AtLeastOneOf
+}
+
+func (s v1Schema) RequiredWith() []string {
+	return nil
+}
+
+func (s v1Schema) Validation() *shim.SchemaValidation {
+	if s.tf.ValidateFunc == nil {
+		return nil
+	}
+	return s.validation.Get(s.tf.ValidateFunc, func(v interface{}) []error {
+		_, errs := s.tf.ValidateFunc(v, "value")
+		return errs
+	}, s.Type())
+}
+
 func (s v1Schema) Removed() string {
 	return s.tf.Removed
 }
@@ -126,13 +156,13 @@ func makeUnknownElement(elem interface{}) interface{} {
 	switch e := elem.(type) {
 	case *schema.Schema:
 		// If the element uses a normal schema, defer to UnknownValue.
-		return v1Schema{e}.UnknownValue()
+		return v1Schema{tf: e}.UnknownValue()
 	case *schema.Resource:
 		// If the element uses a resource schema, fill in unknown values for any required properties.
 		res := make(map[string]interface{})
 		for k, v := range e.Schema {
 			if v.Required {
-				res[k] = v1Schema{v}.UnknownValue()
+				res[k] = v1Schema{tf: v}.UnknownValue()
 			}
 		}
 		return res
@@ -197,14 +227,14 @@ func (m v1SchemaMap) Get(key string) shim.Schema {
 
 func (m v1SchemaMap) GetOk(key string) (shim.Schema, bool) {
 	if s, ok := m[key]; ok {
-		return v1Schema{s}, true
+		return newSchema(s), true
 	}
 	return nil, false
 }
 
 func (m v1SchemaMap) Range(each func(key string, value shim.Schema) bool) {
 	for key, value := range m {
-		if !each(key, v1Schema{value}) {
+		if !each(key, newSchema(value)) {
 			return
 		}
 	}
@@ -0,0 +1,310 @@
+package sdkv1
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func upgradeResourceState(p *schema.Provider, res *schema.Resource,
+	instanceState *terraform.InstanceState) (*terraform.InstanceState, error) {
+
+	if instanceState == nil {
+		return nil, nil
+	}
+
+	version := 0
+	if versionValue, ok := instanceState.Meta["schema_version"]; ok {
+		versionString, ok := versionValue.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected type %T for schema_version", versionValue)
+		}
+		v, err := strconv.ParseInt(versionString, 0, 32)
+		if err != nil {
+			return nil, err
+		}
+		version = int(v)
+	}
+
+	// States written at the current schema version need no upgrade.
+	if version >= res.SchemaVersion {
+		return instanceState, nil
+	}
+
+	// Copy the attributes so that the caller's state is left untouched, and ensure that we have an ID.
+	m := make(map[string]string, len(instanceState.Attributes)+1)
+	for k, v := range instanceState.Attributes {
+		m[k] = v
+	}
+	m["id"] = instanceState.ID
+
+	// First, build a JSON state from the InstanceState.
+	json, version, err := upgradeFlatmapState(p, res, version, m)
+	if err != nil {
+		return nil, err
+	}
+
+	// Next, migrate the JSON state up to the current version.
+	json, err = upgradeJSONState(p, res, version, json)
+	if err != nil {
+		return nil, err
+	}
+
+	configBlock := res.CoreConfigSchema()
+
+	// Strip out removed fields.
+	removeAttributes(json, configBlock.ImpliedType())
+
+	// now we need to turn the state into the default json representation, so
+	// that it can be re-decoded using the actual schema.
+	v, err := schema.JSONMapToStateValue(json, configBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	// Now we need to make sure blocks are represented correctly, which means
+	// that missing blocks are empty collections, rather than null.
+	// First we need to CoerceValue to ensure that all object types match.
+	v, err = configBlock.CoerceValue(v)
+	if err != nil {
+		return nil, err
+	}
+	// Normalize the value and fill in any missing blocks.
+	v = normalizeObjectFromLegacySDK(v, res.Schema)
+
+	// Convert the value back to an InstanceState.
+	newState, err := res.ShimInstanceStateFromValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy the original ID and meta to the new state and stamp in the new version.
+	newState.ID = instanceState.ID
+	newState.Meta = make(map[string]interface{}, len(instanceState.Meta)+1)
+	for k, v := range instanceState.Meta {
+		newState.Meta[k] = v
+	}
+	newState.Meta["schema_version"] = strconv.Itoa(res.SchemaVersion)
+	return newState, nil
+}
+
+// upgradeFlatmapState upgrades a flatmap state using the resource's legacy MigrateState function if necessary and
+// converts it to a JSON state. It returns the JSON state along with its schema version. This mirrors the SDK's
+// internal GRPCProviderServer.upgradeFlatmapState.
+func upgradeFlatmapState(p *schema.Provider, res *schema.Resource, version int,
+	m map[string]string) (map[string]interface{}, int, error) {
+
+	// this will be the version we've upgraded so, defaulting to the given
+	// version in case no migration was called.
+	upgradedVersion := version
+
+	// first determine if we need to call the legacy MigrateState func
+	requiresMigrate := version < res.SchemaVersion
+
+	schemaType := res.CoreConfigSchema().ImpliedType()
+
+	// if there are any StateUpgraders, then we need to only compare
+	// against the first version there
+	if len(res.StateUpgraders) > 0 {
+		requiresMigrate = version < res.StateUpgraders[0].Version
+	}
+
+	switch {
+	case requiresMigrate && res.MigrateState == nil:
+		// Providers were previously allowed to bump the version
+		// without declaring MigrateState.
+		// If there are further upgraders, then we've only updated that far.
+		if len(res.StateUpgraders) > 0 {
+			schemaType = res.StateUpgraders[0].Type
+			upgradedVersion = res.StateUpgraders[0].Version
+		}
+	case requiresMigrate:
+		is := &terraform.InstanceState{
+			ID:         m["id"],
+			Attributes: m,
+			Meta: map[string]interface{}{
+				"schema_version": strconv.Itoa(version),
+			},
+		}
+
+		is, err := res.MigrateState(version, is, p.Meta())
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// re-assign the map in case there was a copy made, making sure to keep
+		// the ID
+		m = is.Attributes
+		m["id"] = is.ID
+
+		// if there are further upgraders, then we've only updated that far
+		if len(res.StateUpgraders) > 0 {
+			schemaType = res.StateUpgraders[0].Type
+			upgradedVersion = res.StateUpgraders[0].Version
+		}
+	default:
+		// the schema version may be newer than the MigrateState functions
+		// handled and older than the current, but still stored in the flatmap
+		// form. If that's the case, we need to find the correct schema type to
+		// convert the state.
+		for _, upgrader := range res.StateUpgraders {
+			if upgrader.Version == version {
+				schemaType = upgrader.Type
+				break
+			}
+		}
+	}
+
+	// now we know the state is up to the latest version that handled the
+	// flatmap format state. Now we can upgrade the format and continue from
+	// there.
+	val, err := schema.StateValueFromInstanceState(&terraform.InstanceState{ID: m["id"], Attributes: m}, schemaType)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	jsonMap, err := schema.StateValueToJSONMap(val, schemaType)
+	return jsonMap, upgradedVersion, err
+}
+
+// upgradeJSONState runs the resource's StateUpgraders on a JSON state, starting from the given version.
+func upgradeJSONState(p *schema.Provider, res *schema.Resource, version int,
+	m map[string]interface{}) (map[string]interface{}, error) {
+
+	var err error
+	for _, upgrader := range res.StateUpgraders {
+		if version != upgrader.Version {
+			continue
+		}
+
+		m, err = upgrader.Upgrade(m, p.Meta())
+		if err != nil {
+			return nil, err
+		}
+		version++
+	}
+
+	return m, nil
+}
+
+// removeAttributes removes any attributes no longer present in the schema, so that the JSON state can be correctly
+// decoded.
+func removeAttributes(v interface{}, ty cty.Type) {
+	// we're only concerned with finding maps that corespond to object
+	// attributes
+	switch v := v.(type) {
+	case []interface{}:
+		// If these aren't blocks the next call will be a noop
+		if ty.IsListType() || ty.IsSetType() {
+			eTy := ty.ElementType()
+			for _, eV := range v {
+				removeAttributes(eV, eTy)
+			}
+		}
+	case map[string]interface{}:
+		// map blocks aren't yet supported, but handle this just in case
+		if ty.IsMapType() {
+			eTy := ty.ElementType()
+			for _, eV := range v {
+				removeAttributes(eV, eTy)
+			}
+			return
+		}
+
+		if !ty.IsObjectType() {
+			return
+		}
+
+		attrTypes := ty.AttributeTypes()
+		for attr, attrV := range v {
+			attrTy, ok := attrTypes[attr]
+			if !ok {
+				delete(v, attr)
+				continue
+			}
+
+			removeAttributes(attrV, attrTy)
+		}
+	}
+}
+
+// normalizeObjectFromLegacySDK ensures that the nested blocks of an object are never null or unknown, representing
+// missing blocks as empty collections instead. This mirrors the SDK's internal objchange.NormalizeObjectFromLegacySDK,
+// which cannot be imported. The legacy SDK only produces list and set blocks, so the nesting mode of each block is
+// taken from the type of its schema. Attributes that are not part of the schema, such as the implicit "id" attribute
+// and the timeouts block, are passed through as-is.
+func normalizeObjectFromLegacySDK(val cty.Value, m schema.InternalMap) cty.Value {
+	if val.IsNull() || !val.IsKnown() {
+		return val
+	}
+
+	vals := make(map[string]cty.Value)
+	for name := range val.Type().AttributeTypes() {
+		vals[name] = val.GetAttr(name)
+	}
+	for name, blockS := range m.CoreConfigSchema().BlockTypes {
+		// Legacy SDK never generates dynamically-typed attributes and so our
+		// normalization code doesn't deal with them.
+		ty := blockS.Block.ImpliedType()
+		if ty.HasDynamicTypes() {
+			continue
+		}
+
+		lv, elem := vals[name], m[name].Elem.(*schema.Resource).Schema
+		switch m[name].Type {
+		case schema.TypeList:
+			switch {
+			case !lv.IsKnown():
+				vals[name] = cty.ListVal([]cty.Value{unknownBlockStub(elem)})
+			case lv.IsNull() || lv.LengthInt() == 0:
+				vals[name] = cty.ListValEmpty(ty)
+			default:
+				subVals := make([]cty.Value, 0, lv.LengthInt())
+				for it := lv.ElementIterator(); it.Next(); {
+					_, subVal := it.Element()
+					subVals = append(subVals, normalizeObjectFromLegacySDK(subVal, elem))
+				}
+				vals[name] = cty.ListVal(subVals)
+			}
+		case schema.TypeSet:
+			switch {
+			case !lv.IsKnown():
+				vals[name] = cty.SetVal([]cty.Value{unknownBlockStub(elem)})
+			case lv.IsNull() || lv.LengthInt() == 0:
+				vals[name] = cty.SetValEmpty(ty)
+			default:
+				subVals := make([]cty.Value, 0, lv.LengthInt())
+				for it := lv.ElementIterator(); it.Next(); {
+					_, subVal := it.Element()
+					subVals = append(subVals, normalizeObjectFromLegacySDK(subVal, elem))
+				}
+				vals[name] = cty.SetVal(subVals)
+			}
+		}
+	}
+	return cty.ObjectVal(vals)
+}
+
+// unknownBlockStub constructs an object value that approximates an unknown block by producing a known block object
+// with all of its leaf attribute values set to unknown.
+func unknownBlockStub(m schema.InternalMap) cty.Value {
+	block := m.CoreConfigSchema()
+
+	vals := make(map[string]cty.Value)
+	for name, attrS := range block.Attributes {
+		vals[name] = cty.UnknownVal(attrS.Type)
+	}
+	for name := range block.BlockTypes {
+		elem := m[name].Elem.(*schema.Resource).Schema
+		switch m[name].Type {
+		case schema.TypeList:
+			vals[name] = cty.ListVal([]cty.Value{unknownBlockStub(elem)})
+		case schema.TypeSet:
+			vals[name] = cty.SetVal([]cty.Value{unknownBlockStub(elem)})
+		}
+	}
+	return cty.ObjectVal(vals)
+}
@@ -0,0 +1,169 @@
+package sdkv1
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestUpgradeResourceState(t *testing.T) {
+	res := &schema.Resource{
+		SchemaVersion: 2,
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Optional: true},
+			"size": {Type: schema.TypeInt, Optional: true},
+		},
+		// Version 0 stored the name as "title".
+		MigrateState: func(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+			is.Attributes["label"] = is.Attributes["title"]
+			delete(is.Attributes, "title")
+			return is, nil
+		},
+		// Version 1 stored the name as "label" and had no size.
+		StateUpgraders: []schema.StateUpgrader{{
+			Version: 1,
+			Type: cty.Object(map[string]cty.Type{
+				"id":    cty.String,
+				"label": cty.String,
+			}),
+			Upgrade: func(m map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+				m["name"] = m["label"]
+				delete(m, "label")
+				m["size"] = 2
+				return m, nil
+			},
+		}},
+	}
+	p := &schema.Provider{ResourcesMap: map[string]*schema.Resource{"example_resource": res}}
+
+	t.Run("from version 0", func(t *testing.T) {
+		state := &terraform.InstanceState{
+			ID:         "abc",
+			Attributes: map[string]string{"id": "abc", "title": "foo"},
+		}
+		upgraded, err := upgradeResourceState(p, res, state)
+		require.NoError(t, err)
+		assert.Equal(t, "abc", upgraded.ID)
+		assert.Equal(t, map[string]string{"id": "abc", "name": "foo", "size": "2"}, upgraded.Attributes)
+		assert.Equal(t, "2", upgraded.Meta["schema_version"])
+
+		// The original state is left untouched.
+		assert.Equal(t, map[string]string{"id": "abc", "title": "foo"}, state.Attributes)
+	})
+
+	t.Run("from version 1", func(t *testing.T) {
+		state := &terraform.InstanceState{
+			ID:         "abc",
+			Attributes: map[string]string{"id": "abc", "label": "foo"},
+			Meta:       map[string]interface{}{"schema_version": "1", "other": "meta"},
+		}
+		upgraded, err := upgradeResourceState(p, res, state)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"id": "abc", "name": "foo", "size": "2"}, upgraded.Attributes)
+		assert.Equal(t, map[string]interface{}{"schema_version": "2", "other": "meta"}, upgraded.Meta)
+	})
+
+	t.Run("current version", func(t *testing.T) {
+		state := &terraform.InstanceState{
+			ID:         "abc",
+			Attributes: map[string]string{"id": "abc", "name": "foo"},
+			Meta:       map[string]interface{}{"schema_version": "2"},
+		}
+		upgraded, err := upgradeResourceState(p, res, state)
+		require.NoError(t, err)
+		assert.Equal(t, state, upgraded)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		state := &terraform.InstanceState{
+			ID:   "abc",
+			Meta: map[string]interface{}{"schema_version": 1},
+		}
+		_, err := upgradeResourceState(p, res, state)
+		assert.EqualError(t, err, "unexpected type int for schema_version")
+	})
+}
+
+func TestUpgradeResourceStateMissingBlocks(t *testing.T) {
+	rule := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"port": {Type: schema.TypeInt, Optional: true},
+			"filter": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cidr": {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+		},
+	}
+	res := &schema.Resource{
+		SchemaVersion: 1,
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Optional: true},
+			"rule": {Type: schema.TypeList, Optional: true, Elem: rule},
+			"tag": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+		},
+		// Version 0 had no rules, tags or filters.
+		StateUpgraders: []schema.StateUpgrader{{
+			Version: 0,
+			Type: cty.Object(map[string]cty.Type{
+				"id":   cty.String,
+				"name": cty.String,
+			}),
+			Upgrade: func(m map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+				if m["name"] == "with-rule" {
+					m["rule"] = []interface{}{map[string]interface{}{"port": 80}}
+				}
+				return m, nil
+			},
+		}},
+	}
+	p := &schema.Provider{ResourcesMap: map[string]*schema.Resource{"example_resource": res}}
+
+	t.Run("top-level blocks", func(t *testing.T) {
+		state := &terraform.InstanceState{
+			ID:         "abc",
+			Attributes: map[string]string{"id": "abc", "name": "foo"},
+		}
+		upgraded, err := upgradeResourceState(p, res, state)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"id":     "abc",
+			"name":   "foo",
+			"rule.#": "0",
+			"tag.#":  "0",
+		}, upgraded.Attributes)
+	})
+
+	t.Run("nested blocks", func(t *testing.T) {
+		state := &terraform.InstanceState{
+			ID:         "abc",
+			Attributes: map[string]string{"id": "abc", "name": "with-rule"},
+		}
+		upgraded, err := upgradeResourceState(p, res, state)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"id":              "abc",
+			"name":            "with-rule",
+			"rule.#":          "1",
+			"rule.0.port":     "80",
+			"rule.0.filter.#": "0",
+			"tag.#":           "0",
+		}, upgraded.Attributes)
+	})
+}
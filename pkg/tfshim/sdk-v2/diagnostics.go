@@ -1,39 +1,38 @@
 package sdkv2
 
 import (
-	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
 )
 
-func warningsAndErrors(diags diag.Diagnostics) ([]string, []error) {
-	var warnings []string
-	var errors []error
+func warningsAndErrors(diags diag.Diagnostics) (diagnostics.Diagnostics, []error) {
+	return fromV2Diags(diags).Split()
+}
+
+func warningsAndErr(diags diag.Diagnostics) (diagnostics.Diagnostics, error) {
+	return fromV2Diags(diags).WarningsAndErr()
+}
+
+// fromV2Diags converts a set of SDK diagnostics to shim diagnostics. Diagnostics with unknown severity will be
+// dropped.
+func fromV2Diags(diags diag.Diagnostics) diagnostics.Diagnostics {
+	var result diagnostics.Diagnostics
 	for _, d := range diags {
+		var severity diagnostics.Severity
 		switch d.Severity {
 		case diag.Error:
-			errors = append(errors, fromV2Diag(d))
+			severity = diagnostics.Error
 		case diag.Warning:
-			warnings = append(warnings, d.Summary)
-		}
-	}
-	return warnings, errors
-}
-
-func errors(diags diag.Diagnostics) error {
-	var err error
-	for _, d := range diags {
-		if d.Severity == diag.Error {
-			err = multierror.Append(err, fromV2Diag(d))
+			severity = diagnostics.Warning
+		default:
+			continue
 		}
+		result = append(result, diagnostics.Diagnostic{
+			Severity:      severity,
+			Summary:       d.Summary,
+			Detail:        d.Detail,
+			AttributePath: d.AttributePath,
+		})
 	}
-	return err
-}
-
-func fromV2Diag(diagnostic diag.Diagnostic) error {
-	return &diagnostics.ValidationError{
-		AttributePath: diagnostic.AttributePath,
-		Summary:       diagnostic.Summary,
-		Detail:        diagnostic.Detail,
-	}
+	return result
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	testing "github.com/mitchellh/go-testing-interface"
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
 )
 
 var _ = shim.Provider(v2Provider{})
@@ -68,80 +69,87 @@ func (p v2Provider) DataSourcesMap() shim.ResourceMap {
 	return v2ResourceMap(p.tf.DataSourcesMap)
 }
 
-func (p v2Provider) Validate(c shim.ResourceConfig) ([]string, []error) {
+func (p v2Provider) Validate(c shim.ResourceConfig) (diagnostics.Diagnostics, []error) {
 	return warningsAndErrors(p.tf.Validate(configFromShim(c)))
 }
 
-func (p v2Provider) ValidateResource(t string, c shim.ResourceConfig) ([]string, []error) {
+func (p v2Provider) ValidateResource(t string, c shim.ResourceConfig) (diagnostics.Diagnostics, []error) {
 	return warningsAndErrors(p.tf.ValidateResource(t, configFromShim(c)))
 }
 
-func (p v2Provider) ValidateDataSource(t string, c shim.ResourceConfig) ([]string, []error) {
+func (p v2Provider) ValidateDataSource(t string, c shim.ResourceConfig) (diagnostics.Diagnostics, []error) {
 	return warningsAndErrors(p.tf.ValidateDataSource(t, configFromShim(c)))
 }
 
-func (p v2Provider) Configure(c shim.ResourceConfig) error {
-	return errors(p.tf.Configure(context.TODO(), configFromShim(c)))
+func (p v2Provider) Configure(c shim.ResourceConfig) (diagnostics.Diagnostics, error) {
+	return warningsAndErr(p.tf.Configure(context.TODO(), configFromShim(c)))
 }
 
-func (p v2Provider) Diff(t string, s shim.InstanceState, c shim.ResourceConfig) (shim.InstanceDiff, error) {
+func (p v2Provider) Diff(t string, s shim.InstanceState,
+	c shim.ResourceConfig) (shim.InstanceDiff, diagnostics.Diagnostics, error) {
+
 	if c == nil {
-		return diffToShim(&terraform.InstanceDiff{Destroy: true}), nil
+		return diffToShim(&terraform.InstanceDiff{Destroy: true}), nil, nil
 	}
 	r, ok := p.tf.ResourcesMap[t]
 	if !ok {
-		return nil, fmt.Errorf("unknown resource %v", t)
+		return nil, nil, fmt.Errorf("unknown resource %v", t)
 	}
 	state, err := upgradeResourceState(p.tf, r, stateFromShim(s))
 	if err != nil {
-		return nil, fmt.Errorf("failed to upgrade resource state: %w", err)
+		return nil, nil, fmt.Errorf("failed to upgrade resource state: %w", err)
 	}
 	diff, err := r.SimpleDiff(context.TODO(), state, configFromShim(c), p.tf.Meta())
-	return diffToShim(diff), err
+	return diffToShim(diff), nil, err
 }
 
-func (p v2Provider) Apply(t string, s shim.InstanceState, d shim.InstanceDiff) (shim.InstanceState, error) {
+func (p v2Provider) Apply(t string, s shim.InstanceState,
+	d shim.InstanceDiff) (shim.InstanceState, diagnostics.Diagnostics, error) {
+
 	r, ok := p.tf.ResourcesMap[t]
 	if !ok {
-		return nil, fmt.Errorf("unknown resource %v", t)
+		return nil, nil, fmt.Errorf("unknown resource %v", t)
 	}
 	state, err := upgradeResourceState(p.tf, r, stateFromShim(s))
 	if err != nil {
-		return nil, fmt.Errorf("failed to upgrade resource state: %w", err)
+		return nil, nil, fmt.Errorf("failed to upgrade resource state: %w", err)
 	}
 	state, diags := r.Apply(context.TODO(), state, diffFromShim(d), p.tf.Meta())
-	return stateToShim(state), errors(diags)
+	warnings, err := warningsAndErr(diags)
+	return stateToShim(state), warnings, err
 }
 
-func (p v2Provider) Refresh(t string, s shim.InstanceState) (shim.InstanceState, error) {
+func (p v2Provider) Refresh(t string, s shim.InstanceState) (shim.InstanceState, diagnostics.Diagnostics, error) {
 	r, ok := p.tf.ResourcesMap[t]
 	if !ok {
-		return nil, fmt.Errorf("unknown resource %v", t)
+		return nil, nil, fmt.Errorf("unknown resource %v", t)
 	}
 	state, err := upgradeResourceState(p.tf, r, stateFromShim(s))
 	if err != nil {
-		return nil, fmt.Errorf("failed to upgrade resource state: %w", err)
+		return nil, nil, fmt.Errorf("failed to upgrade resource state: %w", err)
 	}
 	state, diags := r.RefreshWithoutUpgrade(context.TODO(), state, p.tf.Meta())
-	return stateToShim(state), errors(diags)
+	warnings, err := warningsAndErr(diags)
+	return stateToShim(state), warnings, err
 }
 
-func (p v2Provider) ReadDataDiff(t string, c shim.ResourceConfig) (shim.InstanceDiff, error) {
+func (p v2Provider) ReadDataDiff(t string, c shim.ResourceConfig) (shim.InstanceDiff, diagnostics.Diagnostics, error) {
 	r, ok := p.tf.DataSourcesMap[t]
 	if !ok {
-		return nil, fmt.Errorf("unknown resource %v", t)
+		return nil, nil, fmt.Errorf("unknown resource %v", t)
 	}
 	diff, err := r.Diff(context.TODO(), nil, configFromShim(c), p.tf.Meta())
-	return diffToShim(diff), err
+	return diffToShim(diff), nil, err
 }
 
-func (p v2Provider) ReadDataApply(t string, d shim.InstanceDiff) (shim.InstanceState, error) {
+func (p v2Provider) ReadDataApply(t string, d shim.InstanceDiff) (shim.InstanceState, diagnostics.Diagnostics, error) {
 	r, ok := p.tf.DataSourcesMap[t]
 	if !ok {
-		return nil, fmt.Errorf("unknown resource %v", t)
+		return nil, nil, fmt.Errorf("unknown resource %v", t)
 	}
 	state, diags := r.ReadDataApply(context.TODO(), diffFromShim(d), p.tf.Meta())
-	return stateToShim(state), errors(diags)
+	warnings, err := warningsAndErr(diags)
+	return stateToShim(state), warnings, err
 }
 
 func (p v2Provider) Meta() interface{} {
@@ -1,9 +1,11 @@
 package sdkv2
 
 import (
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimschema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 )
 
 var _ = shim.Schema(v2Schema{})
@@ -17,10 +19,17 @@ const UnknownVariableValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
 
 type v2Schema struct {
 	tf *schema.Schema
+
+	// validation holds the constraints probed from the schema's validator.
+	validation *shimschema.ProbedValidation
+}
+
+func newSchema(s *schema.Schema) v2Schema {
+	return v2Schema{tf: s, validation: &shimschema.ProbedValidation{}}
 }
 
 func NewSchema(s *schema.Schema) shim.Schema {
-	return v2Schema{s}
+	return newSchema(s)
 }
 
 func (s v2Schema) Type() shim.ValueType {
@@ -85,7 +94,7 @@ func (s v2Schema) Elem() interface{} {
 	case *schema.Resource:
 		return v2Resource{e}
 	case *schema.Schema:
-		return v2Schema{e}
+		return newSchema(e)
 	default:
 		return nil
 	}
@@ -103,6 +112,35 @@ func (s v2Schema) ConflictsWith() []string {
 	return s.tf.ConflictsWith
 }
 
+func (s v2Schema) ExactlyOneOf() []string {
+	return s.tf.ExactlyOneOf
+}
+
+func (s v2Schema) AtLeastOneOf() []string {
+	return s.tf.AtLeastOneOf
+}
+
+func (s v2Schema) RequiredWith() []string {
+	return s.tf.RequiredWith
+}
+
+func (s v2Schema) Validation() *shim.SchemaValidation {
+	switch {
+	case s.tf.ValidateFunc != nil:
+		return s.validation.Get(s.tf.ValidateFunc, func(v interface{}) []error {
+			_, errs := s.tf.ValidateFunc(v, "value")
+			return errs
+		}, s.Type())
+	case s.tf.ValidateDiagFunc != nil:
+		return s.validation.Get(s.tf.ValidateDiagFunc, func(v interface{}) []error {
+			_, errs := warningsAndErrors(s.tf.ValidateDiagFunc(v, cty.GetAttrPath("value")))
+			return errs
+		}, s.Type())
+	default:
+		return nil
+	}
+}
+
 func (s v2Schema) Removed() string {
 	return ""
 }
@@ -126,13 +164,13 @@ func makeUnknownElement(elem interface{}) interface{} {
 	switch e := elem.(type) {
 	case *schema.Schema:
 		// If the element uses a normal schema, defer to UnknownValue.
-		return v2Schema{e}.UnknownValue()
+		return v2Schema{tf: e}.UnknownValue()
 	case *schema.Resource:
 		// If the element uses a resource schema, fill in unknown values for any required properties.
 		res := make(map[string]interface{})
 		for k, v := range e.Schema {
 			if v.Required {
-				res[k] = v2Schema{v}.UnknownValue()
+				res[k] = v2Schema{tf: v}.UnknownValue()
 			}
 		}
 		return res
@@ -197,14 +235,14 @@ func (m v2SchemaMap) Get(key string) shim.Schema {
 
 func (m v2SchemaMap) GetOk(key string) (shim.Schema, bool) {
 	if s, ok := m[key]; ok {
-		return v2Schema{s}, true
+		return newSchema(s), true
 	}
 	return nil, false
 }
 
 func (m v2SchemaMap) Range(each func(key string, value shim.Schema) bool) {
 	for key, value := range m {
-		if !each(key, v2Schema{value}) {
+		if !each(key, newSchema(value)) {
 			return
 		}
 	}
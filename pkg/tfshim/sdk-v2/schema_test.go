@@ -0,0 +1,149 @@
+package sdkv2
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/stretchr/testify/assert"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+func TestSchemaValidation(t *testing.T) {
+	float := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name     string
+		schema   *schema.Schema
+		expected *shim.SchemaValidation
+	}{
+		{
+			name:   "no validator",
+			schema: &schema.Schema{Type: schema.TypeString},
+		},
+		{
+			name: "string in slice",
+			schema: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice([]string{"red", "green", "blue"}, false),
+			},
+			expected: &shim.SchemaValidation{AllowedValues: []string{"red", "green", "blue"}},
+		},
+		{
+			name: "string in slice as diag func",
+			schema: &schema.Schema{
+				Type:             schema.TypeString,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"a", "b"}, true)),
+			},
+		},
+		{
+			name: "string in slice ignoring case",
+			schema: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice([]string{"a", "b"}, true),
+			},
+			expected: &shim.SchemaValidation{AllowedValues: []string{"a", "b"}, IgnoreCase: true},
+		},
+		{
+			name: "string in slice or pattern",
+			schema: &schema.Schema{
+				Type: schema.TypeString,
+				ValidateFunc: validation.Any(
+					validation.StringInSlice([]string{"red", "green"}, false),
+					validation.StringMatch(regexp.MustCompile(`^[a-z]+$`), "must be lowercase")),
+			},
+		},
+		{
+			name: "string in slice or anything",
+			schema: &schema.Schema{
+				Type: schema.TypeString,
+				ValidateFunc: func(v interface{}, k string) ([]string, []error) {
+					if v == "\x00" {
+						return validation.StringInSlice([]string{"red", "green"}, false)(v, k)
+					}
+					return nil, nil
+				},
+			},
+		},
+		{
+			name: "string in slice with ambiguous values",
+			schema: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice([]string{"", "two words"}, false),
+			},
+		},
+		{
+			name: "other string validator",
+			schema: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringLenBetween(1, 10),
+			},
+		},
+		{
+			name: "int between",
+			schema: &schema.Schema{
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+			expected: &shim.SchemaValidation{Min: float(1), Max: float(65535)},
+		},
+		{
+			name: "int at least",
+			schema: &schema.Schema{
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			expected: &shim.SchemaValidation{Min: float(0)},
+		},
+		{
+			name: "float at most",
+			schema: &schema.Schema{
+				Type:         schema.TypeFloat,
+				ValidateFunc: validation.FloatAtMost(1.5),
+			},
+			expected: &shim.SchemaValidation{Max: float(1.5)},
+		},
+		{
+			name: "panicking validator",
+			schema: &schema.Schema{
+				Type: schema.TypeString,
+				ValidateFunc: func(v interface{}, k string) ([]string, []error) {
+					panic("oops")
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NewSchema(tt.schema).Validation())
+		})
+	}
+}
+
+func TestSchemaValidationIsCached(t *testing.T) {
+	s := NewSchema(&schema.Schema{
+		Type:         schema.TypeString,
+		ValidateFunc: validation.StringInSlice([]string{"red", "green"}, false),
+	})
+
+	expected := &shim.SchemaValidation{AllowedValues: []string{"red", "green"}}
+	assert.Equal(t, expected, s.Validation())
+	assert.Same(t, s.Validation(), s.Validation())
+}
+
+func TestSchemaCrossFieldConstraints(t *testing.T) {
+	s := NewSchema(&schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		ConflictsWith: []string{"a"},
+		ExactlyOneOf:  []string{"b", "c"},
+		AtLeastOneOf:  []string{"d", "e"},
+		RequiredWith:  []string{"f"},
+	})
+	assert.Equal(t, []string{"a"}, s.ConflictsWith())
+	assert.Equal(t, []string{"b", "c"}, s.ExactlyOneOf())
+	assert.Equal(t, []string{"d", "e"}, s.AtLeastOneOf())
+	assert.Equal(t, []string{"f"}, s.RequiredWith())
+}
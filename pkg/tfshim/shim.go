@@ -2,6 +2,8 @@ package shim
 
 import (
 	"time"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
 )
 
 type ResourceConfig interface {
@@ -58,12 +60,24 @@ const (
 	TypeList
 	TypeMap
 	TypeSet
+	// TypeDynamic is the type of attributes whose values may be of any type, such as arbitrary JSON-like documents.
+	TypeDynamic
 )
 
 type SchemaDefaultFunc func() (interface{}, error)
 
 type SchemaStateFunc func(interface{}) string
 
+// SchemaValidation describes the values accepted by a schema's validation function, as far as they can be determined.
+type SchemaValidation struct {
+	// AllowedValues, if non-empty, is the complete set of string values accepted by the validator.
+	AllowedValues []string
+	// IgnoreCase is true if the validator also accepts the AllowedValues in any case.
+	IgnoreCase bool
+	// Min and Max, if non-nil, are the inclusive bounds of the numeric values accepted by the validator.
+	Min, Max *float64
+}
+
 type Schema interface {
 	Type() ValueType
 	Optional() bool
@@ -79,6 +93,10 @@ type Schema interface {
 	MaxItems() int
 	MinItems() int
 	ConflictsWith() []string
+	ExactlyOneOf() []string
+	AtLeastOneOf() []string
+	RequiredWith() []string
+	Validation() *SchemaValidation
 	Deprecated() string
 	Removed() string
 	Sensitive() bool
@@ -138,17 +156,20 @@ type Provider interface {
 	ResourcesMap() ResourceMap
 	DataSourcesMap() ResourceMap
 
-	Validate(c ResourceConfig) ([]string, []error)
-	ValidateResource(t string, c ResourceConfig) ([]string, []error)
-	ValidateDataSource(t string, c ResourceConfig) ([]string, []error)
+	// The methods below return any warnings reported by the provider alongside their results. Errors reported by the
+	// provider are returned as errors of type *diagnostics.Diagnostic, possibly combined into a multi-error.
+
+	Validate(c ResourceConfig) (diagnostics.Diagnostics, []error)
+	ValidateResource(t string, c ResourceConfig) (diagnostics.Diagnostics, []error)
+	ValidateDataSource(t string, c ResourceConfig) (diagnostics.Diagnostics, []error)
 
-	Configure(c ResourceConfig) error
-	Diff(t string, s InstanceState, c ResourceConfig) (InstanceDiff, error)
-	Apply(t string, s InstanceState, d InstanceDiff) (InstanceState, error)
-	Refresh(t string, s InstanceState) (InstanceState, error)
+	Configure(c ResourceConfig) (diagnostics.Diagnostics, error)
+	Diff(t string, s InstanceState, c ResourceConfig) (InstanceDiff, diagnostics.Diagnostics, error)
+	Apply(t string, s InstanceState, d InstanceDiff) (InstanceState, diagnostics.Diagnostics, error)
+	Refresh(t string, s InstanceState) (InstanceState, diagnostics.Diagnostics, error)
 
-	ReadDataDiff(t string, c ResourceConfig) (InstanceDiff, error)
-	ReadDataApply(t string, d InstanceDiff) (InstanceState, error)
+	ReadDataDiff(t string, c ResourceConfig) (InstanceDiff, diagnostics.Diagnostics, error)
+	ReadDataApply(t string, d InstanceDiff) (InstanceState, diagnostics.Diagnostics, error)
 
 	Meta() interface{}
 	Stop() error
@@ -158,3 +179,11 @@ type Provider interface {
 	NewResourceConfig(object map[string]interface{}) ResourceConfig
 	IsSet(v interface{}) ([]interface{}, bool)
 }
+
+// ProviderWithMeta is implemented by providers that accept a provider_meta value, which is sent to the provider with
+// each resource and data source operation.
+type ProviderWithMeta interface {
+	Provider
+
+	SetProviderMeta(meta map[string]interface{}) error
+}
@@ -2,46 +2,33 @@ package tfplugin5
 
 import (
 	"github.com/hashicorp/go-cty/cty"
-	"github.com/hashicorp/go-multierror"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
 
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
 )
 
-// unmarshalWarningsAndErrors converts a set of diagnostics from its wire format to a list of warnings and a list of
-// errors. Diagnostics with unknown severity will be dropped.
-func unmarshalWarningsAndErrors(diags []*proto.Diagnostic) ([]string, []error) {
-	var warnings []string
-	var errors []error
+// unmarshalDiagnostics converts a set of diagnostics from its wire format. Diagnostics with unknown severity will be
+// dropped.
+func unmarshalDiagnostics(diags []*proto.Diagnostic) diagnostics.Diagnostics {
+	var result diagnostics.Diagnostics
 	for _, d := range diags {
+		var severity diagnostics.Severity
 		switch d.Severity {
 		case proto.Diagnostic_ERROR:
-			errors = append(errors, fromTF5ProtoDiag(d))
+			severity = diagnostics.Error
 		case proto.Diagnostic_WARNING:
-			warnings = append(warnings, d.Summary)
+			severity = diagnostics.Warning
+		default:
+			continue
 		}
+		result = append(result, diagnostics.Diagnostic{
+			Severity:      severity,
+			Summary:       d.Summary,
+			Detail:        d.Detail,
+			AttributePath: pathToCty(d.Attribute),
+		})
 	}
-	return warnings, errors
-}
-
-// unmarshalErrors converts a set of diagnostics from its wire format to a (possibly multi-) error. Diagnostics that
-// are not errors are dropped.
-func unmarshalErrors(diags []*proto.Diagnostic) error {
-	var err error
-	for _, d := range diags {
-		if d.Severity == proto.Diagnostic_ERROR {
-			err = multierror.Append(err, fromTF5ProtoDiag(d))
-		}
-	}
-	return err
-}
-
-func fromTF5ProtoDiag(diagnostic *proto.Diagnostic) error {
-	return &diagnostics.ValidationError{
-		AttributePath: pathToCty(diagnostic.Attribute),
-		Summary:       diagnostic.Summary,
-		Detail:        diagnostic.Detail,
-	}
+	return result
 }
 
 func pathToCty(path *proto.AttributePath) cty.Path {
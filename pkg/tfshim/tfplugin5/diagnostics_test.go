@@ -1,10 +1,11 @@
 package tfplugin5
 
 import (
-	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-multierror"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
@@ -22,20 +23,55 @@ var errorsOnly = []*proto.Diagnostic{
 
 var mixed = append(append([]*proto.Diagnostic{}, warningsOnly...), errorsOnly...)
 
+var expectedWarnings = diagnostics.Diagnostics{
+	{Severity: diagnostics.Warning, Summary: "warning 1"},
+	{Severity: diagnostics.Warning, Summary: "warning 2"},
+}
+
+func TestUnmarshalDiagnostics(t *testing.T) {
+	diags := unmarshalDiagnostics([]*proto.Diagnostic{
+		{
+			Severity: proto.Diagnostic_WARNING,
+			Summary:  "deprecated",
+			Detail:   "use b instead",
+			Attribute: &proto.AttributePath{Steps: []*proto.AttributePath_Step{
+				{Selector: &proto.AttributePath_Step_AttributeName{AttributeName: "a"}},
+				{Selector: &proto.AttributePath_Step_ElementKeyInt{ElementKeyInt: 0}},
+			}},
+		},
+		{Severity: proto.Diagnostic_INVALID, Summary: "dropped"},
+		{Severity: proto.Diagnostic_ERROR, Summary: "failed", Detail: "line 1\nline 2"},
+	})
+	assert.Equal(t, diagnostics.Diagnostics{
+		{
+			Severity:      diagnostics.Warning,
+			Summary:       "deprecated",
+			Detail:        "use b instead",
+			AttributePath: cty.GetAttrPath("a").Index(cty.NumberIntVal(0)),
+		},
+		{Severity: diagnostics.Error, Summary: "failed", Detail: "line 1\nline 2"},
+	}, diags)
+	assert.True(t, diags.HasErrors())
+
+	warnings, err := unmarshalDiagnostics(mixed).WarningsAndErr()
+	assert.Equal(t, expectedWarnings, warnings)
+	assert.EqualError(t, err, "2 errors occurred:\n\t* error 1\n\t* error 2\n\n")
+}
+
 func TestWarningsAndErrors(t *testing.T) {
-	warnings, errors := unmarshalWarningsAndErrors(warningsOnly)
-	assert.Equal(t, []string{"warning 1", "warning 2"}, warnings)
+	warnings, errors := unmarshalDiagnostics(warningsOnly).Split()
+	assert.Equal(t, expectedWarnings, warnings)
 	assert.Empty(t, errors)
 
-	warnings, errors = unmarshalWarningsAndErrors(errorsOnly)
+	warnings, errors = unmarshalDiagnostics(errorsOnly).Split()
 	assert.Empty(t, warnings)
 	assert.Equal(t, errors, []error{&diagnostics.ValidationError{Summary: "error 1"},
 		&diagnostics.ValidationError{Summary: "error 2"}})
 	assert.EqualError(t, errors[0], "error 1")
 	assert.EqualError(t, errors[1], "error 2")
 
-	warnings, errors = unmarshalWarningsAndErrors(mixed)
-	assert.Equal(t, []string{"warning 1", "warning 2"}, warnings)
+	warnings, errors = unmarshalDiagnostics(mixed).Split()
+	assert.Equal(t, expectedWarnings, warnings)
 	assert.Equal(t, errors, []error{&diagnostics.ValidationError{Summary: "error 1"},
 		&diagnostics.ValidationError{Summary: "error 2"}})
 	assert.EqualError(t, errors[0], "error 1")
@@ -43,14 +79,14 @@ func TestWarningsAndErrors(t *testing.T) {
 }
 
 func TestErrors(t *testing.T) {
-	err := unmarshalErrors(warningsOnly)
+	err := unmarshalDiagnostics(warningsOnly).Err()
 	assert.NoError(t, err)
 
-	err = unmarshalErrors(errorsOnly)
+	err = unmarshalDiagnostics(errorsOnly).Err()
 	assert.Equal(t, multierror.Append(nil, &diagnostics.ValidationError{Summary: "error 1"},
 		&diagnostics.ValidationError{Summary: "error 2"}), err)
 
-	err = unmarshalErrors(mixed)
+	err = unmarshalDiagnostics(mixed).Err()
 	assert.Equal(t, multierror.Append(nil, &diagnostics.ValidationError{Summary: "error 1"},
 		&diagnostics.ValidationError{Summary: "error 2"}), err)
 }
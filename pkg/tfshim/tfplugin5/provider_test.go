@@ -18,6 +18,7 @@ import (
 
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 )
@@ -142,7 +143,7 @@ func (l *testLogger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer
 	panic("unsupported")
 }
 
-func startTestProvider(t *testing.T) (*provider, bool) {
+func startTestProvider(t *testing.T) (shim.ProviderWithMeta, bool) {
 	testProviderPath, err := exec.LookPath("pulumi-terraform-bridge-test-provider")
 	require.NoError(t, err)
 
@@ -177,7 +178,7 @@ func startTestProvider(t *testing.T) (*provider, bool) {
 	p, err := client.Dispense("provider")
 	require.NoError(t, err)
 
-	provider := p.(*provider)
+	provider := p.(shim.ProviderWithMeta)
 	t.Cleanup(func() {
 		err := provider.Stop()
 		contract.IgnoreError(err)
@@ -188,22 +189,60 @@ func startTestProvider(t *testing.T) (*provider, bool) {
 	return provider, true
 }
 
+// This corresponds to the TF plugin SDK's timeouts key.
+const timeoutsKey = "e2bfb730-ecaa-11e6-8f88-34363bc7c4c0"
+
+func add(new string, replace bool) shim.ResourceAttrDiff {
+	return shim.ResourceAttrDiff{
+		New:         new,
+		RequiresNew: replace,
+	}
+}
+
+func remove(old string, replace bool) shim.ResourceAttrDiff {
+	return shim.ResourceAttrDiff{
+		Old:         old,
+		NewRemoved:  true,
+		RequiresNew: replace,
+	}
+}
+
+func update(old, new string, replace bool) shim.ResourceAttrDiff {
+	return shim.ResourceAttrDiff{
+		Old:         old,
+		New:         new,
+		RequiresNew: replace,
+	}
+}
+
+func assertInstanceState(t *testing.T, resourceType, id string, object, meta map[string]interface{},
+	actual shim.InstanceState) {
+
+	require.NotNil(t, actual)
+	assert.Equal(t, resourceType, actual.Type())
+	assert.Equal(t, id, actual.ID())
+	actualObject, err := actual.Object(nil)
+	require.NoError(t, err)
+	assert.Equal(t, object, actualObject)
+	assert.Equal(t, meta, actual.Meta())
+}
+
 func TestProviderSchema(t *testing.T) {
 	p, ok := startTestProvider(t)
 	if !ok {
 		return
 	}
 
-	properties := map[string]*attributeSchema{}
+	properties := map[string]*tfplugin.AttributeSchema{}
 	p.Schema().Range(func(k string, v shim.Schema) bool {
-		properties[k] = v.(*attributeSchema)
+		properties[k] = v.(tfplugin.AttributeSchemaShim).V
 		return true
 	})
-	assert.Equal(t, map[string]*attributeSchema{
+	assert.Equal(t, map[string]*tfplugin.AttributeSchema{
 		"config_value": {
-			ctyType:   cty.String,
-			valueType: shim.TypeString,
-			optional:  true,
+			CtyType:   cty.String,
+			ValueType: shim.TypeString,
+			Optional:  true,
 		},
 	}, properties)
 }
@@ -214,11 +253,11 @@ func TestProviderResourcesMap(t *testing.T) {
 		return
 	}
 
-	expected := map[string]*resource{
+	expected := map[string]*tfplugin.Resource{
 		"nested_secret_resource": {
-			resourceType:  "nested_secret_resource",
-			schemaVersion: 1,
-			ctyType: cty.Object(map[string]cty.Type{
+			ResourceType:  "nested_secret_resource",
+			SchemaVersion: 1,
+			CtyType: cty.Object(map[string]cty.Type{
 				"id": cty.String,
 				"timeouts": cty.Object(map[string]cty.Type{
 					"create": cty.String,
@@ -227,58 +266,60 @@ func TestProviderResourcesMap(t *testing.T) {
 					"a_secret": cty.String,
 				})),
 			}),
-			schema: schema.SchemaMap{
-				"id": &attributeSchema{
-					ctyType:   cty.String,
-					valueType: shim.TypeString,
-					computed:  true,
-				},
-				"timeouts": &attributeSchema{
-					ctyType: cty.Object(map[string]cty.Type{
+			Schema: schema.SchemaMap{
+				"id": (&tfplugin.AttributeSchema{
+					CtyType:   cty.String,
+					ValueType: shim.TypeString,
+					Computed:  true,
+				}).Shim(),
+				"timeouts": (&tfplugin.AttributeSchema{
+					CtyType: cty.Object(map[string]cty.Type{
 						"create": cty.String,
 					}),
-					valueType: shim.TypeMap,
-					elem: &resource{
-						ctyType: cty.Object(map[string]cty.Type{
+					ValueType: shim.TypeMap,
+					Elem: (&tfplugin.Resource{
+						CtyType: cty.Object(map[string]cty.Type{
 							"create": cty.String,
 						}),
-						schema: schema.SchemaMap{
-							"create": &attributeSchema{
-								ctyType:   cty.String,
-								valueType: shim.TypeString,
-								optional:  true,
-							},
+						Schema: schema.SchemaMap{
+							"create": (&tfplugin.AttributeSchema{
+								CtyType:   cty.String,
+								ValueType: shim.TypeString,
+								Optional:  true,
+							}).Shim(),
 						},
-					},
-					required: true,
-				},
-				"nested": &attributeSchema{
-					ctyType: cty.List(cty.Object(map[string]cty.Type{
+					}).Shim(),
+					Required: true,
+					Nesting:  tfplugin.NestingSingle,
+				}).Shim(),
+				"nested": (&tfplugin.AttributeSchema{
+					CtyType: cty.List(cty.Object(map[string]cty.Type{
 						"a_secret": cty.String,
 					})),
-					valueType: shim.TypeList,
-					elem: &resource{
-						ctyType: cty.Object(map[string]cty.Type{
+					ValueType: shim.TypeList,
+					Elem: (&tfplugin.Resource{
+						CtyType: cty.Object(map[string]cty.Type{
 							"a_secret": cty.String,
 						}),
-						schema: schema.SchemaMap{
-							"a_secret": &attributeSchema{
-								ctyType:   cty.String,
-								valueType: shim.TypeString,
-								sensitive: true,
-								computed:  true,
-							},
+						Schema: schema.SchemaMap{
+							"a_secret": (&tfplugin.AttributeSchema{
+								CtyType:   cty.String,
+								ValueType: shim.TypeString,
+								Sensitive: true,
+								Computed:  true,
+							}).Shim(),
 						},
-					},
-					maxItems: 1,
-					computed: true,
-				},
+					}).Shim(),
+					MaxItems: 1,
+					Nesting:  tfplugin.NestingList,
+					Computed: true,
+				}).Shim(),
 			},
 		},
 		"example_resource": {
-			resourceType:  "example_resource",
-			schemaVersion: 1,
-			ctyType: cty.Object(map[string]cty.Type{
+			ResourceType:  "example_resource",
+			SchemaVersion: 1,
+			CtyType: cty.Object(map[string]cty.Type{
 				"id": cty.String,
 				"timeouts": cty.Object(map[string]cty.Type{
 					"create": cty.String,
@@ -298,136 +339,138 @@ func TestProviderResourcesMap(t *testing.T) {
 				"set_property_value":            cty.Set(cty.String),
 				"string_with_bad_interpolation": cty.String,
 			}),
-			schema: schema.SchemaMap{
-				"id": &attributeSchema{
-					ctyType:   cty.String,
-					valueType: shim.TypeString,
-					computed:  true,
-				},
-				"timeouts": &attributeSchema{
-					ctyType: cty.Object(map[string]cty.Type{
+			Schema: schema.SchemaMap{
+				"id": (&tfplugin.AttributeSchema{
+					CtyType:   cty.String,
+					ValueType: shim.TypeString,
+					Computed:  true,
+				}).Shim(),
+				"timeouts": (&tfplugin.AttributeSchema{
+					CtyType: cty.Object(map[string]cty.Type{
 						"create": cty.String,
 					}),
-					valueType: shim.TypeMap,
-					elem: &resource{
-						ctyType: cty.Object(map[string]cty.Type{
+					ValueType: shim.TypeMap,
+					Elem: (&tfplugin.Resource{
+						CtyType: cty.Object(map[string]cty.Type{
 							"create": cty.String,
 						}),
-						schema: schema.SchemaMap{
-							"create": &attributeSchema{
-								ctyType:   cty.String,
-								valueType: shim.TypeString,
-								optional:  true,
-							},
+						Schema: schema.SchemaMap{
+							"create": (&tfplugin.AttributeSchema{
+								CtyType:   cty.String,
+								ValueType: shim.TypeString,
+								Optional:  true,
+							}).Shim(),
 						},
-					},
-					required: true,
-				},
-				"nil_property_value": &attributeSchema{
-					ctyType:   cty.Map(cty.String),
-					valueType: shim.TypeMap,
-					elem: &attributeSchema{
-						ctyType:   cty.String,
-						valueType: shim.TypeString,
-					},
-					optional: true,
-				},
-				"bool_property_value": &attributeSchema{
-					ctyType:   cty.Bool,
-					valueType: shim.TypeBool,
-					optional:  true,
-				},
-				"number_property_value": &attributeSchema{
-					ctyType:   cty.Number,
-					valueType: shim.TypeFloat,
-					optional:  true,
-				},
-				"float_property_value": &attributeSchema{
-					ctyType:   cty.Number,
-					valueType: shim.TypeFloat,
-					optional:  true,
-				},
-				"string_property_value": &attributeSchema{
-					ctyType:   cty.String,
-					valueType: shim.TypeString,
-					optional:  true,
-				},
-				"array_property_value": &attributeSchema{
-					ctyType:   cty.List(cty.String),
-					valueType: shim.TypeList,
-					elem: &attributeSchema{
-						ctyType:   cty.String,
-						valueType: shim.TypeString,
-					},
-					required: true,
-				},
-				"object_property_value": &attributeSchema{
-					ctyType:   cty.Map(cty.String),
-					valueType: shim.TypeMap,
-					elem: &attributeSchema{
-						ctyType:   cty.String,
-						valueType: shim.TypeString,
-					},
-					optional: true,
-				},
-				"nested_resources": &attributeSchema{
-					ctyType: cty.List(cty.Object(map[string]cty.Type{
+					}).Shim(),
+					Required: true,
+					Nesting:  tfplugin.NestingSingle,
+				}).Shim(),
+				"nil_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Map(cty.String),
+					ValueType: shim.TypeMap,
+					Elem: (&tfplugin.AttributeSchema{
+						CtyType:   cty.String,
+						ValueType: shim.TypeString,
+					}).Shim(),
+					Optional: true,
+				}).Shim(),
+				"bool_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Bool,
+					ValueType: shim.TypeBool,
+					Optional:  true,
+				}).Shim(),
+				"number_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Number,
+					ValueType: shim.TypeFloat,
+					Optional:  true,
+				}).Shim(),
+				"float_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Number,
+					ValueType: shim.TypeFloat,
+					Optional:  true,
+				}).Shim(),
+				"string_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.String,
+					ValueType: shim.TypeString,
+					Optional:  true,
+				}).Shim(),
+				"array_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.List(cty.String),
+					ValueType: shim.TypeList,
+					Elem: (&tfplugin.AttributeSchema{
+						CtyType:   cty.String,
+						ValueType: shim.TypeString,
+					}).Shim(),
+					Required: true,
+				}).Shim(),
+				"object_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Map(cty.String),
+					ValueType: shim.TypeMap,
+					Elem: (&tfplugin.AttributeSchema{
+						CtyType:   cty.String,
+						ValueType: shim.TypeString,
+					}).Shim(),
+					Optional: true,
+				}).Shim(),
+				"nested_resources": (&tfplugin.AttributeSchema{
+					CtyType: cty.List(cty.Object(map[string]cty.Type{
 						"opt_bool":      cty.Bool,
 						"kind":          cty.String,
 						"configuration": cty.Map(cty.String),
 					})),
-					valueType: shim.TypeList,
-					elem: &resource{
-						ctyType: cty.Object(map[string]cty.Type{
+					ValueType: shim.TypeList,
+					Elem: (&tfplugin.Resource{
+						CtyType: cty.Object(map[string]cty.Type{
 							"opt_bool":      cty.Bool,
 							"kind":          cty.String,
 							"configuration": cty.Map(cty.String),
 						}),
-						schema: schema.SchemaMap{
-							"opt_bool": &attributeSchema{
-								ctyType:   cty.Bool,
-								valueType: shim.TypeBool,
-								optional:  true,
-							},
-							"kind": &attributeSchema{
-								ctyType:   cty.String,
-								valueType: shim.TypeString,
-								optional:  true,
-							},
-							"configuration": &attributeSchema{
-								ctyType:   cty.Map(cty.String),
-								valueType: shim.TypeMap,
-								elem: &attributeSchema{
-									ctyType:   cty.String,
-									valueType: shim.TypeString,
-								},
-								required: true,
-							},
+						Schema: schema.SchemaMap{
+							"opt_bool": (&tfplugin.AttributeSchema{
+								CtyType:   cty.Bool,
+								ValueType: shim.TypeBool,
+								Optional:  true,
+							}).Shim(),
+							"kind": (&tfplugin.AttributeSchema{
+								CtyType:   cty.String,
+								ValueType: shim.TypeString,
+								Optional:  true,
+							}).Shim(),
+							"configuration": (&tfplugin.AttributeSchema{
+								CtyType:   cty.Map(cty.String),
+								ValueType: shim.TypeMap,
+								Elem: (&tfplugin.AttributeSchema{
+									CtyType:   cty.String,
+									ValueType: shim.TypeString,
+								}).Shim(),
+								Required: true,
+							}).Shim(),
 						},
-					},
-					maxItems: 1,
-					optional: true,
-				},
-				"set_property_value": &attributeSchema{
-					ctyType:   cty.Set(cty.String),
-					valueType: shim.TypeSet,
-					elem: &attributeSchema{
-						ctyType:   cty.String,
-						valueType: shim.TypeString,
-					},
-					optional: true,
-				},
-				"string_with_bad_interpolation": &attributeSchema{
-					ctyType:   cty.String,
-					valueType: shim.TypeString,
-					optional:  true,
-				},
+					}).Shim(),
+					MaxItems: 1,
+					Nesting:  tfplugin.NestingList,
+					Optional: true,
+				}).Shim(),
+				"set_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Set(cty.String),
+					ValueType: shim.TypeSet,
+					Elem: (&tfplugin.AttributeSchema{
+						CtyType:   cty.String,
+						ValueType: shim.TypeString,
+					}).Shim(),
+					Optional: true,
+				}).Shim(),
+				"string_with_bad_interpolation": (&tfplugin.AttributeSchema{
+					CtyType:   cty.String,
+					ValueType: shim.TypeString,
+					Optional:  true,
+				}).Shim(),
 			},
 		},
 		"second_resource": {
-			resourceType:  "second_resource",
-			schemaVersion: 1,
-			ctyType: cty.Object(map[string]cty.Type{
+			ResourceType:  "second_resource",
+			SchemaVersion: 1,
+			CtyType: cty.Object(map[string]cty.Type{
 				"id": cty.String,
 				"timeouts": cty.Object(map[string]cty.Type{
 					"create": cty.String,
@@ -449,138 +492,140 @@ func TestProviderResourcesMap(t *testing.T) {
 				"conflicting_property2":               cty.String,
 				"conflicting_property_unidirectional": cty.Bool,
 			}),
-			schema: schema.SchemaMap{
-				"id": &attributeSchema{
-					ctyType:   cty.String,
-					valueType: shim.TypeString,
-					computed:  true,
-				},
-				"timeouts": &attributeSchema{
-					ctyType: cty.Object(map[string]cty.Type{
+			Schema: schema.SchemaMap{
+				"id": (&tfplugin.AttributeSchema{
+					CtyType:   cty.String,
+					ValueType: shim.TypeString,
+					Computed:  true,
+				}).Shim(),
+				"timeouts": (&tfplugin.AttributeSchema{
+					CtyType: cty.Object(map[string]cty.Type{
 						"create": cty.String,
 						"update": cty.String,
 					}),
-					valueType: shim.TypeMap,
-					elem: &resource{
-						ctyType: cty.Object(map[string]cty.Type{
+					ValueType: shim.TypeMap,
+					Elem: (&tfplugin.Resource{
+						CtyType: cty.Object(map[string]cty.Type{
 							"create": cty.String,
 							"update": cty.String,
 						}),
-						schema: schema.SchemaMap{
-							"create": &attributeSchema{
-								ctyType:   cty.String,
-								valueType: shim.TypeString,
-								optional:  true,
-							},
-							"update": &attributeSchema{
-								ctyType:   cty.String,
-								valueType: shim.TypeString,
-								optional:  true,
-							},
+						Schema: schema.SchemaMap{
+							"create": (&tfplugin.AttributeSchema{
+								CtyType:   cty.String,
+								ValueType: shim.TypeString,
+								Optional:  true,
+							}).Shim(),
+							"update": (&tfplugin.AttributeSchema{
+								CtyType:   cty.String,
+								ValueType: shim.TypeString,
+								Optional:  true,
+							}).Shim(),
 						},
-					},
-					required: true,
-				},
-				"nil_property_value": &attributeSchema{
-					ctyType:   cty.Map(cty.String),
-					valueType: shim.TypeMap,
-					elem: &attributeSchema{
-						ctyType:   cty.String,
-						valueType: shim.TypeString,
-					},
-					optional: true,
-				},
-				"bool_property_value": &attributeSchema{
-					ctyType:   cty.Bool,
-					valueType: shim.TypeBool,
-					optional:  true,
-				},
-				"number_property_value": &attributeSchema{
-					ctyType:   cty.Number,
-					valueType: shim.TypeFloat,
-					optional:  true,
-				},
-				"float_property_value": &attributeSchema{
-					ctyType:   cty.Number,
-					valueType: shim.TypeFloat,
-					optional:  true,
-				},
-				"string_property_value": &attributeSchema{
-					ctyType:   cty.String,
-					valueType: shim.TypeString,
-					optional:  true,
-				},
-				"array_property_value": &attributeSchema{
-					ctyType:   cty.List(cty.String),
-					valueType: shim.TypeList,
-					elem: &attributeSchema{
-						ctyType:   cty.String,
-						valueType: shim.TypeString,
-					},
-					required: true,
-				},
-				"object_property_value": &attributeSchema{
-					ctyType:   cty.Map(cty.String),
-					valueType: shim.TypeMap,
-					elem: &attributeSchema{
-						ctyType:   cty.String,
-						valueType: shim.TypeString,
-					},
-					optional: true,
-				},
-				"nested_resources": &attributeSchema{
-					ctyType: cty.List(cty.Object(map[string]cty.Type{
+					}).Shim(),
+					Required: true,
+					Nesting:  tfplugin.NestingSingle,
+				}).Shim(),
+				"nil_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Map(cty.String),
+					ValueType: shim.TypeMap,
+					Elem: (&tfplugin.AttributeSchema{
+						CtyType:   cty.String,
+						ValueType: shim.TypeString,
+					}).Shim(),
+					Optional: true,
+				}).Shim(),
+				"bool_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Bool,
+					ValueType: shim.TypeBool,
+					Optional:  true,
+				}).Shim(),
+				"number_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Number,
+					ValueType: shim.TypeFloat,
+					Optional:  true,
+				}).Shim(),
+				"float_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Number,
+					ValueType: shim.TypeFloat,
+					Optional:  true,
+				}).Shim(),
+				"string_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.String,
+					ValueType: shim.TypeString,
+					Optional:  true,
+				}).Shim(),
+				"array_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.List(cty.String),
+					ValueType: shim.TypeList,
+					Elem: (&tfplugin.AttributeSchema{
+						CtyType:   cty.String,
+						ValueType: shim.TypeString,
+					}).Shim(),
+					Required: true,
+				}).Shim(),
+				"object_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Map(cty.String),
+					ValueType: shim.TypeMap,
+					Elem: (&tfplugin.AttributeSchema{
+						CtyType:   cty.String,
+						ValueType: shim.TypeString,
+					}).Shim(),
+					Optional: true,
+				}).Shim(),
+				"nested_resources": (&tfplugin.AttributeSchema{
+					CtyType: cty.List(cty.Object(map[string]cty.Type{
 						"configuration": cty.Map(cty.String),
 					})),
-					valueType: shim.TypeList,
-					elem: &resource{
-						ctyType: cty.Object(map[string]cty.Type{
+					ValueType: shim.TypeList,
+					Elem: (&tfplugin.Resource{
+						CtyType: cty.Object(map[string]cty.Type{
 							"configuration": cty.Map(cty.String),
 						}),
-						schema: schema.SchemaMap{
-							"configuration": &attributeSchema{
-								ctyType:   cty.Map(cty.String),
-								valueType: shim.TypeMap,
-								elem: &attributeSchema{
-									ctyType:   cty.String,
-									valueType: shim.TypeString,
-								},
-								required: true,
-							},
+						Schema: schema.SchemaMap{
+							"configuration": (&tfplugin.AttributeSchema{
+								CtyType:   cty.Map(cty.String),
+								ValueType: shim.TypeMap,
+								Elem: (&tfplugin.AttributeSchema{
+									CtyType:   cty.String,
+									ValueType: shim.TypeString,
+								}).Shim(),
+								Required: true,
+							}).Shim(),
 						},
-					},
-					maxItems: 1,
-					optional: true,
-				},
-				"set_property_value": &attributeSchema{
-					ctyType:   cty.Set(cty.String),
-					valueType: shim.TypeSet,
-					elem: &attributeSchema{
-						ctyType:   cty.String,
-						valueType: shim.TypeString,
-					},
-					optional: true,
-				},
-				"string_with_bad_interpolation": &attributeSchema{
-					ctyType:   cty.String,
-					valueType: shim.TypeString,
-					optional:  true,
-				},
-				"conflicting_property": &attributeSchema{
-					ctyType:   cty.String,
-					valueType: shim.TypeString,
-					optional:  true,
-				},
-				"conflicting_property2": &attributeSchema{
-					ctyType:   cty.String,
-					valueType: shim.TypeString,
-					optional:  true,
-				},
-				"conflicting_property_unidirectional": &attributeSchema{
-					ctyType:   cty.Bool,
-					valueType: shim.TypeBool,
-					optional:  true,
-				},
+					}).Shim(),
+					MaxItems: 1,
+					Nesting:  tfplugin.NestingList,
+					Optional: true,
+				}).Shim(),
+				"set_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Set(cty.String),
+					ValueType: shim.TypeSet,
+					Elem: (&tfplugin.AttributeSchema{
+						CtyType:   cty.String,
+						ValueType: shim.TypeString,
+					}).Shim(),
+					Optional: true,
+				}).Shim(),
+				"string_with_bad_interpolation": (&tfplugin.AttributeSchema{
+					CtyType:   cty.String,
+					ValueType: shim.TypeString,
+					Optional:  true,
+				}).Shim(),
+				"conflicting_property": (&tfplugin.AttributeSchema{
+					CtyType:   cty.String,
+					ValueType: shim.TypeString,
+					Optional:  true,
+				}).Shim(),
+				"conflicting_property2": (&tfplugin.AttributeSchema{
+					CtyType:   cty.String,
+					ValueType: shim.TypeString,
+					Optional:  true,
+				}).Shim(),
+				"conflicting_property_unidirectional": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Bool,
+					ValueType: shim.TypeBool,
+					Optional:  true,
+				}).Shim(),
 			},
 		},
 	}
@@ -594,11 +639,11 @@ func TestProviderResourcesMap(t *testing.T) {
 		names[name] = true
 
 		// Ignore the provider field of both resources.
-		actual := v.(*resource)
-		assert.Equal(t, expected.resourceType, actual.resourceType)
-		assert.Equal(t, expected.ctyType, actual.ctyType)
-		assert.Equal(t, expected.schema, actual.schema)
-		assert.Equal(t, expected.schemaVersion, actual.schemaVersion)
+		actual := v.(tfplugin.ResourceShim).V
+		assert.Equal(t, expected.ResourceType, actual.ResourceType)
+		assert.Equal(t, expected.CtyType, actual.CtyType)
+		assert.Equal(t, expected.Schema, actual.Schema)
+		assert.Equal(t, expected.SchemaVersion, actual.SchemaVersion)
 		return true
 	})
 
@@ -614,11 +659,11 @@ func TestProviderDataSourcesMap(t *testing.T) {
 		return
 	}
 
-	expected := map[string]*resource{
+	expected := map[string]*tfplugin.Resource{
 		"example_resource": {
-			resourceType:  "example_resource",
-			schemaVersion: 1,
-			ctyType: cty.Object(map[string]cty.Type{
+			ResourceType:  "example_resource",
+			SchemaVersion: 1,
+			CtyType: cty.Object(map[string]cty.Type{
 				"id":                    cty.String,
 				"nil_property_value":    cty.Map(cty.String),
 				"bool_property_value":   cty.Bool,
@@ -634,106 +679,107 @@ func TestProviderDataSourcesMap(t *testing.T) {
 				"set_property_value":            cty.Set(cty.String),
 				"string_with_bad_interpolation": cty.String,
 			}),
-			schema: schema.SchemaMap{
-				"id": &attributeSchema{
-					ctyType:   cty.String,
-					valueType: shim.TypeString,
-					computed:  true,
-				},
-				"nil_property_value": &attributeSchema{
-					ctyType:   cty.Map(cty.String),
-					valueType: shim.TypeMap,
-					elem: &attributeSchema{
-						ctyType:   cty.String,
-						valueType: shim.TypeString,
-					},
-					optional: true,
-				},
-				"bool_property_value": &attributeSchema{
-					ctyType:   cty.Bool,
-					valueType: shim.TypeBool,
-					optional:  true,
-				},
-				"number_property_value": &attributeSchema{
-					ctyType:   cty.Number,
-					valueType: shim.TypeFloat,
-					optional:  true,
-				},
-				"float_property_value": &attributeSchema{
-					ctyType:   cty.Number,
-					valueType: shim.TypeFloat,
-					optional:  true,
-				},
-				"string_property_value": &attributeSchema{
-					ctyType:   cty.String,
-					valueType: shim.TypeString,
-					optional:  true,
-				},
-				"array_property_value": &attributeSchema{
-					ctyType:   cty.List(cty.String),
-					valueType: shim.TypeList,
-					elem: &attributeSchema{
-						ctyType:   cty.String,
-						valueType: shim.TypeString,
-					},
-					required: true,
-				},
-				"object_property_value": &attributeSchema{
-					ctyType:   cty.Map(cty.String),
-					valueType: shim.TypeMap,
-					elem: &attributeSchema{
-						ctyType:   cty.String,
-						valueType: shim.TypeString,
-					},
-					optional: true,
-				},
-				"map_property_value": &attributeSchema{
-					ctyType:   cty.Map(cty.String),
-					valueType: shim.TypeMap,
-					elem: &attributeSchema{
-						ctyType:   cty.String,
-						valueType: shim.TypeString,
-					},
-					optional: true,
-				},
-				"nested_resources": &attributeSchema{
-					ctyType: cty.List(cty.Object(map[string]cty.Type{
+			Schema: schema.SchemaMap{
+				"id": (&tfplugin.AttributeSchema{
+					CtyType:   cty.String,
+					ValueType: shim.TypeString,
+					Computed:  true,
+				}).Shim(),
+				"nil_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Map(cty.String),
+					ValueType: shim.TypeMap,
+					Elem: (&tfplugin.AttributeSchema{
+						CtyType:   cty.String,
+						ValueType: shim.TypeString,
+					}).Shim(),
+					Optional: true,
+				}).Shim(),
+				"bool_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Bool,
+					ValueType: shim.TypeBool,
+					Optional:  true,
+				}).Shim(),
+				"number_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Number,
+					ValueType: shim.TypeFloat,
+					Optional:  true,
+				}).Shim(),
+				"float_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Number,
+					ValueType: shim.TypeFloat,
+					Optional:  true,
+				}).Shim(),
+				"string_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.String,
+					ValueType: shim.TypeString,
+					Optional:  true,
+				}).Shim(),
+				"array_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.List(cty.String),
+					ValueType: shim.TypeList,
+					Elem: (&tfplugin.AttributeSchema{
+						CtyType:   cty.String,
+						ValueType: shim.TypeString,
+					}).Shim(),
+					Required: true,
+				}).Shim(),
+				"object_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Map(cty.String),
+					ValueType: shim.TypeMap,
+					Elem: (&tfplugin.AttributeSchema{
+						CtyType:   cty.String,
+						ValueType: shim.TypeString,
+					}).Shim(),
+					Optional: true,
+				}).Shim(),
+				"map_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Map(cty.String),
+					ValueType: shim.TypeMap,
+					Elem: (&tfplugin.AttributeSchema{
+						CtyType:   cty.String,
+						ValueType: shim.TypeString,
+					}).Shim(),
+					Optional: true,
+				}).Shim(),
+				"nested_resources": (&tfplugin.AttributeSchema{
+					CtyType: cty.List(cty.Object(map[string]cty.Type{
 						"configuration": cty.Map(cty.String),
 					})),
-					valueType: shim.TypeList,
-					elem: &resource{
-						ctyType: cty.Object(map[string]cty.Type{
+					ValueType: shim.TypeList,
+					Elem: (&tfplugin.Resource{
+						CtyType: cty.Object(map[string]cty.Type{
 							"configuration": cty.Map(cty.String),
 						}),
-						schema: schema.SchemaMap{
-							"configuration": &attributeSchema{
-								ctyType:   cty.Map(cty.String),
-								valueType: shim.TypeMap,
-								elem: &attributeSchema{
-									ctyType:   cty.String,
-									valueType: shim.TypeString,
-								},
-								required: true,
-							},
+						Schema: schema.SchemaMap{
+							"configuration": (&tfplugin.AttributeSchema{
+								CtyType:   cty.Map(cty.String),
+								ValueType: shim.TypeMap,
+								Elem: (&tfplugin.AttributeSchema{
+									CtyType:   cty.String,
+									ValueType: shim.TypeString,
+								}).Shim(),
+								Required: true,
+							}).Shim(),
 						},
-					},
-					maxItems: 1,
-					optional: true,
-				},
-				"set_property_value": &attributeSchema{
-					ctyType:   cty.Set(cty.String),
-					valueType: shim.TypeSet,
-					elem: &attributeSchema{
-						ctyType:   cty.String,
-						valueType: shim.TypeString,
-					},
-					optional: true,
-				},
-				"string_with_bad_interpolation": &attributeSchema{
-					ctyType:   cty.String,
-					valueType: shim.TypeString,
-					optional:  true,
-				},
+					}).Shim(),
+					MaxItems: 1,
+					Nesting:  tfplugin.NestingList,
+					Optional: true,
+				}).Shim(),
+				"set_property_value": (&tfplugin.AttributeSchema{
+					CtyType:   cty.Set(cty.String),
+					ValueType: shim.TypeSet,
+					Elem: (&tfplugin.AttributeSchema{
+						CtyType:   cty.String,
+						ValueType: shim.TypeString,
+					}).Shim(),
+					Optional: true,
+				}).Shim(),
+				"string_with_bad_interpolation": (&tfplugin.AttributeSchema{
+					CtyType:   cty.String,
+					ValueType: shim.TypeString,
+					Optional:  true,
+				}).Shim(),
 			},
 		},
 	}
@@ -747,11 +793,11 @@ func TestProviderDataSourcesMap(t *testing.T) {
 		names[name] = true
 
 		// Ignore the provider field of both resources.
-		actual := v.(*resource)
-		assert.Equal(t, expected.resourceType, actual.resourceType)
-		assert.Equal(t, expected.ctyType, actual.ctyType)
-		assert.Equal(t, expected.schema, actual.schema)
-		assert.Equal(t, expected.schemaVersion, actual.schemaVersion)
+		actual := v.(tfplugin.ResourceShim).V
+		assert.Equal(t, expected.ResourceType, actual.ResourceType)
+		assert.Equal(t, expected.CtyType, actual.CtyType)
+		assert.Equal(t, expected.Schema, actual.Schema)
+		assert.Equal(t, expected.SchemaVersion, actual.SchemaVersion)
 		return true
 	})
 
@@ -868,7 +914,7 @@ func TestConfigure(t *testing.T) {
 		return
 	}
 
-	err := p.Configure(p.NewResourceConfig(map[string]interface{}{
+	_, err := p.Configure(p.NewResourceConfig(map[string]interface{}{
 		"config_value": "foo",
 	}))
 	assert.NoError(t, err)
@@ -883,7 +929,7 @@ func TestDiff(t *testing.T) {
 	res, ok := p.ResourcesMap().GetOk("example_resource")
 	require.True(t, ok)
 
-	err := p.Configure(p.NewResourceConfig(map[string]interface{}{
+	_, err := p.Configure(p.NewResourceConfig(map[string]interface{}{
 		"config_value": "foo",
 	}))
 	require.NoError(t, err)
@@ -999,12 +1045,12 @@ func TestDiff(t *testing.T) {
 				"string_with_bad_interpolation": cty.NullVal(cty.String),
 			}
 			for k, v := range c.state {
-				val, err := goToCty(v, expected[k].Type())
+				val, err := tfplugin.GoToCty(v, expected[k].Type())
 				require.NoError(t, err)
 				expected[k] = val
 			}
 			for k, v := range c.config {
-				val, err := goToCty(v, expected[k].Type())
+				val, err := tfplugin.GoToCty(v, expected[k].Type())
 				require.NoError(t, err)
 				expected[k] = val
 			}
@@ -1021,10 +1067,8 @@ func TestDiff(t *testing.T) {
 			require.NoError(t, err)
 
 			config := p.NewResourceConfig(c.config)
-			configVal, err := goToCty(config, res.(*resource).ctyType)
-			require.NoError(t, err)
 
-			diff, err := p.Diff("example_resource", state, config)
+			diff, _, err := p.Diff("example_resource", state, config)
 			require.NoError(t, err)
 
 			var meta map[string]interface{}
@@ -1037,13 +1081,14 @@ func TestDiff(t *testing.T) {
 				}
 			}
 
-			assert.Equal(t, &instanceDiff{
-				config:      configVal,
-				planned:     cty.ObjectVal(expected),
-				attributes:  c.attributes,
-				requiresNew: requiresNew,
-				meta:        meta,
-			}, diff)
+			assert.Equal(t, c.attributes, diff.Attributes())
+			assert.Equal(t, requiresNew, diff.RequiresNew())
+
+			planned, err := diff.ProposedState(res, state)
+			require.NoError(t, err)
+			expectedObject, err := tfplugin.CtyToGo(cty.ObjectVal(expected))
+			require.NoError(t, err)
+			assertInstanceState(t, "example_resource", "0", expectedObject.(map[string]interface{}), meta, planned)
 		})
 	}
 }
@@ -1057,7 +1102,7 @@ func TestApply(t *testing.T) {
 	resource, ok := p.ResourcesMap().GetOk("example_resource")
 	require.True(t, ok)
 
-	err := p.Configure(p.NewResourceConfig(map[string]interface{}{
+	_, err := p.Configure(p.NewResourceConfig(map[string]interface{}{
 		"config_value": "foo",
 	}))
 	require.NoError(t, err)
@@ -1143,12 +1188,12 @@ func TestApply(t *testing.T) {
 				"string_with_bad_interpolation": cty.StringVal("some ${interpolated:value} with syntax errors"),
 			}
 			for k, v := range c.state {
-				val, err := goToCty(v, expected[k].Type())
+				val, err := tfplugin.GoToCty(v, expected[k].Type())
 				require.NoError(t, err)
 				expected[k] = val
 			}
 			for k, v := range c.config {
-				val, err := goToCty(v, expected[k].Type())
+				val, err := tfplugin.GoToCty(v, expected[k].Type())
 				require.NoError(t, err)
 				expected[k] = val
 			}
@@ -1158,7 +1203,7 @@ func TestApply(t *testing.T) {
 
 			config := p.NewResourceConfig(c.config)
 
-			diff, err := p.Diff("example_resource", state, config)
+			diff, _, err := p.Diff("example_resource", state, config)
 			require.NoError(t, err)
 
 			if len(diff.Attributes()) == 0 {
@@ -1196,7 +1241,7 @@ func TestApply(t *testing.T) {
 					"string_with_bad_interpolation": cty.StringVal("some ${interpolated:value} with syntax errors"),
 				}
 				for k, v := range c.config {
-					val, err := goToCty(v, expected[k].Type())
+					val, err := tfplugin.GoToCty(v, expected[k].Type())
 					require.NoError(t, err)
 					expected[k] = val
 				}
@@ -1204,26 +1249,21 @@ func TestApply(t *testing.T) {
 				state, err = resource.InstanceState("", map[string]interface{}{}, nil)
 				require.NoError(t, err)
 
-				diff, err = p.Diff("example_resource", state, config)
+				diff, _, err = p.Diff("example_resource", state, config)
 				require.NoError(t, err)
 			}
 
-			state, err = p.Apply("example_resource", state, diff)
+			state, _, err = p.Apply("example_resource", state, diff)
 			require.NoError(t, err)
 
-			expectedObject, err := ctyToGo(cty.ObjectVal(expected))
+			expectedObject, err := tfplugin.CtyToGo(cty.ObjectVal(expected))
 			require.NoError(t, err)
 
-			assert.Equal(t, &instanceState{
-				resourceType: "example_resource",
-				id:           "0",
-				object:       expectedObject.(map[string]interface{}),
-				meta: map[string]interface{}{
-					timeoutsKey: map[string]interface{}{
-						"create": float64(1.2e11),
-					},
-					"schema_version": "1",
+			assertInstanceState(t, "example_resource", "0", expectedObject.(map[string]interface{}), map[string]interface{}{
+				timeoutsKey: map[string]interface{}{
+					"create": float64(1.2e11),
 				},
+				"schema_version": "1",
 			}, state)
 		})
 	}
@@ -1238,7 +1278,7 @@ func TestRefresh(t *testing.T) {
 	resource, ok := p.ResourcesMap().GetOk("example_resource")
 	require.True(t, ok)
 
-	err := p.Configure(p.NewResourceConfig(map[string]interface{}{
+	_, err := p.Configure(p.NewResourceConfig(map[string]interface{}{
 		"config_value": "foo",
 	}))
 	require.NoError(t, err)
@@ -1283,18 +1323,13 @@ func TestRefresh(t *testing.T) {
 		"string_with_bad_interpolation": cty.StringVal("some ${interpolated:value} with syntax errors"),
 	}
 
-	state, err = p.Refresh("example_resource", state)
+	state, _, err = p.Refresh("example_resource", state)
 	require.NoError(t, err)
 
-	expectedObject, err := ctyToGo(cty.ObjectVal(expected))
+	expectedObject, err := tfplugin.CtyToGo(cty.ObjectVal(expected))
 	require.NoError(t, err)
 
-	assert.Equal(t, &instanceState{
-		resourceType: "example_resource",
-		id:           "0",
-		object:       expectedObject.(map[string]interface{}),
-		meta:         meta,
-	}, state)
+	assertInstanceState(t, "example_resource", "0", expectedObject.(map[string]interface{}), meta, state)
 }
 
 func TestReadDataDiff(t *testing.T) {
@@ -1307,7 +1342,7 @@ func TestReadDataDiff(t *testing.T) {
 		"array_property_value": []interface{}{"foo"},
 	})
 
-	diff, err := p.ReadDataDiff("example_resource", config)
+	diff, _, err := p.ReadDataDiff("example_resource", config)
 	require.NoError(t, err)
 
 	expected := cty.ObjectVal(map[string]cty.Value{
@@ -1327,7 +1362,14 @@ func TestReadDataDiff(t *testing.T) {
 		"string_with_bad_interpolation": cty.NullVal(cty.String),
 	})
 
-	assert.Equal(t, &instanceDiff{planned: expected}, diff)
+	assert.Empty(t, diff.Attributes())
+	assert.False(t, diff.Destroy())
+
+	planned, err := diff.ProposedState(p.DataSourcesMap().Get("example_resource"), nil)
+	require.NoError(t, err)
+	expectedObject, err := tfplugin.CtyToGo(expected)
+	require.NoError(t, err)
+	assertInstanceState(t, "example_resource", "", expectedObject.(map[string]interface{}), nil, planned)
 }
 
 func TestReadDataApply(t *testing.T) {
@@ -1340,10 +1382,10 @@ func TestReadDataApply(t *testing.T) {
 		"array_property_value": []interface{}{"foo"},
 	})
 
-	diff, err := p.ReadDataDiff("example_resource", config)
+	diff, _, err := p.ReadDataDiff("example_resource", config)
 	require.NoError(t, err)
 
-	state, err := p.ReadDataApply("example_resource", diff)
+	state, _, err := p.ReadDataApply("example_resource", diff)
 	require.NoError(t, err)
 
 	expected := cty.ObjectVal(map[string]cty.Value{
@@ -1371,14 +1413,10 @@ func TestReadDataApply(t *testing.T) {
 		}),
 		"string_with_bad_interpolation": cty.StringVal("some ${interpolated:value} with syntax errors"),
 	})
-	expectedObject, err := ctyToGo(expected)
+	expectedObject, err := tfplugin.CtyToGo(expected)
 	require.NoError(t, err)
 
-	assert.Equal(t, &instanceState{
-		resourceType: "example_resource",
-		id:           "0",
-		object:       expectedObject.(map[string]interface{}),
-	}, state)
+	assertInstanceState(t, "example_resource", "0", expectedObject.(map[string]interface{}), nil, state)
 }
 
 func TestImportResourceState(t *testing.T) {
@@ -1416,18 +1454,27 @@ func TestImportResourceState(t *testing.T) {
 		"set_property_value":            cty.NullVal(cty.Set(cty.String)),
 		"string_with_bad_interpolation": cty.NullVal(cty.String),
 	})
-	expectedObject, err := ctyToGo(expected)
+	expectedObject, err := tfplugin.CtyToGo(expected)
 	require.NoError(t, err)
 
-	assert.Equal(t, &instanceState{
-		resourceType: "example_resource",
-		id:           "0",
-		object:       expectedObject.(map[string]interface{}),
-		meta: map[string]interface{}{
-			timeoutsKey: map[string]interface{}{
-				"create": float64(1.2e11),
-			},
-			"schema_version": "1",
+	assertInstanceState(t, "example_resource", "0", expectedObject.(map[string]interface{}), map[string]interface{}{
+		timeoutsKey: map[string]interface{}{
+			"create": float64(1.2e11),
 		},
+		"schema_version": "1",
 	}, state)
 }
+
+func TestSetProviderMeta(t *testing.T) {
+	p, ok := startTestProvider(t)
+	if !ok {
+		return
+	}
+
+	// The test provider's provider_meta block is empty.
+	err := p.SetProviderMeta(map[string]interface{}{"module_name": "pulumi"})
+	assert.EqualError(t, err, `provider_meta has no attribute "module_name"`)
+	assert.Nil(t, p.Meta())
+
+	assert.NoError(t, p.SetProviderMeta(nil))
+}
@@ -1,115 +1,79 @@
 package tfplugin5
 
 import (
-	"github.com/hashicorp/go-cty/cty"
-
-	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
-	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
 )
 
 // UnknownVariableValue is the sentinal defined in github.com/hashicorp/terraform/configs/hcl2shim,
-// representing a variable whose value is not known at some particular time. The value is duplicated here in
-// order to prevent an additional dependency - it is unlikely to ever change upstream since that would break
-// rather a lot of things.
-const UnknownVariableValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
-
-type attributeSchema struct {
-	ctyType     cty.Type
-	valueType   shim.ValueType
-	optional    bool
-	required    bool
-	description string
-	computed    bool
-	forceNew    bool
-	elem        interface{}
-	maxItems    int
-	minItems    int
-	deprecated  string
-	sensitive   bool
-}
-
-func (s *attributeSchema) Type() shim.ValueType {
-	return s.valueType
-}
-
-func (s *attributeSchema) Optional() bool {
-	return s.optional
-}
-
-func (s *attributeSchema) Required() bool {
-	return s.required
-}
-
-func (s *attributeSchema) Default() interface{} {
-	return nil
-}
-
-func (s *attributeSchema) DefaultFunc() shim.SchemaDefaultFunc {
-	return nil
-}
-
-func (s *attributeSchema) DefaultValue() (interface{}, error) {
-	return nil, nil
-}
-
-func (s *attributeSchema) Description() string {
-	return s.description
-}
-
-func (s *attributeSchema) Computed() bool {
-	return s.computed
-}
-
-func (s *attributeSchema) ForceNew() bool {
-	return s.forceNew
-}
+// representing a variable whose value is not known at some particular time.
+const UnknownVariableValue = tfplugin.UnknownVariableValue
 
-func (s *attributeSchema) StateFunc() shim.SchemaStateFunc {
-	return nil
-}
-
-func (s *attributeSchema) Elem() interface{} {
-	return s.elem
-}
-
-func (s *attributeSchema) MaxItems() int {
-	return s.maxItems
-}
-
-func (s *attributeSchema) MinItems() int {
-	return s.minItems
-}
-
-func (s *attributeSchema) ConflictsWith() []string {
-	return nil
-}
-
-func (s *attributeSchema) Removed() string {
-	return ""
-}
-
-func (s *attributeSchema) Deprecated() string {
-	return s.deprecated
+func unmarshalSchema(schema *proto.Schema) *tfplugin.Schema {
+	if schema == nil {
+		return nil
+	}
+	return &tfplugin.Schema{
+		Version: schema.GetVersion(),
+		Block:   unmarshalBlock(schema.GetBlock()),
+	}
 }
 
-func (s *attributeSchema) Sensitive() bool {
-	return s.sensitive
-}
+func unmarshalSchemaMap(schemas map[string]*proto.Schema) map[string]*tfplugin.Schema {
+	result := make(map[string]*tfplugin.Schema, len(schemas))
+	for name, schema := range schemas {
+		result[name] = unmarshalSchema(schema)
+	}
+	return result
+}
+
+func unmarshalBlock(block *proto.Schema_Block) *tfplugin.Block {
+	attributes := make([]*tfplugin.Attribute, len(block.GetAttributes()))
+	for i, attribute := range block.GetAttributes() {
+		attributes[i] = &tfplugin.Attribute{
+			Name:        attribute.GetName(),
+			Type:        attribute.GetType(),
+			Description: attribute.GetDescription(),
+			Required:    attribute.GetRequired(),
+			Optional:    attribute.GetOptional(),
+			Computed:    attribute.GetComputed(),
+			Sensitive:   attribute.GetSensitive(),
+			Deprecated:  attribute.GetDeprecated(),
+		}
+	}
 
-func (s *attributeSchema) UnknownValue() interface{} {
-	return UnknownVariableValue
-}
+	blockTypes := make([]*tfplugin.NestedBlock, len(block.GetBlockTypes()))
+	for i, nestedBlock := range block.GetBlockTypes() {
+		blockTypes[i] = &tfplugin.NestedBlock{
+			TypeName: nestedBlock.GetTypeName(),
+			Block:    unmarshalBlock(nestedBlock.GetBlock()),
+			Nesting:  unmarshalNestingMode(nestedBlock.GetNesting()),
+			MinItems: nestedBlock.GetMinItems(),
+			MaxItems: nestedBlock.GetMaxItems(),
+		}
+	}
 
-func (s *attributeSchema) SetElement(v interface{}) (interface{}, error) {
-	val, err := goToCty(v, s.ctyType)
-	if err != nil {
-		return nil, err
+	return &tfplugin.Block{
+		Attributes:  attributes,
+		BlockTypes:  blockTypes,
+		Description: block.GetDescription(),
+		Deprecated:  block.GetDeprecated(),
 	}
-	return val, nil
 }
 
-func (s *attributeSchema) SetHash(v interface{}) int {
-	val, ok := v.(cty.Value)
-	contract.Assertf(ok, "internal error: SetHash must be a cty.Value")
-	return val.Hash()
+func unmarshalNestingMode(nesting proto.Schema_NestedBlock_NestingMode) tfplugin.NestingMode {
+	switch nesting {
+	case proto.Schema_NestedBlock_SINGLE:
+		return tfplugin.NestingSingle
+	case proto.Schema_NestedBlock_GROUP:
+		return tfplugin.NestingGroup
+	case proto.Schema_NestedBlock_LIST:
+		return tfplugin.NestingList
+	case proto.Schema_NestedBlock_SET:
+		return tfplugin.NestingSet
+	case proto.Schema_NestedBlock_MAP:
+		return tfplugin.NestingMap
+	default:
+		return tfplugin.NestingInvalid
+	}
 }
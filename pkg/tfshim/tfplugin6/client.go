@@ -0,0 +1,221 @@
+package tfplugin6
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin6/proto"
+)
+
+// client adapts a protocol version 6 provider client to the protocol-independent client used by the provider shim.
+type client struct {
+	client proto.ProviderClient
+}
+
+var _ = tfplugin.Client(client{})
+
+func dynamicValue(msgpack []byte) *proto.DynamicValue {
+	if msgpack == nil {
+		return nil
+	}
+	return &proto.DynamicValue{Msgpack: msgpack}
+}
+
+func attributePaths(paths []*proto.AttributePath) []cty.Path {
+	result := make([]cty.Path, len(paths))
+	for i, path := range paths {
+		result[i] = pathToCty(path)
+	}
+	return result
+}
+
+func (c client) GetProviderSchema(ctx context.Context) (*tfplugin.ProviderSchema, error) {
+	resp, err := c.client.GetProviderSchema(ctx, &proto.GetProviderSchema_Request{})
+	if err != nil {
+		return nil, err
+	}
+	return &tfplugin.ProviderSchema{
+		Provider:          unmarshalSchema(resp.GetProvider()),
+		ProviderMeta:      unmarshalSchema(resp.GetProviderMeta()),
+		ResourceSchemas:   unmarshalSchemaMap(resp.GetResourceSchemas()),
+		DataSourceSchemas: unmarshalSchemaMap(resp.GetDataSourceSchemas()),
+	}, nil
+}
+
+func (c client) ValidateProviderConfig(ctx context.Context, config []byte) (diagnostics.Diagnostics, error) {
+	resp, err := c.client.ValidateProviderConfig(ctx, &proto.ValidateProviderConfig_Request{
+		Config: dynamicValue(config),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalDiagnostics(resp.GetDiagnostics()), nil
+}
+
+func (c client) ValidateResourceConfig(ctx context.Context, typeName string,
+	config []byte) (diagnostics.Diagnostics, error) {
+
+	resp, err := c.client.ValidateResourceConfig(ctx, &proto.ValidateResourceConfig_Request{
+		TypeName: typeName,
+		Config:   dynamicValue(config),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalDiagnostics(resp.GetDiagnostics()), nil
+}
+
+func (c client) ValidateDataResourceConfig(ctx context.Context, typeName string,
+	config []byte) (diagnostics.Diagnostics, error) {
+
+	resp, err := c.client.ValidateDataResourceConfig(ctx, &proto.ValidateDataResourceConfig_Request{
+		TypeName: typeName,
+		Config:   dynamicValue(config),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalDiagnostics(resp.GetDiagnostics()), nil
+}
+
+func (c client) ConfigureProvider(ctx context.Context, terraformVersion string,
+	config []byte) (diagnostics.Diagnostics, error) {
+
+	resp, err := c.client.ConfigureProvider(ctx, &proto.ConfigureProvider_Request{
+		TerraformVersion: terraformVersion,
+		Config:           dynamicValue(config),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalDiagnostics(resp.GetDiagnostics()), nil
+}
+
+func (c client) UpgradeResourceState(ctx context.Context, typeName string, version int64,
+	rawStateJSON []byte) ([]byte, diagnostics.Diagnostics, error) {
+
+	resp, err := c.client.UpgradeResourceState(ctx, &proto.UpgradeResourceState_Request{
+		TypeName: typeName,
+		Version:  version,
+		RawState: &proto.RawState{Json: rawStateJSON},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.GetUpgradedState().GetMsgpack(), unmarshalDiagnostics(resp.GetDiagnostics()), nil
+}
+
+func (c client) ImportResourceState(ctx context.Context, typeName, id string) ([]tfplugin.ImportedResource, error) {
+	resp, err := c.client.ImportResourceState(ctx, &proto.ImportResourceState_Request{
+		TypeName: typeName,
+		Id:       id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imported := make([]tfplugin.ImportedResource, len(resp.GetImportedResources()))
+	for i, r := range resp.GetImportedResources() {
+		imported[i] = tfplugin.ImportedResource{
+			TypeName: r.GetTypeName(),
+			State:    r.GetState().GetMsgpack(),
+			Private:  r.GetPrivate(),
+		}
+	}
+	return imported, nil
+}
+
+func (c client) PlanResourceChange(ctx context.Context,
+	req *tfplugin.PlanResourceChangeRequest) (*tfplugin.PlanResourceChangeResponse, error) {
+
+	resp, err := c.client.PlanResourceChange(ctx, &proto.PlanResourceChange_Request{
+		TypeName:         req.TypeName,
+		PriorState:       dynamicValue(req.PriorState),
+		ProposedNewState: dynamicValue(req.ProposedNewState),
+		Config:           dynamicValue(req.Config),
+		PriorPrivate:     req.PriorPrivate,
+		ProviderMeta:     dynamicValue(req.ProviderMeta),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tfplugin.PlanResourceChangeResponse{
+		PlannedState:    resp.GetPlannedState().GetMsgpack(),
+		RequiresReplace: attributePaths(resp.GetRequiresReplace()),
+		PlannedPrivate:  resp.GetPlannedPrivate(),
+		Diagnostics:     unmarshalDiagnostics(resp.GetDiagnostics()),
+	}, nil
+}
+
+func (c client) ApplyResourceChange(ctx context.Context,
+	req *tfplugin.ApplyResourceChangeRequest) (*tfplugin.ApplyResourceChangeResponse, error) {
+
+	resp, err := c.client.ApplyResourceChange(ctx, &proto.ApplyResourceChange_Request{
+		TypeName:       req.TypeName,
+		PriorState:     dynamicValue(req.PriorState),
+		PlannedState:   dynamicValue(req.PlannedState),
+		Config:         dynamicValue(req.Config),
+		PlannedPrivate: req.PlannedPrivate,
+		ProviderMeta:   dynamicValue(req.ProviderMeta),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tfplugin.ApplyResourceChangeResponse{
+		NewState:    resp.GetNewState().GetMsgpack(),
+		Private:     resp.GetPrivate(),
+		Diagnostics: unmarshalDiagnostics(resp.GetDiagnostics()),
+	}, nil
+}
+
+func (c client) ReadResource(ctx context.Context,
+	req *tfplugin.ReadResourceRequest) (*tfplugin.ReadResourceResponse, error) {
+
+	resp, err := c.client.ReadResource(ctx, &proto.ReadResource_Request{
+		TypeName:     req.TypeName,
+		CurrentState: dynamicValue(req.CurrentState),
+		Private:      req.Private,
+		ProviderMeta: dynamicValue(req.ProviderMeta),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tfplugin.ReadResourceResponse{
+		NewState:    resp.GetNewState().GetMsgpack(),
+		Private:     resp.GetPrivate(),
+		Diagnostics: unmarshalDiagnostics(resp.GetDiagnostics()),
+	}, nil
+}
+
+func (c client) ReadDataSource(ctx context.Context,
+	req *tfplugin.ReadDataSourceRequest) (*tfplugin.ReadDataSourceResponse, error) {
+
+	resp, err := c.client.ReadDataSource(ctx, &proto.ReadDataSource_Request{
+		TypeName:     req.TypeName,
+		Config:       dynamicValue(req.Config),
+		ProviderMeta: dynamicValue(req.ProviderMeta),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tfplugin.ReadDataSourceResponse{
+		State:       resp.GetState().GetMsgpack(),
+		Diagnostics: unmarshalDiagnostics(resp.GetDiagnostics()),
+	}, nil
+}
+
+func (c client) StopProvider(ctx context.Context) error {
+	resp, err := c.client.StopProvider(ctx, &proto.StopProvider_Request{})
+	switch {
+	case err != nil:
+		return err
+	case resp.GetError() != "":
+		return fmt.Errorf("%s", resp.GetError())
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,50 @@
+package tfplugin6
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin6/proto"
+)
+
+// unmarshalDiagnostics converts a set of diagnostics from its wire format. Diagnostics with unknown severity will be
+// dropped.
+func unmarshalDiagnostics(diags []*proto.Diagnostic) diagnostics.Diagnostics {
+	var result diagnostics.Diagnostics
+	for _, d := range diags {
+		var severity diagnostics.Severity
+		switch d.Severity {
+		case proto.Diagnostic_ERROR:
+			severity = diagnostics.Error
+		case proto.Diagnostic_WARNING:
+			severity = diagnostics.Warning
+		default:
+			continue
+		}
+		result = append(result, diagnostics.Diagnostic{
+			Severity:      severity,
+			Summary:       d.Summary,
+			Detail:        d.Detail,
+			AttributePath: pathToCty(d.Attribute),
+		})
+	}
+	return result
+}
+
+func pathToCty(path *proto.AttributePath) cty.Path {
+	var p cty.Path
+	if path == nil {
+		return p
+	}
+	for _, s := range path.Steps {
+		switch s := s.Selector.(type) {
+		case *proto.AttributePath_Step_AttributeName:
+			p = p.GetAttr(s.AttributeName)
+		case *proto.AttributePath_Step_ElementKeyString:
+			p = p.IndexString(s.ElementKeyString)
+		case *proto.AttributePath_Step_ElementKeyInt:
+			p = p.Index(cty.NumberIntVal(s.ElementKeyInt))
+		}
+	}
+	return p
+}
@@ -0,0 +1,14 @@
+package tfplugin6
+
+import (
+	"context"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin6/proto"
+)
+
+// NewProvider returns a shim.Provider that drives a Terraform provider over plugin protocol version 6.
+func NewProvider(ctx context.Context, c proto.ProviderClient, terraformVersion string) (shim.Provider, error) {
+	return tfplugin.NewProvider(ctx, client{client: c}, terraformVersion)
+}
@@ -0,0 +1,108 @@
+package tfplugin6
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin6/proto"
+)
+
+func nestedAttribute(name string, nesting proto.Schema_Object_NestingMode, optional bool) *proto.Schema_Attribute {
+	return &proto.Schema_Attribute{
+		Name:     name,
+		Optional: optional,
+		Computed: !optional,
+		NestedType: &proto.Schema_Object{
+			Nesting: nesting,
+			Attributes: []*proto.Schema_Attribute{
+				{Name: "name", Type: []byte(`"string"`), Required: true},
+				{Name: "port", Type: []byte(`"number"`), Optional: true},
+			},
+		},
+	}
+}
+
+var nestedSchema = &proto.Schema{
+	Block: &proto.Schema_Block{
+		Attributes: []*proto.Schema_Attribute{
+			{Name: "id", Type: []byte(`"string"`), Computed: true},
+			nestedAttribute("single", proto.Schema_Object_SINGLE, true),
+			nestedAttribute("list", proto.Schema_Object_LIST, true),
+			nestedAttribute("set", proto.Schema_Object_SET, false),
+			nestedAttribute("map", proto.Schema_Object_MAP, true),
+		},
+	},
+}
+
+type testProviderClient struct {
+	proto.ProviderClient
+
+	dataSource cty.Value
+}
+
+func (c *testProviderClient) GetProviderSchema(ctx context.Context, in *proto.GetProviderSchema_Request,
+	opts ...grpc.CallOption) (*proto.GetProviderSchema_Response, error) {
+
+	return &proto.GetProviderSchema_Response{
+		Provider:          &proto.Schema{Block: &proto.Schema_Block{}},
+		ResourceSchemas:   map[string]*proto.Schema{"nested": nestedSchema},
+		DataSourceSchemas: map[string]*proto.Schema{"nested": nestedSchema},
+	}, nil
+}
+
+func (c *testProviderClient) ReadDataSource(ctx context.Context, in *proto.ReadDataSource_Request,
+	opts ...grpc.CallOption) (*proto.ReadDataSource_Response, error) {
+
+	ty, err := msgpack.ImpliedType(in.Config.Msgpack)
+	if err != nil {
+		return nil, err
+	}
+	config, err := msgpack.Unmarshal(in.Config.Msgpack, ty)
+	if err != nil {
+		return nil, err
+	}
+	c.dataSource = config
+
+	state, err := msgpack.Marshal(config, ty)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ReadDataSource_Response{State: &proto.DynamicValue{Msgpack: state}}, nil
+}
+
+func TestReadDataSourceWithNestedAttributes(t *testing.T) {
+	client := &testProviderClient{}
+	p, err := NewProvider(context.Background(), client, "")
+	require.NoError(t, err)
+
+	_, ok := p.ResourcesMap().GetOk("nested")
+	assert.True(t, ok)
+
+	config := map[string]interface{}{
+		"single": map[string]interface{}{"name": "a", "port": 80},
+		"list": []interface{}{
+			map[string]interface{}{"name": "b"},
+		},
+		"map": map[string]interface{}{
+			"key": map[string]interface{}{"name": "c", "port": 443},
+		},
+	}
+	diff, _, err := p.ReadDataDiff("nested", p.NewResourceConfig(config))
+	require.NoError(t, err)
+	state, _, err := p.ReadDataApply("nested", diff)
+	require.NoError(t, err)
+
+	object, err := state.Object(nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "a", "port": int64(80)}, object["single"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "b", "port": nil}}, object["list"])
+	assert.Equal(t, map[string]interface{}{
+		"key": map[string]interface{}{"name": "c", "port": int64(443)},
+	}, object["map"])
+}
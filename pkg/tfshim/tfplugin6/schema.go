@@ -0,0 +1,110 @@
+package tfplugin6
+
+import (
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/internal/tfplugin"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin6/proto"
+)
+
+// UnknownVariableValue is the sentinal defined in github.com/hashicorp/terraform/configs/hcl2shim,
+// representing a variable whose value is not known at some particular time.
+const UnknownVariableValue = tfplugin.UnknownVariableValue
+
+func unmarshalSchema(schema *proto.Schema) *tfplugin.Schema {
+	if schema == nil {
+		return nil
+	}
+	return &tfplugin.Schema{
+		Version: schema.GetVersion(),
+		Block:   unmarshalBlock(schema.GetBlock()),
+	}
+}
+
+func unmarshalSchemaMap(schemas map[string]*proto.Schema) map[string]*tfplugin.Schema {
+	result := make(map[string]*tfplugin.Schema, len(schemas))
+	for name, schema := range schemas {
+		result[name] = unmarshalSchema(schema)
+	}
+	return result
+}
+
+func unmarshalAttributes(attributes []*proto.Schema_Attribute) []*tfplugin.Attribute {
+	result := make([]*tfplugin.Attribute, len(attributes))
+	for i, attribute := range attributes {
+		result[i] = &tfplugin.Attribute{
+			Name:        attribute.GetName(),
+			Type:        attribute.GetType(),
+			NestedType:  unmarshalObject(attribute.GetNestedType()),
+			Description: attribute.GetDescription(),
+			Required:    attribute.GetRequired(),
+			Optional:    attribute.GetOptional(),
+			Computed:    attribute.GetComputed(),
+			Sensitive:   attribute.GetSensitive(),
+			Deprecated:  attribute.GetDeprecated(),
+		}
+	}
+	return result
+}
+
+func unmarshalObject(object *proto.Schema_Object) *tfplugin.Object {
+	if object == nil {
+		return nil
+	}
+	return &tfplugin.Object{
+		Attributes: unmarshalAttributes(object.GetAttributes()),
+		Nesting:    unmarshalObjectNestingMode(object.GetNesting()),
+		MinItems:   object.GetMinItems(),
+		MaxItems:   object.GetMaxItems(),
+	}
+}
+
+func unmarshalBlock(block *proto.Schema_Block) *tfplugin.Block {
+	blockTypes := make([]*tfplugin.NestedBlock, len(block.GetBlockTypes()))
+	for i, nestedBlock := range block.GetBlockTypes() {
+		blockTypes[i] = &tfplugin.NestedBlock{
+			TypeName: nestedBlock.GetTypeName(),
+			Block:    unmarshalBlock(nestedBlock.GetBlock()),
+			Nesting:  unmarshalNestingMode(nestedBlock.GetNesting()),
+			MinItems: nestedBlock.GetMinItems(),
+			MaxItems: nestedBlock.GetMaxItems(),
+		}
+	}
+
+	return &tfplugin.Block{
+		Attributes:  unmarshalAttributes(block.GetAttributes()),
+		BlockTypes:  blockTypes,
+		Description: block.GetDescription(),
+		Deprecated:  block.GetDeprecated(),
+	}
+}
+
+func unmarshalNestingMode(nesting proto.Schema_NestedBlock_NestingMode) tfplugin.NestingMode {
+	switch nesting {
+	case proto.Schema_NestedBlock_SINGLE:
+		return tfplugin.NestingSingle
+	case proto.Schema_NestedBlock_GROUP:
+		return tfplugin.NestingGroup
+	case proto.Schema_NestedBlock_LIST:
+		return tfplugin.NestingList
+	case proto.Schema_NestedBlock_SET:
+		return tfplugin.NestingSet
+	case proto.Schema_NestedBlock_MAP:
+		return tfplugin.NestingMap
+	default:
+		return tfplugin.NestingInvalid
+	}
+}
+
+func unmarshalObjectNestingMode(nesting proto.Schema_Object_NestingMode) tfplugin.NestingMode {
+	switch nesting {
+	case proto.Schema_Object_SINGLE:
+		return tfplugin.NestingSingle
+	case proto.Schema_Object_LIST:
+		return tfplugin.NestingList
+	case proto.Schema_Object_SET:
+		return tfplugin.NestingSet
+	case proto.Schema_Object_MAP:
+		return tfplugin.NestingMap
+	default:
+		return tfplugin.NestingInvalid
+	}
+}